@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/dto"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/usecase"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/auth"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/config"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/logging"
+	gormp "github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/gorm"
+)
+
+const apiKeysUsage = `usage: apikeys create --name NAME [--scope admin|read-write|read-only] [--expires-in 720h] [--tenant ID]
+       apikeys list [--tenant ID]
+       apikeys revoke [--tenant ID] <id>`
+
+// runAPIKeysCommand จัดการคำสั่งย่อย `apikeys ...` ตรงกับฐานข้อมูล (DB_DRIVER/DB_DSN เดียวกับเซิร์ฟเวอร์)
+// ใช้ออกคีย์ scope admin คีย์แรกเมื่อเปิด AUTH_API_KEYS โดยไม่มี JWT (ยังไม่มีใครมี apikeys:manage)
+// ผู้ที่เข้าถึงฐานข้อมูลได้ถือว่ามีสิทธิ์อยู่แล้ว จึงไม่ตรวจ permission (policy nil)
+func runAPIKeysCommand(arguments []string) error {
+	if len(arguments) == 0 {
+		return errors.New(apiKeysUsage)
+	}
+	flags := flag.NewFlagSet("apikeys "+arguments[0], flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprintln(flags.Output(), apiKeysUsage) }
+	tenantID := flags.String("tenant", requestmeta.DefaultTenant, "")
+	name := flags.String("name", "", "")
+	scope := flags.String("scope", domain.APIKeyScopeAdmin, "")
+	expiresIn := flags.String("expires-in", "", "")
+	if err := flags.Parse(arguments[1:]); errors.Is(err, flag.ErrHelp) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	logger, flush, err := logging.NewZapLogger(logging.ConfigFromEnv())
+	if err != nil {
+		return err
+	}
+	defer flush()
+	db, err := gormp.Open(logger)
+	if err != nil {
+		return err
+	}
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(gormp.NewAPIKeyRepositoryGorm(db),
+		auth.NewBcryptHasher(config.Int("AUTH_API_KEY_BCRYPT_COST", 10)), systemClock{}, logger, nil)
+	requestContext := requestmeta.WithPrincipal(
+		requestmeta.WithTenant(context.Background(), *tenantID), requestmeta.Principal{Subject: "cli"})
+
+	switch arguments[0] {
+	case "create":
+		if flags.NArg() > 0 {
+			return errors.New(apiKeysUsage)
+		}
+		issued, issueError := apiKeyUseCase.Issue(requestContext,
+			dto.IssueAPIKeyCommand{Name: *name, Scope: *scope, ExpiresIn: *expiresIn})
+		if errors.Is(issueError, domain.ErrBadInput) {
+			return errors.New("--name is required, --scope must be admin, read-write or read-only, --expires-in must be a positive duration")
+		}
+		if issueError != nil {
+			return issueError
+		}
+		// คีย์เต็มแสดงครั้งเดียว ไม่ได้เก็บไว้ที่ไหน
+		fmt.Printf("id=%d prefix=%s scope=%s tenant=%s\n%s\n", issued.ID, issued.Prefix, issued.Scope, *tenantID, issued.Key)
+		return nil
+	case "list":
+		if flags.NArg() > 0 {
+			return errors.New(apiKeysUsage)
+		}
+		keys, listError := apiKeyUseCase.List(requestContext)
+		if listError != nil {
+			return listError
+		}
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "ID\tNAME\tPREFIX\tSCOPE\tCREATED AT\tEXPIRES AT\tLAST USED\tREVOKED AT")
+		for _, key := range keys {
+			fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", key.ID, key.Name, key.Prefix, key.Scope,
+				key.CreatedAt, orDash(key.ExpiresAt), orDash(key.LastUsedAt), orDash(key.RevokedAt))
+		}
+		return writer.Flush()
+	case "revoke":
+		if flags.NArg() != 1 {
+			return errors.New(apiKeysUsage)
+		}
+		id, convertError := strconv.ParseUint(flags.Arg(0), 10, 64)
+		if convertError != nil {
+			return fmt.Errorf("invalid id %q", flags.Arg(0))
+		}
+		if revokeError := apiKeyUseCase.Revoke(requestContext, uint(id)); revokeError != nil {
+			return revokeError
+		}
+		fmt.Printf("revoked %d\n", id)
+		return nil
+	default:
+		return errors.New(apiKeysUsage)
+	}
+}
@@ -0,0 +1,75 @@
+// Package authorization = นโยบายสิทธิ์ (บทบาท → permission) ที่ use case ใช้ตัดสินก่อนทำงาน
+package authorization
+
+import (
+	"context"
+	"slices"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+// permission ที่ใช้ในระบบ
+const (
+	PermissionBooksRead       = "books:read"
+	PermissionBooksWrite      = "books:write"  // สร้าง/แก้ไข
+	PermissionBooksDelete     = "books:delete" // soft delete
+	PermissionBooksPurge      = "books:purge"  // ลบถาวร
+	PermissionAPIKeysManage   = "apikeys:manage"
+	PermissionLogsManage      = "logs:manage"      // ปรับระดับ log ขณะรัน
+	PermissionDiagnosticsRead = "diagnostics:read" // ดูสถิติภายใน (connection pool, แคช)
+
+	// AllPermissions ใช้ในนโยบายแทน "ทุก permission"
+	AllPermissions = "*"
+)
+
+// Policy = ตารางบทบาท → permission
+// principal ได้ permission จากทุกบทบาทที่มี รวมกับ scope ของ credential (เช่น API key)
+type Policy struct {
+	rolePermissions map[string][]string
+}
+
+// NewPolicy สร้างนโยบายจากตาราง role → permissions
+func NewPolicy(rolePermissions map[string][]string) *Policy {
+	copied := make(map[string][]string, len(rolePermissions))
+	for role, permissions := range rolePermissions {
+		copied[role] = slices.Clone(permissions)
+	}
+	return &Policy{rolePermissions: copied}
+}
+
+// DefaultPolicy = viewer อ่านได้, editor อ่าน/เขียน/ลบได้, admin ทำได้ทุกอย่าง
+func DefaultPolicy() *Policy {
+	return NewPolicy(map[string][]string{
+		"viewer": {PermissionBooksRead},
+		"editor": {PermissionBooksRead, PermissionBooksWrite, PermissionBooksDelete},
+		"admin":  {AllPermissions},
+	})
+}
+
+// Allows = principal มี permission นี้หรือไม่
+func (policy *Policy) Allows(principal requestmeta.Principal, permission string) bool {
+	if slices.Contains(principal.Scopes, permission) {
+		return true
+	}
+	for _, role := range principal.Roles {
+		granted := policy.rolePermissions[role]
+		if slices.Contains(granted, permission) || slices.Contains(granted, AllPermissions) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize คืน domain.ErrForbidden ถ้าผู้เรียกใน context ไม่มี permission
+// policy เป็น nil = ปิดการตรวจสิทธิ์ (ระบบไม่ได้เปิดการยืนยันตัวตน) ทุกคำขอผ่าน
+func (policy *Policy) Authorize(requestContext context.Context, permission string) error {
+	if policy == nil {
+		return nil
+	}
+	principal, ok := requestmeta.PrincipalFrom(requestContext)
+	if !ok || !policy.Allows(principal, permission) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
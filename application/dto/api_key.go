@@ -0,0 +1,27 @@
+package dto
+
+// ชุด DTO ของการจัดการ API key
+
+type IssueAPIKeyCommand struct {
+	Name      string
+	Scope     string // domain.APIKeyScopeReadOnly, domain.APIKeyScopeReadWrite หรือ domain.APIKeyScopeAdmin
+	ExpiresIn string // เช่น "720h"; ว่าง = ไม่หมดอายุ
+}
+
+type APIKeyReadModel struct {
+	ID         uint
+	Name       string
+	Prefix     string
+	Scope      string
+	CreatedBy  string
+	CreatedAt  string
+	ExpiresAt  string // ว่าง = ไม่หมดอายุ
+	LastUsedAt string // ว่าง = ยังไม่เคยใช้
+	RevokedAt  string // ว่าง = ยังใช้งานอยู่
+}
+
+// IssuedAPIKey = ผลของการออกคีย์ Key คือค่าเต็มที่แสดงได้ครั้งเดียว (ระบบไม่ได้เก็บไว้)
+type IssuedAPIKey struct {
+	APIKeyReadModel
+	Key string
+}
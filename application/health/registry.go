@@ -0,0 +1,192 @@
+// Package health = ทะเบียน health check ของ dependency (DB, Redis ฯลฯ) ที่ /readyz และ /admin/health/details เรียกใช้
+// อแดปเตอร์ไม่ต้องรู้จัก HTTP แค่มีฟังก์ชันตรวจ (ctx) error แล้วให้ main ลงทะเบียนไว้
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Status ตามรูปแบบ application/health+json (pass/warn/fail)
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Check ตรวจ dependency หนึ่งตัว คืน nil = ปกติ
+type Check func(requestContext context.Context) error
+
+// Pinger = อแดปเตอร์ที่ ping ปลายทางของตัวเองได้ (เช่น RedisStore) ลงทะเบียนด้วย pinger.Ping ได้เลย
+type Pinger interface {
+	Ping(requestContext context.Context) error
+}
+
+// CheckOption ปรับการลงทะเบียน check
+type CheckOption func(check *registeredCheck)
+
+// Critical = check นี้ล้มเหลวแล้วไม่พร้อมรับ request (/readyz ตอบ 503)
+// ไม่ใส่ = ล้มเหลวแค่ลดสถานะรวมเป็น warn (เช่น แคช/replica ที่มีทางสำรอง)
+func Critical() CheckOption {
+	return func(check *registeredCheck) { check.critical = true }
+}
+
+// WithComponentType ระบุชนิดของ dependency ในรายงาน (เช่น datastore, component)
+func WithComponentType(componentType string) CheckOption {
+	return func(check *registeredCheck) { check.componentType = componentType }
+}
+
+// WithTimeout ใช้ timeout เฉพาะ check นี้แทนค่าเริ่มต้นของ registry
+func WithTimeout(timeout time.Duration) CheckOption {
+	return func(check *registeredCheck) { check.timeout = timeout }
+}
+
+type registeredCheck struct {
+	name          string
+	check         Check
+	critical      bool
+	componentType string
+	timeout       time.Duration
+}
+
+// CheckResult = ผลของ check หนึ่งตัว (observedValue = เวลาที่ใช้ตรวจ หน่วย ms)
+type CheckResult struct {
+	ComponentType string  `json:"componentType,omitempty"`
+	Status        Status  `json:"status"`
+	ObservedValue float64 `json:"observedValue"`
+	ObservedUnit  string  `json:"observedUnit"`
+	Time          string  `json:"time"`
+	Output        string  `json:"output,omitempty"`
+}
+
+// Report = รายงานรวม ตามรูปแบบ application/health+json (checks: ชื่อ → รายการผล)
+type Report struct {
+	Status Status                   `json:"status"`
+	Output string                   `json:"output,omitempty"`
+	Checks map[string][]CheckResult `json:"checks,omitempty"`
+}
+
+// Registry เก็บ check ที่ลงทะเบียนไว้ ปลอดภัยต่อการใช้หลาย goroutine
+type Registry struct {
+	mutex          sync.RWMutex
+	checks         []registeredCheck
+	defaultTimeout time.Duration
+}
+
+// NewRegistry สร้าง registry ว่าง defaultTimeout = เวลาสูงสุดต่อ check (ค้างเกินนี้ถือว่า fail)
+func NewRegistry(defaultTimeout time.Duration) *Registry {
+	return &Registry{defaultTimeout: defaultTimeout}
+}
+
+// Register ลงทะเบียน check ชื่อ name (ชื่อซ้ำ = แทนที่ของเดิม)
+func (registry *Registry) Register(name string, check Check, options ...CheckOption) {
+	registered := registeredCheck{name: name, check: check, timeout: registry.defaultTimeout}
+	for _, option := range options {
+		option(&registered)
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	for index := range registry.checks {
+		if registry.checks[index].name == name {
+			registry.checks[index] = registered
+			return
+		}
+	}
+	registry.checks = append(registry.checks, registered)
+}
+
+// Readiness รันเฉพาะ check ที่เป็น Critical (ใช้กับ /readyz)
+func (registry *Registry) Readiness(requestContext context.Context) Report {
+	return registry.run(requestContext, true)
+}
+
+// Details รันทุก check (ใช้กับ /admin/health/details)
+func (registry *Registry) Details(requestContext context.Context) Report {
+	return registry.run(requestContext, false)
+}
+
+// run รันทุก check พร้อมกัน สถานะรวม: critical ล้ม = fail, ตัวอื่นล้ม = warn, ไม่งั้น pass
+func (registry *Registry) run(requestContext context.Context, criticalOnly bool) Report {
+	registry.mutex.RLock()
+	var selected []registeredCheck
+	for _, check := range registry.checks {
+		if !criticalOnly || check.critical {
+			selected = append(selected, check)
+		}
+	}
+	registry.mutex.RUnlock()
+
+	results := make([]CheckResult, len(selected))
+	var waitGroup sync.WaitGroup
+	for index, check := range selected {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			results[index] = runCheck(requestContext, check)
+		}()
+	}
+	waitGroup.Wait()
+
+	report := Report{Status: StatusPass, Checks: make(map[string][]CheckResult, len(selected))}
+	for index, check := range selected {
+		result := results[index]
+		report.Checks[check.name] = []CheckResult{result}
+		if result.Status != StatusFail {
+			continue
+		}
+		if check.critical {
+			report.Status = StatusFail
+		} else if report.Status == StatusPass {
+			report.Status = StatusWarn
+		}
+	}
+	return report
+}
+
+func runCheck(requestContext context.Context, check registeredCheck) CheckResult {
+	checkContext := requestContext
+	if check.timeout > 0 {
+		var cancel context.CancelFunc
+		checkContext, cancel = context.WithTimeout(requestContext, check.timeout)
+		defer cancel()
+	}
+
+	started := time.Now()
+	err := callCheck(checkContext, check.check)
+	result := CheckResult{
+		ComponentType: check.componentType,
+		Status:        StatusPass,
+		ObservedValue: float64(time.Since(started).Microseconds()) / 1000,
+		ObservedUnit:  "ms",
+		Time:          started.UTC().Format(time.RFC3339),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Output = err.Error()
+	}
+	return result
+}
+
+// callCheck ไม่รอ check ที่ไม่เคารพ ctx เกิน deadline และกัน panic ไม่ให้ล้มทั้งโปรเซส
+func callCheck(checkContext context.Context, check Check) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- errors.New("check panicked")
+			}
+		}()
+		done <- check(checkContext)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-checkContext.Done():
+		return checkContext.Err()
+	}
+}
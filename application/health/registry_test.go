@@ -0,0 +1,114 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/health"
+)
+
+func passing(context.Context) error { return nil }
+
+func failing(context.Context) error { return errors.New("connection refused") }
+
+func TestRegistryStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		register      func(registry *health.Registry)
+		wantReadiness health.Status
+		wantDetails   health.Status
+	}{
+		{
+			name: "all pass",
+			register: func(registry *health.Registry) {
+				registry.Register("database", passing, health.Critical())
+				registry.Register("cache", passing)
+			},
+			wantReadiness: health.StatusPass,
+			wantDetails:   health.StatusPass,
+		},
+		{
+			name: "non-critical failure only warns",
+			register: func(registry *health.Registry) {
+				registry.Register("database", passing, health.Critical())
+				registry.Register("cache", failing)
+			},
+			wantReadiness: health.StatusPass,
+			wantDetails:   health.StatusWarn,
+		},
+		{
+			name: "critical failure fails",
+			register: func(registry *health.Registry) {
+				registry.Register("database", failing, health.Critical())
+				registry.Register("cache", failing)
+			},
+			wantReadiness: health.StatusFail,
+			wantDetails:   health.StatusFail,
+		},
+		{
+			name:          "empty registry passes",
+			register:      func(*health.Registry) {},
+			wantReadiness: health.StatusPass,
+			wantDetails:   health.StatusPass,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			registry := health.NewRegistry(time.Second)
+			test.register(registry)
+
+			if got := registry.Readiness(context.Background()).Status; got != test.wantReadiness {
+				t.Fatalf("readiness = %s, want %s", got, test.wantReadiness)
+			}
+			if got := registry.Details(context.Background()).Status; got != test.wantDetails {
+				t.Fatalf("details = %s, want %s", got, test.wantDetails)
+			}
+		})
+	}
+}
+
+func TestRegistryReportsOutputAndLatency(t *testing.T) {
+	registry := health.NewRegistry(time.Second)
+	registry.Register("database", failing, health.Critical(), health.WithComponentType("datastore"))
+
+	report := registry.Details(context.Background())
+	results := report.Checks["database"]
+	if len(results) != 1 {
+		t.Fatalf("checks[database] = %v, want one result", report.Checks)
+	}
+	result := results[0]
+	if result.Status != health.StatusFail || result.Output != "connection refused" || result.ComponentType != "datastore" {
+		t.Fatalf("result = %+v", result)
+	}
+	if result.ObservedUnit != "ms" || result.Time == "" {
+		t.Fatalf("result missing latency fields: %+v", result)
+	}
+}
+
+func TestRegistryTimesOutHangingCheck(t *testing.T) {
+	registry := health.NewRegistry(time.Second)
+	hanging := func(context.Context) error { select {} }
+	registry.Register("replica", hanging, health.Critical(), health.WithTimeout(20*time.Millisecond))
+
+	started := time.Now()
+	report := registry.Readiness(context.Background())
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Fatalf("readiness took %s, want it bounded by the check timeout", elapsed)
+	}
+	if report.Status != health.StatusFail {
+		t.Fatalf("status = %s, want fail", report.Status)
+	}
+}
+
+func TestRegistryRegisterReplacesSameName(t *testing.T) {
+	registry := health.NewRegistry(time.Second)
+	registry.Register("database", failing, health.Critical())
+	registry.Register("database", passing, health.Critical())
+
+	report := registry.Details(context.Background())
+	if report.Status != health.StatusPass || len(report.Checks) != 1 {
+		t.Fatalf("report = %+v, want a single passing check", report)
+	}
+}
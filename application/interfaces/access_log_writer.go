@@ -0,0 +1,12 @@
+package interfaces
+
+import "time"
+
+// AccessLogWriter = ปลายทางของ access log (เช่น ไฟล์ที่หมุนตามเวลา/ขนาด)
+// ต้องปลอดภัยต่อการเรียกจากหลาย goroutine
+type AccessLogWriter interface {
+	// WriteLine เขียนหนึ่งบรรทัด (ยังไม่มี \n) ที่เกิดขึ้นเวลา now
+	WriteLine(now time.Time, line string) error
+	// Close flush และปิดปลายทาง (เรียกตอนปิดโปรแกรม หลัง server หยุดรับ request แล้ว)
+	Close() error
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+// APIKeyRepository คือพอร์ตเก็บ API key (เก็บแค่ hash ของ secret)
+// List/Revoke เห็นเฉพาะคีย์ของ tenant ใน context; GetByPrefix ค้นทุก tenant (ใช้ตอนยืนยันตัวตน)
+type APIKeyRepository interface {
+	Create(requestContext context.Context, key *domain.APIKey) error
+	// GetByPrefix คืน domain.ErrNotFound ถ้าไม่มี prefix นี้
+	GetByPrefix(requestContext context.Context, prefix string) (domain.APIKey, error)
+	List(requestContext context.Context) ([]domain.APIKey, error)
+	// Revoke คืน domain.ErrNotFound ถ้าไม่มี id นี้ (เพิกถอนซ้ำไม่ถือเป็น error)
+	Revoke(requestContext context.Context, id uint, revokedAt time.Time) error
+	TouchLastUsed(requestContext context.Context, id uint, usedAt time.Time) error
+}
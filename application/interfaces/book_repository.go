@@ -1,16 +1,19 @@
 package interfaces
 
 import (
+	"context"
+
 	"github.com/nuba55yo/go-101-CleanCRUD/domain"
 )
 
 // BookRepository คือพอร์ตออกจาก use case ไปยังเลเยอร์ persistence
 // เลเยอร์ infrastructure ต้อง implement อินเทอร์เฟซนี้ (เช่น GORM repository)
+// requestContext ใช้ยกเลิกงานตาม request และส่งข้อมูลประจำ request (ดู package requestmeta)
 type BookRepository interface {
-	List() ([]domain.Book, error)
-	GetByID(id uint) (domain.Book, error)
-	ExistsActiveByTitle(title string, excludeID *uint) (bool, error)
-	Create(book *domain.Book) error
-	Update(book *domain.Book) error
-	SoftDelete(id uint) error
+	List(requestContext context.Context) ([]domain.Book, error)
+	GetByID(requestContext context.Context, id uint) (domain.Book, error)
+	ExistsActiveByTitle(requestContext context.Context, title string, excludeID *uint) (bool, error)
+	Create(requestContext context.Context, book *domain.Book) error
+	Update(requestContext context.Context, book *domain.Book) error
+	SoftDelete(requestContext context.Context, id uint) error
 }
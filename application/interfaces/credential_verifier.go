@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+)
+
+// CredentialVerifier ตรวจ credential ที่มากับ request (เช่น JWT) แล้วคืนตัวตนของผู้เรียก
+// เลเยอร์ infrastructure ทำตัวจริง; presentation เรียกผ่านพอร์ตนี้
+type CredentialVerifier interface {
+	Verify(requestContext context.Context, credential string) (requestmeta.Principal, error)
+}
@@ -0,0 +1,31 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotentResponse = คำตอบที่บันทึกไว้เพื่อส่งซ้ำเมื่อ client retry ด้วย Idempotency-Key เดิม
+type IdempotentResponse struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// IdempotencyRecord = สถานะของ Idempotency-Key หนึ่งตัว
+type IdempotencyRecord struct {
+	Fingerprint string // hash ของ method + path + body ของ request แรก
+	Completed   bool   // false = request แรกยังทำงานอยู่
+	Response    IdempotentResponse
+}
+
+// IdempotencyStore เก็บ Idempotency-Key (ในโปรเซส หรือใช้ร่วมกันหลาย instance เช่น Redis)
+type IdempotencyStore interface {
+	// Reserve จองคีย์แบบ atomic: คืน reserved=true ถ้าเป็นคนแรก (จองไว้ lockTTL)
+	// ไม่งั้นคืน record ที่มีอยู่แล้ว
+	Reserve(requestContext context.Context, key, fingerprint string, lockTTL time.Duration) (record IdempotencyRecord, reserved bool, err error)
+	// Complete บันทึกคำตอบ เก็บไว้ ttl
+	Complete(requestContext context.Context, key string, record IdempotencyRecord, ttl time.Duration) error
+	// Release ยกเลิกการจอง (request แรกล้มเหลว ให้ retry ทำงานใหม่ได้)
+	Release(requestContext context.Context, key string) error
+}
@@ -4,8 +4,19 @@ import "context"
 
 // Logger คือสัญญา logging ที่ไม่ผูกกับ framework
 // เลเยอร์ infrastructure จะทำตัวจริง (เช่น zap) มา implement อันนี้
+// อแดปเตอร์ดึงข้อมูลจาก requestContext เอง (request id, ผู้เรียก, tenant, trace id) ไม่ต้องส่งเป็น keyValues
 type Logger interface {
+	Debug(requestContext context.Context, message string, keyValues ...any)
 	Info(requestContext context.Context, message string, keyValues ...any)
 	Warn(requestContext context.Context, message string, keyValues ...any)
 	Error(requestContext context.Context, message string, keyValues ...any)
+	// With คืน logger ที่ติด keyValues ไว้กับทุกบรรทัด (เช่น component, operation)
+	With(keyValues ...any) Logger
+}
+
+// LogLevelController ปรับระดับ log ขณะรันได้ (debug, info, warn, error)
+type LogLevelController interface {
+	Level() string
+	// SetLevel คืน error เมื่อไม่รู้จักชื่อระดับ
+	SetLevel(level string) error
 }
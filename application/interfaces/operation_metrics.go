@@ -0,0 +1,9 @@
+package interfaces
+
+import "time"
+
+// OperationMetrics คือสัญญาเก็บสถิติการทำงานของ use case ไม่ผูกกับระบบ metrics ตัวใด
+// errorType ว่าง = สำเร็จ, ไม่ว่าง = ชนิดของ error (เช่น not_found, forbidden)
+type OperationMetrics interface {
+	ObserveOperation(operation string, duration time.Duration, errorType string)
+}
@@ -0,0 +1,34 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimit = token bucket: เติม Requests โทเคนต่อ Per และจุได้มากสุด Burst (0 = เท่ากับ Requests)
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+// Capacity = จำนวนโทเคนสูงสุดในถัง
+func (limit RateLimit) Capacity() int {
+	if limit.Burst > 0 {
+		return limit.Burst
+	}
+	return limit.Requests
+}
+
+// RateLimitDecision = ผลของการขอโทเคน 1 อัน
+type RateLimitDecision struct {
+	Allowed    bool
+	Remaining  int           // โทเคนที่เหลือหลังจากครั้งนี้
+	RetryAfter time.Duration // ถูกปฏิเสธ: ต้องรออีกเท่าไรจึงจะมีโทเคน
+	ResetAfter time.Duration // อีกเท่าไรถังจะเต็ม
+}
+
+// RateLimitStore เก็บสถานะ token bucket ต่อ key (ในโปรเซส หรือใช้ร่วมกันหลาย instance เช่น Redis)
+type RateLimitStore interface {
+	Take(requestContext context.Context, key string, limit RateLimit) (RateLimitDecision, error)
+}
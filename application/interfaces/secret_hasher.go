@@ -0,0 +1,8 @@
+package interfaces
+
+// SecretHasher แฮช secret แบบช้าโดยตั้งใจ (bcrypt/argon2) และตรวจเทียบ
+// ใช้กับ secret ที่ต้องเก็บถาวร เช่น API key
+type SecretHasher interface {
+	Hash(secret string) (string, error)
+	Matches(hash, secret string) bool
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import "context"
+
+// Tracer คือสัญญาสร้าง span ของ distributed tracing ไม่ผูกกับ OpenTelemetry
+// เลเยอร์ infrastructure จะทำตัวจริงมา implement อันนี้
+type Tracer interface {
+	// Start เปิด span ลูกของ span ที่อยู่ใน requestContext แล้วคืน context ที่มี span ใหม่
+	Start(requestContext context.Context, name string, keyValues ...any) (context.Context, Span)
+}
+
+// Span = ช่วงการทำงานหนึ่งช่วง
+type Span interface {
+	SetAttributes(keyValues ...any)
+	// End ปิด span; err != nil = บันทึกว่าล้มเหลว
+	End(err error)
+}
@@ -0,0 +1,37 @@
+package requestmeta
+
+import (
+	"context"
+	"slices"
+)
+
+// Principal = ตัวตนของผู้เรียกที่ยืนยันแล้ว (จาก JWT, API key ฯลฯ)
+type Principal struct {
+	Subject    string         // เช่น user id หรือ id ของ API key
+	AuthMethod string         // วิธียืนยันตัวตน เช่น "jwt"
+	Roles      []string       // บทบาท (ใช้ตอนตรวจสิทธิ์)
+	Scopes     []string       // ขอบเขตที่ credential อนุญาต
+	TenantID   string         // tenant ที่ credential ผูกอยู่ ("" = ไม่ผูก)
+	Claims     map[string]any // claim ดิบ (เผื่อใช้ต่อ)
+}
+
+// HasRole = principal มีบทบาทนี้หรือไม่
+func (principal Principal) HasRole(role string) bool {
+	return slices.Contains(principal.Roles, role)
+}
+
+// HasScope = principal มี scope นี้หรือไม่
+func (principal Principal) HasScope(scope string) bool {
+	return slices.Contains(principal.Scopes, scope)
+}
+
+// WithPrincipal ผูก principal ไว้กับ context (middleware ยืนยันตัวตนเป็นคนใส่)
+func WithPrincipal(requestContext context.Context, principal Principal) context.Context {
+	return context.WithValue(requestContext, principalContextKey, principal)
+}
+
+// PrincipalFrom คืน principal ที่ผูกไว้ (ok=false ถ้า request ไม่ได้ยืนยันตัวตน)
+func PrincipalFrom(requestContext context.Context) (Principal, bool) {
+	principal, ok := requestContext.Value(principalContextKey).(Principal)
+	return principal, ok
+}
@@ -0,0 +1,14 @@
+package requestmeta
+
+import "context"
+
+// WithRequestID ผูก request id (correlation id) ไว้กับ context ให้ทุก log ของ request เดียวกันโยงกันได้
+func WithRequestID(requestContext context.Context, requestID string) context.Context {
+	return context.WithValue(requestContext, requestIDContextKey, requestID)
+}
+
+// RequestID คืน request id ที่ผูกไว้ หรือ "" ถ้าไม่มี (เช่น งานเบื้องหลังที่ไม่ได้มาจาก HTTP)
+func RequestID(requestContext context.Context) string {
+	requestID, _ := requestContext.Value(requestIDContextKey).(string)
+	return requestID
+}
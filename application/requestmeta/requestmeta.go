@@ -0,0 +1,39 @@
+// Package requestmeta เก็บข้อมูลประจำ request ที่ต้องส่งผ่าน context ข้ามเลเยอร์
+// (presentation ใส่เข้ามา, use case/infrastructure อ่านออกไป) โดยไม่ผูกกับ HTTP framework
+package requestmeta
+
+import "context"
+
+type contextKey int
+
+const (
+	clientKeyContextKey contextKey = iota
+	strongReadContextKey
+	principalContextKey
+	tenantContextKey
+	requestIDContextKey
+)
+
+// WithClientKey ผูก "ตัวตนของ client" (เช่น X-Client-ID หรือ IP) ไว้กับ context
+// ใช้ทำ read-your-writes: client ที่เพิ่งเขียนจะอ่านจาก primary ชั่วคราว
+func WithClientKey(requestContext context.Context, clientKey string) context.Context {
+	return context.WithValue(requestContext, clientKeyContextKey, clientKey)
+}
+
+// ClientKey คืน client key ที่ผูกไว้ ("" ถ้าไม่มี)
+func ClientKey(requestContext context.Context) string {
+	clientKey, _ := requestContext.Value(clientKeyContextKey).(string)
+	return clientKey
+}
+
+// WithStrongRead บอก persistence ว่าการอ่านใน context นี้ต้องเห็นข้อมูลล่าสุด (อ่านจาก primary)
+// use case ใช้ครอบการอ่านที่อยู่ใน write path เช่น โหลดของเดิมก่อน update
+func WithStrongRead(requestContext context.Context) context.Context {
+	return context.WithValue(requestContext, strongReadContextKey, true)
+}
+
+// StrongReadRequested = true ถ้ามีการขอ WithStrongRead ไว้
+func StrongReadRequested(requestContext context.Context) bool {
+	requested, _ := requestContext.Value(strongReadContextKey).(bool)
+	return requested
+}
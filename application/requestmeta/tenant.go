@@ -0,0 +1,26 @@
+package requestmeta
+
+import "context"
+
+// DefaultTenant = tenant ของ request ที่ไม่ได้ระบุ (และของข้อมูลก่อนเปิด multi-tenancy)
+const DefaultTenant = "default"
+
+// WithTenant ผูก tenant (องค์กร/ห้องสมุด) ของ request ไว้กับ context
+// persistence ใช้ค่านี้กรองทุก query
+func WithTenant(requestContext context.Context, tenantID string) context.Context {
+	return context.WithValue(requestContext, tenantContextKey, tenantID)
+}
+
+// TenantID คืน tenant ที่ผูกไว้ หรือ DefaultTenant ถ้าไม่มี
+func TenantID(requestContext context.Context) string {
+	if tenantID, _ := requestContext.Value(tenantContextKey).(string); tenantID != "" {
+		return tenantID
+	}
+	return DefaultTenant
+}
+
+// TenantFrom คืน tenant ที่ผูกไว้จริง (ok = false ถ้า request ไม่ได้ระบุ tenant) ใช้กับ log ที่ไม่อยากเติมค่าเริ่มต้น
+func TenantFrom(requestContext context.Context) (string, bool) {
+	tenantID, _ := requestContext.Value(tenantContextKey).(string)
+	return tenantID, tenantID != ""
+}
@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/authorization"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/dto"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+// รูปแบบคีย์: bk_<prefix 8 ตัว>_<secret> ; prefix ใช้ค้นหา, secret ถูกเก็บเป็น hash
+const (
+	apiKeyMarker       = "bk_"
+	apiKeyPrefixBytes  = 4
+	apiKeySecretBytes  = 32
+	lastUsedResolution = time.Minute // อัปเดต last_used_at อย่างมากนาทีละครั้งต่อคีย์
+)
+
+// ErrInvalidAPIKey = คีย์ผิดรูปแบบ ไม่มีในระบบ secret ไม่ตรง ถูกเพิกถอน หรือหมดอายุ
+// (ไม่แยกบอกสาเหตุให้ผู้เรียกรู้)
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// APIKeyUseCase = พอร์ตเข้าของการจัดการ API key และเป็น interfaces.CredentialVerifier ด้วย
+type APIKeyUseCase interface {
+	Issue(requestContext context.Context, command dto.IssueAPIKeyCommand) (dto.IssuedAPIKey, error)
+	List(requestContext context.Context) ([]dto.APIKeyReadModel, error)
+	Revoke(requestContext context.Context, id uint) error
+	Verify(requestContext context.Context, credential string) (requestmeta.Principal, error)
+}
+
+type apiKeyUseCase struct {
+	apiKeyRepository interfaces.APIKeyRepository
+	secretHasher     interfaces.SecretHasher
+	clock            interfaces.Clock
+	logger           interfaces.Logger
+	policy           *authorization.Policy
+
+	// verifiedSecrets จำ digest ของ secret ที่เคยตรวจผ่านแล้ว (ต่อ prefix)
+	// เพื่อไม่ต้องเสีย bcrypt ทุก request; สถานะเพิกถอน/หมดอายุยังอ่านจาก repository ทุกครั้ง
+	verifiedMutex   sync.Mutex
+	verifiedSecrets map[string]verifiedSecret
+}
+
+type verifiedSecret struct {
+	secretHash string
+	digest     [sha256.Size]byte
+}
+
+// NewAPIKeyUseCase ประกอบ dependencies ให้พร้อมใช้
+// การออก/ดู/เพิกถอนคีย์ต้องมี permission apikeys:manage ตาม policy
+func NewAPIKeyUseCase(
+	apiKeyRepository interfaces.APIKeyRepository,
+	secretHasher interfaces.SecretHasher,
+	clock interfaces.Clock,
+	logger interfaces.Logger,
+	policy *authorization.Policy,
+) APIKeyUseCase {
+	return &apiKeyUseCase{
+		apiKeyRepository: apiKeyRepository,
+		secretHasher:     secretHasher,
+		clock:            clock,
+		logger:           logger,
+		policy:           policy,
+		verifiedSecrets:  make(map[string]verifiedSecret),
+	}
+}
+
+// Issue: ตรวจ input, สุ่ม prefix/secret, เก็บ hash แล้วคืนคีย์เต็ม (ครั้งเดียว)
+func (useCase *apiKeyUseCase) Issue(
+	requestContext context.Context,
+	command dto.IssueAPIKeyCommand,
+) (dto.IssuedAPIKey, error) {
+
+	if authorizeError := useCase.policy.Authorize(requestContext, authorization.PermissionAPIKeysManage); authorizeError != nil {
+		return dto.IssuedAPIKey{}, authorizeError
+	}
+
+	name := strings.TrimSpace(command.Name)
+	if name == "" || !domain.ValidAPIKeyScope(command.Scope) {
+		return dto.IssuedAPIKey{}, domain.ErrBadInput
+	}
+
+	now := useCase.clock.Now()
+	var expiresAt *time.Time
+	if command.ExpiresIn != "" {
+		lifetime, parseError := time.ParseDuration(command.ExpiresIn)
+		if parseError != nil || lifetime <= 0 {
+			return dto.IssuedAPIKey{}, domain.ErrBadInput
+		}
+		expiry := now.Add(lifetime)
+		expiresAt = &expiry
+	}
+
+	prefix, secret, randomError := generateAPIKeyParts()
+	if randomError != nil {
+		return dto.IssuedAPIKey{}, randomError
+	}
+	secretHash, hashError := useCase.secretHasher.Hash(secret)
+	if hashError != nil {
+		return dto.IssuedAPIKey{}, hashError
+	}
+
+	var createdBy string
+	if principal, ok := requestmeta.PrincipalFrom(requestContext); ok {
+		createdBy = principal.Subject
+	}
+	entity := domain.APIKey{
+		TenantID:   requestmeta.TenantID(requestContext),
+		Name:       name,
+		Prefix:     prefix,
+		SecretHash: secretHash,
+		Scope:      command.Scope,
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+		ExpiresAt:  expiresAt,
+	}
+	if createError := useCase.apiKeyRepository.Create(requestContext, &entity); createError != nil {
+		return dto.IssuedAPIKey{}, createError
+	}
+
+	useCase.logger.Info(requestContext, "api key issued",
+		"id", entity.ID, "prefix", entity.Prefix, "scope", entity.Scope)
+
+	return dto.IssuedAPIKey{
+		APIKeyReadModel: toAPIKeyReadModel(entity),
+		Key:             apiKeyMarker + prefix + "_" + secret,
+	}, nil
+}
+
+// List: คีย์ทั้งหมด (รวมที่ถูกเพิกถอน) โดยไม่มี secret
+func (useCase *apiKeyUseCase) List(requestContext context.Context) ([]dto.APIKeyReadModel, error) {
+	if authorizeError := useCase.policy.Authorize(requestContext, authorization.PermissionAPIKeysManage); authorizeError != nil {
+		return nil, authorizeError
+	}
+	entities, listError := useCase.apiKeyRepository.List(requestContext)
+	if listError != nil {
+		return nil, listError
+	}
+	readModels := make([]dto.APIKeyReadModel, 0, len(entities))
+	for _, entity := range entities {
+		readModels = append(readModels, toAPIKeyReadModel(entity))
+	}
+	return readModels, nil
+}
+
+// Revoke: มีผลทันทีกับ request ถัดไป เพราะ Verify อ่านสถานะจาก repository ทุกครั้ง
+func (useCase *apiKeyUseCase) Revoke(requestContext context.Context, id uint) error {
+	if authorizeError := useCase.policy.Authorize(requestContext, authorization.PermissionAPIKeysManage); authorizeError != nil {
+		return authorizeError
+	}
+	if revokeError := useCase.apiKeyRepository.Revoke(requestContext, id, useCase.clock.Now()); revokeError != nil {
+		return revokeError
+	}
+	useCase.logger.Info(requestContext, "api key revoked", "id", id)
+	return nil
+}
+
+// Verify: แยก prefix/secret, โหลดคีย์, เช็คสถานะ, เทียบ hash แล้วคืน principal
+func (useCase *apiKeyUseCase) Verify(
+	requestContext context.Context,
+	credential string,
+) (requestmeta.Principal, error) {
+
+	prefix, secret, ok := parseAPIKey(credential)
+	if !ok {
+		return requestmeta.Principal{}, ErrInvalidAPIKey
+	}
+	entity, getError := useCase.apiKeyRepository.GetByPrefix(requestContext, prefix)
+	if errors.Is(getError, domain.ErrNotFound) {
+		return requestmeta.Principal{}, ErrInvalidAPIKey
+	}
+	if getError != nil {
+		return requestmeta.Principal{}, getError
+	}
+
+	now := useCase.clock.Now()
+	if !entity.IsUsableAt(now) || !useCase.secretMatches(entity, secret) {
+		return requestmeta.Principal{}, ErrInvalidAPIKey
+	}
+
+	if entity.LastUsedAt == nil || now.Sub(*entity.LastUsedAt) >= lastUsedResolution {
+		if touchError := useCase.apiKeyRepository.TouchLastUsed(requestContext, entity.ID, now); touchError != nil {
+			useCase.logger.Warn(requestContext, "api key last-used update failed", "id", entity.ID, "error", touchError)
+		}
+	}
+
+	return requestmeta.Principal{
+		Subject:    "apikey:" + entity.Prefix,
+		AuthMethod: "api_key",
+		Scopes:     scopesOfAPIKey(entity.Scope),
+		TenantID:   entity.TenantID,
+		Claims:     map[string]any{"key_id": entity.ID, "key_name": entity.Name},
+	}, nil
+}
+
+// secretMatches เทียบกับ digest ที่เคยตรวจผ่านก่อน ถ้าไม่มีค่อยใช้ SecretHasher (ช้า)
+func (useCase *apiKeyUseCase) secretMatches(entity domain.APIKey, secret string) bool {
+	digest := sha256.Sum256([]byte(secret))
+
+	useCase.verifiedMutex.Lock()
+	verified, found := useCase.verifiedSecrets[entity.Prefix]
+	useCase.verifiedMutex.Unlock()
+	if found && verified.secretHash == entity.SecretHash {
+		return subtle.ConstantTimeCompare(verified.digest[:], digest[:]) == 1
+	}
+
+	if !useCase.secretHasher.Matches(entity.SecretHash, secret) {
+		return false
+	}
+	useCase.verifiedMutex.Lock()
+	useCase.verifiedSecrets[entity.Prefix] = verifiedSecret{secretHash: entity.SecretHash, digest: digest}
+	useCase.verifiedMutex.Unlock()
+	return true
+}
+
+// scopesOfAPIKey แปลง scope ของคีย์เป็น permission ที่ principal ได้รับ (ดู authorization.Policy)
+func scopesOfAPIKey(scope string) []string {
+	switch scope {
+	case domain.APIKeyScopeAdmin:
+		return []string{authorization.PermissionBooksRead, authorization.PermissionBooksWrite, authorization.PermissionBooksDelete,
+			authorization.PermissionAPIKeysManage}
+	case domain.APIKeyScopeReadWrite:
+		return []string{authorization.PermissionBooksRead, authorization.PermissionBooksWrite, authorization.PermissionBooksDelete}
+	}
+	return []string{authorization.PermissionBooksRead}
+}
+
+func generateAPIKeyParts() (prefix, secret string, err error) {
+	prefixBytes := make([]byte, apiKeyPrefixBytes)
+	secretBytes := make([]byte, apiKeySecretBytes)
+	if _, err = rand.Read(prefixBytes); err != nil {
+		return "", "", err
+	}
+	if _, err = rand.Read(secretBytes); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(prefixBytes), base64.RawURLEncoding.EncodeToString(secretBytes), nil
+}
+
+func parseAPIKey(credential string) (prefix, secret string, ok bool) {
+	rest, found := strings.CutPrefix(strings.TrimSpace(credential), apiKeyMarker)
+	if !found {
+		return "", "", false
+	}
+	prefix, secret, found = strings.Cut(rest, "_")
+	if !found || len(prefix) != hex.EncodedLen(apiKeyPrefixBytes) || secret == "" {
+		return "", "", false
+	}
+	return prefix, secret, true
+}
+
+func toAPIKeyReadModel(entity domain.APIKey) dto.APIKeyReadModel {
+	return dto.APIKeyReadModel{
+		ID:         entity.ID,
+		Name:       entity.Name,
+		Prefix:     entity.Prefix,
+		Scope:      entity.Scope,
+		CreatedBy:  entity.CreatedBy,
+		CreatedAt:  entity.CreatedAt.Format(time.RFC3339Nano),
+		ExpiresAt:  formatOptionalTime(entity.ExpiresAt),
+		LastUsedAt: formatOptionalTime(entity.LastUsedAt),
+		RevokedAt:  formatOptionalTime(entity.RevokedAt),
+	}
+}
+
+func formatOptionalTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,143 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/authorization"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/dto"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/usecase"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/auth"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/memory"
+)
+
+type fixedClock struct{ now time.Time }
+
+func (clock *fixedClock) Now() time.Time { return clock.now }
+
+type discardLogger struct{}
+
+func (discardLogger) Debug(context.Context, string, ...any) {}
+func (discardLogger) Info(context.Context, string, ...any)  {}
+func (discardLogger) Warn(context.Context, string, ...any)  {}
+func (discardLogger) Error(context.Context, string, ...any) {}
+func (logger discardLogger) With(...any) interfaces.Logger  { return logger }
+
+func newAPIKeyUseCase() (usecase.APIKeyUseCase, *fixedClock) {
+	clock := &fixedClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	return usecase.NewAPIKeyUseCase(memory.NewAPIKeyRepositoryMemory(), auth.NewBcryptHasher(4), clock, discardLogger{}, authorization.DefaultPolicy()), clock
+}
+
+func TestAPIKeyIssueVerifyRevoke(t *testing.T) {
+	ctx := adminContext
+	apiKeys, _ := newAPIKeyUseCase()
+
+	issued, err := apiKeys.Issue(ctx, dto.IssueAPIKeyCommand{Name: "import", Scope: domain.APIKeyScopeReadOnly})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if issued.CreatedBy != "admin-1" || issued.Key == "" {
+		t.Fatalf("unexpected issued key %+v", issued)
+	}
+
+	for range 2 { // ครั้งที่สองตรวจผ่าน digest ที่จำไว้
+		principal, err := apiKeys.Verify(ctx, issued.Key)
+		if err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+		if principal.AuthMethod != "api_key" || !principal.HasScope("books:read") || principal.HasScope("books:write") {
+			t.Fatalf("unexpected principal %+v", principal)
+		}
+	}
+	if _, err := apiKeys.Verify(ctx, issued.Key+"x"); !errors.Is(err, usecase.ErrInvalidAPIKey) {
+		t.Fatalf("tampered key: expected ErrInvalidAPIKey, got %v", err)
+	}
+
+	listed, err := apiKeys.List(ctx)
+	if err != nil || len(listed) != 1 || listed[0].LastUsedAt == "" {
+		t.Fatalf("expected one key with last-used set, got %+v (err %v)", listed, err)
+	}
+
+	if err := apiKeys.Revoke(ctx, issued.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, err := apiKeys.Verify(ctx, issued.Key); !errors.Is(err, usecase.ErrInvalidAPIKey) {
+		t.Fatalf("revoked key: expected ErrInvalidAPIKey, got %v", err)
+	}
+	if err := apiKeys.Revoke(ctx, 999); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown id: expected ErrNotFound, got %v", err)
+	}
+}
+
+var adminContext = requestmeta.WithPrincipal(context.Background(), requestmeta.Principal{Subject: "admin-1", Roles: []string{"admin"}})
+
+func TestAPIKeyExpiry(t *testing.T) {
+	ctx := adminContext
+	apiKeys, clock := newAPIKeyUseCase()
+
+	issued, err := apiKeys.Issue(ctx, dto.IssueAPIKeyCommand{Name: "temp", Scope: domain.APIKeyScopeReadWrite, ExpiresIn: "1h"})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	principal, err := apiKeys.Verify(ctx, issued.Key)
+	if err != nil || !principal.HasScope("books:write") {
+		t.Fatalf("expected read-write key to verify, got %+v (err %v)", principal, err)
+	}
+	clock.now = clock.now.Add(time.Hour)
+	if _, err := apiKeys.Verify(ctx, issued.Key); !errors.Is(err, usecase.ErrInvalidAPIKey) {
+		t.Fatalf("expired key: expected ErrInvalidAPIKey, got %v", err)
+	}
+}
+
+func TestAPIKeyIssueRejectsBadInput(t *testing.T) {
+	apiKeys, _ := newAPIKeyUseCase()
+	for _, command := range []dto.IssueAPIKeyCommand{
+		{Name: "", Scope: domain.APIKeyScopeReadOnly},
+		{Name: "x", Scope: "superuser"},
+		{Name: "x", Scope: domain.APIKeyScopeReadOnly, ExpiresIn: "soon"},
+	} {
+		if _, err := apiKeys.Issue(adminContext, command); !errors.Is(err, domain.ErrBadInput) {
+			t.Errorf("%+v: expected ErrBadInput, got %v", command, err)
+		}
+	}
+}
+
+func TestAPIKeyManagementRequiresPermission(t *testing.T) {
+	apiKeys, _ := newAPIKeyUseCase()
+	editorContext := requestmeta.WithPrincipal(context.Background(), requestmeta.Principal{Subject: "u", Roles: []string{"editor"}})
+	if _, err := apiKeys.Issue(editorContext, dto.IssueAPIKeyCommand{Name: "x", Scope: domain.APIKeyScopeReadOnly}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if _, err := apiKeys.List(context.Background()); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("anonymous: expected ErrForbidden, got %v", err)
+	}
+}
+
+// คีย์ scope admin (ออกผ่าน `apikeys create` ตอนเริ่มระบบ) จัดการคีย์อื่นต่อได้โดยไม่ต้องมี JWT
+func TestAPIKeyAdminScopeCanManageKeys(t *testing.T) {
+	apiKeys, _ := newAPIKeyUseCase()
+	withoutPolicy := usecase.NewAPIKeyUseCase(memory.NewAPIKeyRepositoryMemory(), auth.NewBcryptHasher(4),
+		&fixedClock{now: time.Now()}, discardLogger{}, nil)
+	bootstrap, err := withoutPolicy.Issue(context.Background(), dto.IssueAPIKeyCommand{Name: "bootstrap", Scope: domain.APIKeyScopeAdmin})
+	if err != nil || bootstrap.Scope != domain.APIKeyScopeAdmin {
+		t.Fatalf("nil policy (CLI) should issue an admin key: %+v (err %v)", bootstrap, err)
+	}
+
+	issued, err := apiKeys.Issue(adminContext, dto.IssueAPIKeyCommand{Name: "ops", Scope: domain.APIKeyScopeAdmin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	principal, err := apiKeys.Verify(context.Background(), issued.Key)
+	if err != nil || !principal.HasScope(authorization.PermissionAPIKeysManage) || !principal.HasScope(authorization.PermissionBooksWrite) {
+		t.Fatalf("admin key principal = %+v (err %v)", principal, err)
+	}
+	keyContext := requestmeta.WithPrincipal(context.Background(), principal)
+	if _, err := apiKeys.Issue(keyContext, dto.IssueAPIKeyCommand{Name: "import", Scope: domain.APIKeyScopeReadOnly}); err != nil {
+		t.Fatalf("admin key should issue keys: %v", err)
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"time"
 
 	// เปลี่ยนโมดูลให้ตรงกับของคุณ ถ้าไม่ใช่ path นี้
+	"github.com/nuba55yo/go-101-CleanCRUD/application/authorization"
 	"github.com/nuba55yo/go-101-CleanCRUD/application/dto"
 	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
 	"github.com/nuba55yo/go-101-CleanCRUD/domain"
 )
 
@@ -25,27 +27,44 @@ type bookUseCase struct {
 	bookRepository interfaces.BookRepository
 	clock          interfaces.Clock
 	logger         interfaces.Logger
+	policy         *authorization.Policy
 }
 
 // NewBookUseCase ประกอบ dependencies ให้พร้อมใช้
+// policy = nil เมื่อไม่ได้เปิดการยืนยันตัวตน (ทุกคำขอผ่าน)
 func NewBookUseCase(
 	bookRepository interfaces.BookRepository,
 	clock interfaces.Clock,
 	logger interfaces.Logger,
+	policy *authorization.Policy,
 ) BookUseCase {
 	return &bookUseCase{
 		bookRepository: bookRepository,
 		clock:          clock,
 		logger:         logger,
+		policy:         policy,
 	}
 }
 
+// authorize ตรวจสิทธิ์ก่อนทำงาน; ไม่ผ่านคืน domain.ErrForbidden และบันทึกไว้
+func (useCase *bookUseCase) authorize(requestContext context.Context, permission string) error {
+	if authorizeError := useCase.policy.Authorize(requestContext, permission); authorizeError != nil {
+		useCase.logger.Warn(requestContext, "permission denied", "permission", permission)
+		return authorizeError
+	}
+	return nil
+}
+
 // Create: ตรวจ input, เช็คชื่อซ้ำ (ไม่นับเล่มที่ลบแบบ soft delete), เซฟ, แล้วคืน ReadModel
 func (useCase *bookUseCase) Create(
 	requestContext context.Context,
 	command dto.CreateBookCommand,
 ) (dto.BookReadModel, error) {
 
+	if authorizeError := useCase.authorize(requestContext, authorization.PermissionBooksWrite); authorizeError != nil {
+		return dto.BookReadModel{}, authorizeError
+	}
+
 	title := strings.TrimSpace(command.Title)
 	author := strings.TrimSpace(command.Author)
 	if title == "" || author == "" {
@@ -53,7 +72,7 @@ func (useCase *bookUseCase) Create(
 	}
 
 	isDuplicate, existsError := useCase.bookRepository.
-		ExistsActiveByTitle(strings.ToLower(title), nil)
+		ExistsActiveByTitle(requestContext, strings.ToLower(title), nil)
 	if existsError != nil {
 		return dto.BookReadModel{}, existsError
 	}
@@ -68,7 +87,7 @@ func (useCase *bookUseCase) Create(
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
-	if createError := useCase.bookRepository.Create(&entity); createError != nil {
+	if createError := useCase.bookRepository.Create(requestContext, &entity); createError != nil {
 		return dto.BookReadModel{}, createError
 	}
 
@@ -90,6 +109,10 @@ func (useCase *bookUseCase) Update(
 	command dto.UpdateBookCommand,
 ) (dto.BookReadModel, error) {
 
+	if authorizeError := useCase.authorize(requestContext, authorization.PermissionBooksWrite); authorizeError != nil {
+		return dto.BookReadModel{}, authorizeError
+	}
+
 	title := strings.TrimSpace(command.Title)
 	author := strings.TrimSpace(command.Author)
 	if title == "" || author == "" {
@@ -97,7 +120,7 @@ func (useCase *bookUseCase) Update(
 	}
 
 	isDuplicate, existsError := useCase.bookRepository.
-		ExistsActiveByTitle(strings.ToLower(title), &command.ID)
+		ExistsActiveByTitle(requestContext, strings.ToLower(title), &command.ID)
 	if existsError != nil {
 		return dto.BookReadModel{}, existsError
 	}
@@ -105,7 +128,8 @@ func (useCase *bookUseCase) Update(
 		return dto.BookReadModel{}, domain.ErrTitleExists
 	}
 
-	currentEntity, getError := useCase.bookRepository.GetByID(command.ID)
+	// อ่านจาก primary เสมอ (replica อาจยังไม่เห็นเล่มที่เพิ่งสร้าง)
+	currentEntity, getError := useCase.bookRepository.GetByID(requestmeta.WithStrongRead(requestContext), command.ID)
 	if getError != nil {
 		return dto.BookReadModel{}, getError // รวมทั้งกรณี ErrNotFound
 	}
@@ -114,7 +138,7 @@ func (useCase *bookUseCase) Update(
 	currentEntity.Author = author
 	currentEntity.UpdatedAt = useCase.clock.Now()
 
-	if updateError := useCase.bookRepository.Update(&currentEntity); updateError != nil {
+	if updateError := useCase.bookRepository.Update(requestContext, &currentEntity); updateError != nil {
 		return dto.BookReadModel{}, updateError
 	}
 
@@ -133,7 +157,11 @@ func (useCase *bookUseCase) Get(
 	id uint,
 ) (dto.BookReadModel, error) {
 
-	entity, getError := useCase.bookRepository.GetByID(id)
+	if authorizeError := useCase.authorize(requestContext, authorization.PermissionBooksRead); authorizeError != nil {
+		return dto.BookReadModel{}, authorizeError
+	}
+
+	entity, getError := useCase.bookRepository.GetByID(requestContext, id)
 	if getError != nil {
 		return dto.BookReadModel{}, getError // รวมทั้งกรณี ErrNotFound
 	}
@@ -152,7 +180,11 @@ func (useCase *bookUseCase) List(
 	requestContext context.Context,
 ) ([]dto.BookReadModel, error) {
 
-	entities, listError := useCase.bookRepository.List()
+	if authorizeError := useCase.authorize(requestContext, authorization.PermissionBooksRead); authorizeError != nil {
+		return nil, authorizeError
+	}
+
+	entities, listError := useCase.bookRepository.List(requestContext)
 	if listError != nil {
 		return nil, listError
 	}
@@ -175,5 +207,8 @@ func (useCase *bookUseCase) Delete(
 	requestContext context.Context,
 	id uint,
 ) error {
-	return useCase.bookRepository.SoftDelete(id)
+	if authorizeError := useCase.authorize(requestContext, authorization.PermissionBooksDelete); authorizeError != nil {
+		return authorizeError
+	}
+	return useCase.bookRepository.SoftDelete(requestContext, id)
 }
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/dto"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+// instrumentedBookUseCase = decorator ครอบ BookUseCase นับจำนวน/เวลา/error ของแต่ละ operation
+type instrumentedBookUseCase struct {
+	inner   BookUseCase
+	metrics interfaces.OperationMetrics
+}
+
+// WithOperationMetrics ครอบ bookUseCase ให้ส่งสถิติของทุก operation ไปที่ metrics
+func WithOperationMetrics(bookUseCase BookUseCase, metrics interfaces.OperationMetrics) BookUseCase {
+	return &instrumentedBookUseCase{inner: bookUseCase, metrics: metrics}
+}
+
+// ErrorType แปลง error เป็นชื่อชนิดสั้น ๆ ตาม domain error (ใช้เป็น label ของ metrics)
+func ErrorType(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, domain.ErrBadInput):
+		return "bad_input"
+	case errors.Is(err, domain.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, domain.ErrTitleExists):
+		return "title_exists"
+	case errors.Is(err, domain.ErrForbidden):
+		return "forbidden"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	default:
+		return "internal"
+	}
+}
+
+func (useCase *instrumentedBookUseCase) observe(operation string, started time.Time, err error) {
+	useCase.metrics.ObserveOperation(operation, time.Since(started), ErrorType(err))
+}
+
+func (useCase *instrumentedBookUseCase) Create(requestContext context.Context, command dto.CreateBookCommand) (dto.BookReadModel, error) {
+	started := time.Now()
+	book, err := useCase.inner.Create(requestContext, command)
+	useCase.observe("books.create", started, err)
+	return book, err
+}
+
+func (useCase *instrumentedBookUseCase) Update(requestContext context.Context, command dto.UpdateBookCommand) (dto.BookReadModel, error) {
+	started := time.Now()
+	book, err := useCase.inner.Update(requestContext, command)
+	useCase.observe("books.update", started, err)
+	return book, err
+}
+
+func (useCase *instrumentedBookUseCase) Get(requestContext context.Context, id uint) (dto.BookReadModel, error) {
+	started := time.Now()
+	book, err := useCase.inner.Get(requestContext, id)
+	useCase.observe("books.get", started, err)
+	return book, err
+}
+
+func (useCase *instrumentedBookUseCase) List(requestContext context.Context) ([]dto.BookReadModel, error) {
+	started := time.Now()
+	books, err := useCase.inner.List(requestContext)
+	useCase.observe("books.list", started, err)
+	return books, err
+}
+
+func (useCase *instrumentedBookUseCase) Delete(requestContext context.Context, id uint) error {
+	started := time.Now()
+	err := useCase.inner.Delete(requestContext, id)
+	useCase.observe("books.delete", started, err)
+	return err
+}
@@ -0,0 +1,54 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/dto"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/usecase"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/memory"
+)
+
+type recordedOperation struct {
+	operation string
+	errorType string
+}
+
+type operationRecorder struct {
+	operations []recordedOperation
+}
+
+func (recorder *operationRecorder) ObserveOperation(operation string, _ time.Duration, errorType string) {
+	recorder.operations = append(recorder.operations, recordedOperation{operation: operation, errorType: errorType})
+}
+
+func TestWithOperationMetricsRecordsOutcomes(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	recorder := &operationRecorder{}
+	books := usecase.WithOperationMetrics(
+		usecase.NewBookUseCase(memory.NewBookRepositoryMemory(), clock, discardLogger{}, nil), recorder)
+
+	ctx := context.Background()
+	created, _ := books.Create(ctx, dto.CreateBookCommand{Title: "Refactoring", Author: "Martin Fowler"})
+	_, _ = books.Create(ctx, dto.CreateBookCommand{Title: "Refactoring", Author: "Martin Fowler"})
+	_, _ = books.Create(ctx, dto.CreateBookCommand{})
+	_, _ = books.Get(ctx, created.ID+100)
+	_ = books.Delete(ctx, created.ID)
+
+	want := []recordedOperation{
+		{operation: "books.create"},
+		{operation: "books.create", errorType: "title_exists"},
+		{operation: "books.create", errorType: "bad_input"},
+		{operation: "books.get", errorType: "not_found"},
+		{operation: "books.delete"},
+	}
+	if len(recorder.operations) != len(want) {
+		t.Fatalf("operations = %+v, want %+v", recorder.operations, want)
+	}
+	for index := range want {
+		if recorder.operations[index] != want[index] {
+			t.Fatalf("operation %d = %+v, want %+v", index, recorder.operations[index], want[index])
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/authorization"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/dto"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/usecase"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/memory"
+)
+
+func withRoles(roles ...string) context.Context {
+	return requestmeta.WithPrincipal(context.Background(), requestmeta.Principal{Subject: "u", Roles: roles})
+}
+
+func TestBookUseCaseEnforcesPolicy(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	books := usecase.NewBookUseCase(memory.NewBookRepositoryMemory(), clock, discardLogger{}, authorization.DefaultPolicy())
+
+	created, err := books.Create(withRoles("editor"), dto.CreateBookCommand{Title: "Clean Code", Author: "Robert C. Martin"})
+	if err != nil {
+		t.Fatalf("editor Create: %v", err)
+	}
+	if _, err := books.Get(withRoles("viewer"), created.ID); err != nil {
+		t.Fatalf("viewer Get: %v", err)
+	}
+
+	denied := map[string]error{}
+	_, denied["viewer Create"] = books.Create(withRoles("viewer"), dto.CreateBookCommand{Title: "x", Author: "y"})
+	_, denied["viewer Update"] = books.Update(withRoles("viewer"), dto.UpdateBookCommand{ID: created.ID, Title: "x", Author: "y"})
+	denied["viewer Delete"] = books.Delete(withRoles("viewer"), created.ID)
+	_, denied["anonymous List"] = books.List(context.Background())
+	_, denied["unknown role Get"] = books.Get(withRoles("guest"), created.ID)
+	for name, err := range denied {
+		if !errors.Is(err, domain.ErrForbidden) {
+			t.Errorf("%s: expected ErrForbidden, got %v", name, err)
+		}
+	}
+
+	if err := books.Delete(withRoles("admin"), created.ID); err != nil {
+		t.Fatalf("admin Delete: %v", err)
+	}
+}
+
+func TestBookUseCaseWithoutPolicyAllowsAll(t *testing.T) {
+	books := usecase.NewBookUseCase(memory.NewBookRepositoryMemory(), &fixedClock{now: time.Now()}, discardLogger{}, nil)
+	if _, err := books.Create(context.Background(), dto.CreateBookCommand{Title: "t", Author: "a"}); err != nil {
+		t.Fatalf("Create without policy: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/dto"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+)
+
+// tracedBookUseCase = decorator ครอบ BookUseCase เปิด span ลูกให้ทุกเมธอด
+type tracedBookUseCase struct {
+	inner  BookUseCase
+	tracer interfaces.Tracer
+}
+
+// WithTracing ครอบ bookUseCase ให้ทุกเมธอดมี span ของตัวเอง (อยู่ใต้ span ของ HTTP request)
+func WithTracing(bookUseCase BookUseCase, tracer interfaces.Tracer) BookUseCase {
+	return &tracedBookUseCase{inner: bookUseCase, tracer: tracer}
+}
+
+// finish ปิด span; error ทางธุรกิจ (not_found, forbidden ฯลฯ) ติดไว้เป็น attribute ไม่นับเป็น span ล้มเหลว
+func finish(span interfaces.Span, err error) {
+	if errorType := ErrorType(err); errorType != "" && errorType != "internal" {
+		span.SetAttributes("error.type", errorType)
+		err = nil
+	}
+	span.End(err)
+}
+
+func (useCase *tracedBookUseCase) Create(requestContext context.Context, command dto.CreateBookCommand) (dto.BookReadModel, error) {
+	requestContext, span := useCase.tracer.Start(requestContext, "bookUseCase.Create")
+	book, err := useCase.inner.Create(requestContext, command)
+	if err == nil {
+		span.SetAttributes("book.id", book.ID)
+	}
+	finish(span, err)
+	return book, err
+}
+
+func (useCase *tracedBookUseCase) Update(requestContext context.Context, command dto.UpdateBookCommand) (dto.BookReadModel, error) {
+	requestContext, span := useCase.tracer.Start(requestContext, "bookUseCase.Update", "book.id", command.ID)
+	book, err := useCase.inner.Update(requestContext, command)
+	finish(span, err)
+	return book, err
+}
+
+func (useCase *tracedBookUseCase) Get(requestContext context.Context, id uint) (dto.BookReadModel, error) {
+	requestContext, span := useCase.tracer.Start(requestContext, "bookUseCase.Get", "book.id", id)
+	book, err := useCase.inner.Get(requestContext, id)
+	finish(span, err)
+	return book, err
+}
+
+func (useCase *tracedBookUseCase) List(requestContext context.Context) ([]dto.BookReadModel, error) {
+	requestContext, span := useCase.tracer.Start(requestContext, "bookUseCase.List")
+	books, err := useCase.inner.List(requestContext)
+	if err == nil {
+		span.SetAttributes("books.count", len(books))
+	}
+	finish(span, err)
+	return books, err
+}
+
+func (useCase *tracedBookUseCase) Delete(requestContext context.Context, id uint) error {
+	requestContext, span := useCase.tracer.Start(requestContext, "bookUseCase.Delete", "book.id", id)
+	err := useCase.inner.Delete(requestContext, id)
+	finish(span, err)
+	return err
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/authorization"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/health"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+// DiagnosticsUseCase = พอร์ตเข้าของสถิติภายใน (connection pool, แคช, ผล health check) ต้องมี permission diagnostics:read
+// แหล่งที่ไม่ได้เปิด (เช่น STORAGE=memory, ไม่มีแคช) คืน domain.ErrNotFound
+type DiagnosticsUseCase interface {
+	DatabaseStats(requestContext context.Context) (sql.DBStats, error)
+	CacheStats(requestContext context.Context) (any, error)
+	HealthDetails(requestContext context.Context) (health.Report, error)
+}
+
+type diagnosticsUseCase struct {
+	databaseStats  func() (sql.DBStats, error)
+	cacheStats     func() any
+	healthRegistry *health.Registry
+	logger         interfaces.Logger
+	policy         *authorization.Policy
+}
+
+// NewDiagnosticsUseCase ประกอบ dependencies ให้พร้อมใช้ (databaseStats/cacheStats/healthRegistry เป็น nil ได้)
+func NewDiagnosticsUseCase(
+	databaseStats func() (sql.DBStats, error),
+	cacheStats func() any,
+	healthRegistry *health.Registry,
+	logger interfaces.Logger,
+	policy *authorization.Policy,
+) DiagnosticsUseCase {
+	return &diagnosticsUseCase{
+		databaseStats:  databaseStats,
+		cacheStats:     cacheStats,
+		healthRegistry: healthRegistry,
+		logger:         logger,
+		policy:         policy,
+	}
+}
+
+func (useCase *diagnosticsUseCase) authorize(requestContext context.Context) error {
+	if authorizeError := useCase.policy.Authorize(requestContext, authorization.PermissionDiagnosticsRead); authorizeError != nil {
+		useCase.logger.Warn(requestContext, "permission denied", "permission", authorization.PermissionDiagnosticsRead)
+		return authorizeError
+	}
+	return nil
+}
+
+// DatabaseStats คืนสถิติ connection pool ของฐานข้อมูลหลัก
+func (useCase *diagnosticsUseCase) DatabaseStats(requestContext context.Context) (sql.DBStats, error) {
+	if authorizeError := useCase.authorize(requestContext); authorizeError != nil {
+		return sql.DBStats{}, authorizeError
+	}
+	if useCase.databaseStats == nil {
+		return sql.DBStats{}, domain.ErrNotFound
+	}
+	return useCase.databaseStats()
+}
+
+// CacheStats คืนตัวนับ hit/miss ของแคช
+func (useCase *diagnosticsUseCase) CacheStats(requestContext context.Context) (any, error) {
+	if authorizeError := useCase.authorize(requestContext); authorizeError != nil {
+		return nil, authorizeError
+	}
+	if useCase.cacheStats == nil {
+		return nil, domain.ErrNotFound
+	}
+	return useCase.cacheStats(), nil
+}
+
+// HealthDetails รันทุก health check; output ของ check ที่ล้มอาจมี host/user ของ dependency จึงต้องมีสิทธิ์
+func (useCase *diagnosticsUseCase) HealthDetails(requestContext context.Context) (health.Report, error) {
+	if authorizeError := useCase.authorize(requestContext); authorizeError != nil {
+		return health.Report{}, authorizeError
+	}
+	if useCase.healthRegistry == nil {
+		return health.Report{}, domain.ErrNotFound
+	}
+	return useCase.healthRegistry.Details(requestContext), nil
+}
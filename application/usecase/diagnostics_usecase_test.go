@@ -0,0 +1,51 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/authorization"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/health"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/usecase"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+func TestDiagnosticsUseCase(t *testing.T) {
+	diagnostics := usecase.NewDiagnosticsUseCase(
+		func() (sql.DBStats, error) { return sql.DBStats{OpenConnections: 3}, nil }, nil, nil,
+		discardLogger{}, authorization.DefaultPolicy())
+
+	if _, err := diagnostics.DatabaseStats(context.Background()); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("anonymous DatabaseStats err = %v, want ErrForbidden", err)
+	}
+	if _, err := diagnostics.DatabaseStats(withRoles("editor")); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("editor DatabaseStats err = %v, want ErrForbidden", err)
+	}
+	stats, err := diagnostics.DatabaseStats(withRoles("admin"))
+	if err != nil || stats.OpenConnections != 3 {
+		t.Fatalf("admin DatabaseStats = %+v, %v", stats, err)
+	}
+	if _, err := diagnostics.CacheStats(withRoles("admin")); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("CacheStats without a cache err = %v, want ErrNotFound", err)
+	}
+}
+
+// ผล health check ละเอียด (มี error ของ dependency) ต้องมี diagnostics:read เหมือนสถิติอื่น
+func TestDiagnosticsUseCaseHealthDetails(t *testing.T) {
+	registry := health.NewRegistry(time.Second)
+	registry.Register("database", func(context.Context) error {
+		return errors.New("dial tcp db.internal:5432: connect refused (user=books)")
+	}, health.Critical())
+	diagnostics := usecase.NewDiagnosticsUseCase(nil, nil, registry, discardLogger{}, authorization.DefaultPolicy())
+
+	if _, err := diagnostics.HealthDetails(context.Background()); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("anonymous HealthDetails err = %v, want ErrForbidden", err)
+	}
+	report, err := diagnostics.HealthDetails(withRoles("admin"))
+	if err != nil || report.Status != health.StatusFail {
+		t.Fatalf("admin HealthDetails = %+v, %v", report, err)
+	}
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/authorization"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+// LogLevelUseCase = พอร์ตเข้าของการดู/ปรับระดับ log ขณะรัน (ต้องมี permission logs:manage)
+type LogLevelUseCase interface {
+	Level(requestContext context.Context) (string, error)
+	SetLevel(requestContext context.Context, level string) (string, error)
+}
+
+type logLevelUseCase struct {
+	levels interfaces.LogLevelController
+	logger interfaces.Logger
+	policy *authorization.Policy
+}
+
+// NewLogLevelUseCase ประกอบ dependencies ให้พร้อมใช้
+func NewLogLevelUseCase(levels interfaces.LogLevelController, logger interfaces.Logger, policy *authorization.Policy) LogLevelUseCase {
+	return &logLevelUseCase{levels: levels, logger: logger, policy: policy}
+}
+
+func (useCase *logLevelUseCase) authorize(requestContext context.Context) error {
+	if authorizeError := useCase.policy.Authorize(requestContext, authorization.PermissionLogsManage); authorizeError != nil {
+		useCase.logger.Warn(requestContext, "permission denied", "permission", authorization.PermissionLogsManage)
+		return authorizeError
+	}
+	return nil
+}
+
+// Level คืนระดับ log ปัจจุบัน
+func (useCase *logLevelUseCase) Level(requestContext context.Context) (string, error) {
+	if authorizeError := useCase.authorize(requestContext); authorizeError != nil {
+		return "", authorizeError
+	}
+	return useCase.levels.Level(), nil
+}
+
+// SetLevel เปลี่ยนระดับ log (มีผลทันทีกับทุก logger) ชื่อระดับไม่ถูกต้องคืน domain.ErrBadInput
+func (useCase *logLevelUseCase) SetLevel(requestContext context.Context, level string) (string, error) {
+	if authorizeError := useCase.authorize(requestContext); authorizeError != nil {
+		return "", authorizeError
+	}
+	previous := useCase.levels.Level()
+	if setError := useCase.levels.SetLevel(level); setError != nil {
+		return "", domain.ErrBadInput
+	}
+	current := useCase.levels.Level()
+	// Warn ให้ยังเห็นบรรทัดนี้แม้ปรับระดับขึ้นเป็น warn
+	useCase.logger.Warn(requestContext, "log level changed", "from", previous, "to", current)
+	return current, nil
+}
@@ -0,0 +1,43 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/authorization"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/usecase"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+type levelHolder struct{ level string }
+
+func (holder *levelHolder) Level() string { return holder.level }
+
+func (holder *levelHolder) SetLevel(level string) error {
+	switch level {
+	case "debug", "info", "warn", "error":
+		holder.level = level
+		return nil
+	}
+	return errors.New("unknown level")
+}
+
+func TestLogLevelUseCase(t *testing.T) {
+	levels := &levelHolder{level: "info"}
+	logLevels := usecase.NewLogLevelUseCase(levels, discardLogger{}, authorization.DefaultPolicy())
+
+	if _, err := logLevels.SetLevel(withRoles("editor"), "debug"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("editor SetLevel err = %v, want ErrForbidden", err)
+	}
+	if _, err := logLevels.Level(context.Background()); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("anonymous Level err = %v, want ErrForbidden", err)
+	}
+	if _, err := logLevels.SetLevel(withRoles("admin"), "loud"); !errors.Is(err, domain.ErrBadInput) {
+		t.Fatalf("SetLevel(loud) err = %v, want ErrBadInput", err)
+	}
+	level, err := logLevels.SetLevel(withRoles("admin"), "debug")
+	if err != nil || level != "debug" || levels.level != "debug" {
+		t.Fatalf("admin SetLevel = %q, %v (holder %q)", level, err, levels.level)
+	}
+}
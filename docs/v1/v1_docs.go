@@ -23,15 +23,23 @@ const docTemplatev1 = `{
                 "tags": [
                     "books"
                 ],
-                "summary": "ดึงรายการหนังสือทั้งหมด",
+                "summary": "List books",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "type": "object",
-                                "additionalProperties": true
+                                "$ref": "#/definitions/v1.BookJSON"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
@@ -47,7 +55,7 @@ const docTemplatev1 = `{
                 "tags": [
                     "books"
                 ],
-                "summary": "สร้างหนังสือใหม่",
+                "summary": "Create book",
                 "parameters": [
                     {
                         "description": "payload",
@@ -55,7 +63,7 @@ const docTemplatev1 = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateBookRequest"
+                            "$ref": "#/definitions/v1.CreateBookJSON"
                         }
                     }
                 ],
@@ -63,8 +71,7 @@ const docTemplatev1 = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/v1.BookJSON"
                         }
                     },
                     "400": {
@@ -76,6 +83,15 @@ const docTemplatev1 = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "409": {
                         "description": "Conflict",
                         "schema": {
@@ -96,7 +112,7 @@ const docTemplatev1 = `{
                 "tags": [
                     "books"
                 ],
-                "summary": "ดึงหนังสือตามรหัส",
+                "summary": "Get book by id",
                 "parameters": [
                     {
                         "type": "integer",
@@ -109,9 +125,17 @@ const docTemplatev1 = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.BookJSON"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -135,7 +159,7 @@ const docTemplatev1 = `{
                 "tags": [
                     "books"
                 ],
-                "summary": "แก้ไขหนังสือ",
+                "summary": "Update book",
                 "parameters": [
                     {
                         "type": "integer",
@@ -150,7 +174,7 @@ const docTemplatev1 = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateBookRequest"
+                            "$ref": "#/definitions/v1.UpdateBookJSON"
                         }
                     }
                 ],
@@ -158,8 +182,7 @@ const docTemplatev1 = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/v1.BookJSON"
                         }
                     },
                     "400": {
@@ -171,6 +194,15 @@ const docTemplatev1 = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -195,7 +227,7 @@ const docTemplatev1 = `{
                 "tags": [
                     "books"
                 ],
-                "summary": "ลบหนังสือ (soft delete)",
+                "summary": "Delete book (soft delete)",
                 "parameters": [
                     {
                         "type": "integer",
@@ -209,8 +241,8 @@ const docTemplatev1 = `{
                     "204": {
                         "description": "No Content"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -223,37 +255,49 @@ const docTemplatev1 = `{
         }
     },
     "definitions": {
-        "dto.CreateBookRequest": {
+        "v1.BookJSON": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CreateBookJSON": {
             "type": "object",
-            "required": [
-                "author",
-                "title"
-            ],
             "properties": {
                 "author": {
                     "type": "string",
-                    "minLength": 1
+                    "example": "Eric Evans"
                 },
                 "title": {
                     "type": "string",
-                    "minLength": 1
+                    "example": "Domain-Driven Design"
                 }
             }
         },
-        "dto.UpdateBookRequest": {
+        "v1.UpdateBookJSON": {
             "type": "object",
-            "required": [
-                "author",
-                "title"
-            ],
             "properties": {
                 "author": {
                     "type": "string",
-                    "minLength": 1
+                    "example": "Eric Evans"
                 },
                 "title": {
                     "type": "string",
-                    "minLength": 1
+                    "example": "DDD 2nd"
                 }
             }
         }
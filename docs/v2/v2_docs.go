@@ -21,15 +21,26 @@ const docTemplatev2 = `{
                     "application/json"
                 ],
                 "tags": [
-                    "books-v2"
+                    "books"
                 ],
                 "summary": "List books (v2)",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v2.BookJSON"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
@@ -42,7 +53,7 @@ const docTemplatev2 = `{
                     "application/json"
                 ],
                 "tags": [
-                    "books-v2"
+                    "books"
                 ],
                 "summary": "Create book (v2)",
                 "parameters": [
@@ -52,7 +63,7 @@ const docTemplatev2 = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateBookRequest"
+                            "$ref": "#/definitions/v2.CreateBookJSON"
                         }
                     }
                 ],
@@ -60,8 +71,7 @@ const docTemplatev2 = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/v2.BookJSON"
                         }
                     },
                     "400": {
@@ -73,6 +83,15 @@ const docTemplatev2 = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "409": {
                         "description": "Conflict",
                         "schema": {
@@ -91,7 +110,7 @@ const docTemplatev2 = `{
                     "application/json"
                 ],
                 "tags": [
-                    "books-v2"
+                    "books"
                 ],
                 "summary": "Get book by id (v2)",
                 "parameters": [
@@ -106,9 +125,17 @@ const docTemplatev2 = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.BookJSON"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
@@ -130,7 +157,7 @@ const docTemplatev2 = `{
                     "application/json"
                 ],
                 "tags": [
-                    "books-v2"
+                    "books"
                 ],
                 "summary": "Update book (v2)",
                 "parameters": [
@@ -147,7 +174,7 @@ const docTemplatev2 = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateBookRequest"
+                            "$ref": "#/definitions/v2.UpdateBookJSON"
                         }
                     }
                 ],
@@ -155,8 +182,7 @@ const docTemplatev2 = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/v2.BookJSON"
                         }
                     },
                     "400": {
@@ -168,6 +194,15 @@ const docTemplatev2 = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -190,9 +225,9 @@ const docTemplatev2 = `{
             },
             "delete": {
                 "tags": [
-                    "books-v2"
+                    "books"
                 ],
-                "summary": "Delete book (soft delete) (v2)",
+                "summary": "Delete book (v2) (soft delete)",
                 "parameters": [
                     {
                         "type": "integer",
@@ -206,8 +241,8 @@ const docTemplatev2 = `{
                     "204": {
                         "description": "No Content"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -220,37 +255,57 @@ const docTemplatev2 = `{
         }
     },
     "definitions": {
-        "dto.CreateBookRequest": {
+        "v2.BookData": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "v2.BookJSON": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/v2.BookData"
+                },
+                "version": {
+                    "description": "\"v2\"",
+                    "type": "string"
+                }
+            }
+        },
+        "v2.CreateBookJSON": {
             "type": "object",
-            "required": [
-                "author",
-                "title"
-            ],
             "properties": {
                 "author": {
-                    "type": "string",
-                    "minLength": 1
+                    "type": "string"
                 },
                 "title": {
-                    "type": "string",
-                    "minLength": 1
+                    "type": "string"
                 }
             }
         },
-        "dto.UpdateBookRequest": {
+        "v2.UpdateBookJSON": {
             "type": "object",
-            "required": [
-                "author",
-                "title"
-            ],
             "properties": {
                 "author": {
-                    "type": "string",
-                    "minLength": 1
+                    "type": "string"
                 },
                 "title": {
-                    "type": "string",
-                    "minLength": 1
+                    "type": "string"
                 }
             }
         }
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// ขอบเขตของ API key
+const (
+	APIKeyScopeReadOnly  = "read-only"  // อ่านหนังสือได้อย่างเดียว
+	APIKeyScopeReadWrite = "read-write" // อ่าน/สร้าง/แก้/ลบได้
+	APIKeyScopeAdmin     = "admin"      // read-write + ออก/ดู/เพิกถอน API key
+)
+
+// APIKey = credential ระยะยาวสำหรับระบบที่ใช้ OAuth ไม่ได้ (เช่น batch integration)
+// เก็บเฉพาะ hash ของ secret; Prefix ใช้ค้นหาคีย์ (ไม่เป็นความลับ)
+type APIKey struct {
+	ID         uint
+	TenantID   string // คีย์ใช้ได้เฉพาะใน tenant ที่ออกให้
+	Name       string
+	Prefix     string
+	SecretHash string
+	Scope      string
+	CreatedBy  string
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time // nil = ไม่หมดอายุ
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// IsUsableAt = คีย์ยังใช้ได้ ณ เวลา now (ยังไม่ถูกเพิกถอนและยังไม่หมดอายุ)
+func (key APIKey) IsUsableAt(now time.Time) bool {
+	if key.RevokedAt != nil {
+		return false
+	}
+	return key.ExpiresAt == nil || now.Before(*key.ExpiresAt)
+}
+
+// ValidAPIKeyScope = scope นี้รองรับหรือไม่
+func ValidAPIKeyScope(scope string) bool {
+	return scope == APIKeyScopeReadOnly || scope == APIKeyScopeReadWrite || scope == APIKeyScopeAdmin
+}
@@ -12,4 +12,7 @@ var (
 
 	// หาไม่เจอ (เช่น id ไม่ตรงกับข้อมูลในระบบ)
 	ErrNotFound = errors.New("not found")
+
+	// ผู้เรียกไม่มีสิทธิ์ทำรายการนี้ (ยืนยันตัวตนแล้ว แต่บทบาท/scope ไม่พอ)
+	ErrForbidden = errors.New("forbidden")
 )
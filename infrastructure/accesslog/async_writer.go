@@ -0,0 +1,177 @@
+package accesslog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+)
+
+// OverflowPolicy = สิ่งที่ทำเมื่อคิวเต็ม
+type OverflowPolicy string
+
+const (
+	// OverflowDrop ทิ้งบรรทัดแล้วนับผ่าน OnDrop (request ไม่ต้องรอดิสก์เลย)
+	OverflowDrop OverflowPolicy = "drop"
+	// OverflowBlock ให้ request รอจนคิวมีที่ว่าง (ไม่เสีย log แต่ดิสก์ช้า = API ช้า)
+	OverflowBlock OverflowPolicy = "block"
+)
+
+// ParseOverflowPolicy อ่าน "drop" / "block" (ว่าง = drop)
+func ParseOverflowPolicy(text string) (OverflowPolicy, error) {
+	switch policy := OverflowPolicy(strings.ToLower(strings.TrimSpace(text))); policy {
+	case "", OverflowDrop:
+		return OverflowDrop, nil
+	case OverflowBlock:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("access log overflow policy %q: want drop or block", text)
+	}
+}
+
+// AsyncConfig = ขนาดคิวและจังหวะการเขียนเป็นชุด
+type AsyncConfig struct {
+	QueueSize     int            // จำนวนบรรทัดที่รอเขียนได้ (ค่าเริ่มต้น 10000)
+	BatchSize     int            // เขียนทันทีเมื่อสะสมครบเท่านี้ (ค่าเริ่มต้น 256)
+	FlushInterval time.Duration  // เขียนบรรทัดที่ค้างอย่างน้อยทุกช่วงนี้ (ค่าเริ่มต้น 1s)
+	Overflow      OverflowPolicy // ค่าเริ่มต้น OverflowDrop
+	OnDrop        func()         // ถูกเรียกทุกบรรทัดที่ทิ้งเพราะคิวเต็ม (เช่น นับเป็น metrics)
+	OnError       func(error)    // error จากการเขียนเบื้องหลัง
+}
+
+// batchWriter = ปลายทางที่เขียนทีละชุดได้ (เช่น RotatingFile)
+type batchWriter interface {
+	WriteLines(lines []Line) error
+}
+
+// AsyncWriter ครอบ AccessLogWriter ให้ WriteLine แค่ใส่คิวแล้วคืนทันที goroutine เบื้องหลังเขียนเป็นชุด
+// Close เขียนบรรทัดที่ค้างทั้งหมดก่อนปิดปลายทาง
+type AsyncWriter struct {
+	next   interfaces.AccessLogWriter
+	config AsyncConfig
+	queue  chan Line
+	done   chan struct{}
+
+	// closeMutex กันส่งเข้าคิวที่ปิดแล้ว: WriteLine ถือ RLock, Close ถือ Lock
+	closeMutex sync.RWMutex
+	closed     bool
+}
+
+// NewAsyncWriter เริ่ม goroutine เขียนเบื้องหลัง (ต้องเรียก Close ตอนปิดโปรแกรม)
+func NewAsyncWriter(next interfaces.AccessLogWriter, config AsyncConfig) *AsyncWriter {
+	if config.QueueSize <= 0 {
+		config.QueueSize = 10_000
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 256
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	if config.Overflow == "" {
+		config.Overflow = OverflowDrop
+	}
+	writer := &AsyncWriter{
+		next:   next,
+		config: config,
+		queue:  make(chan Line, config.QueueSize),
+		done:   make(chan struct{}),
+	}
+	go writer.run()
+	return writer
+}
+
+// WriteLine ใส่บรรทัดเข้าคิว; คิวเต็ม = ทิ้ง (OverflowDrop) หรือรอ (OverflowBlock)
+// หลัง Close แล้วจะเขียนตรงไปที่ปลายทาง
+func (writer *AsyncWriter) WriteLine(now time.Time, line string) error {
+	writer.closeMutex.RLock()
+	defer writer.closeMutex.RUnlock()
+	if writer.closed {
+		return writer.next.WriteLine(now, line)
+	}
+
+	entry := Line{Time: now, Text: line}
+	if writer.config.Overflow == OverflowBlock {
+		writer.queue <- entry
+		return nil
+	}
+	select {
+	case writer.queue <- entry:
+	default:
+		if writer.config.OnDrop != nil {
+			writer.config.OnDrop()
+		}
+	}
+	return nil
+}
+
+// QueueLength = จำนวนบรรทัดที่รอเขียนอยู่
+func (writer *AsyncWriter) QueueLength() int {
+	return len(writer.queue)
+}
+
+func (writer *AsyncWriter) run() {
+	defer close(writer.done)
+	ticker := time.NewTicker(writer.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Line, 0, writer.config.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		writer.write(batch)
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case entry, open := <-writer.queue:
+			if !open {
+				flush()
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) >= writer.config.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (writer *AsyncWriter) write(batch []Line) {
+	if next, ok := writer.next.(batchWriter); ok {
+		writer.reportError(next.WriteLines(batch))
+		return
+	}
+	for _, entry := range batch {
+		writer.reportError(writer.next.WriteLine(entry.Time, entry.Text))
+	}
+}
+
+func (writer *AsyncWriter) reportError(err error) {
+	if err != nil && writer.config.OnError != nil {
+		writer.config.OnError(err)
+	}
+}
+
+// Close หยุดรับเข้าคิว เขียนบรรทัดที่ค้างทั้งหมด แล้วปิดปลายทาง
+func (writer *AsyncWriter) Close() error {
+	return writer.CloseContext(context.Background())
+}
+
+// CloseContext = Close ที่ส่ง ctx ต่อให้ปลายทาง (เช่น HTTPWriter) จำกัดเวลาปิด
+func (writer *AsyncWriter) CloseContext(ctx context.Context) error {
+	writer.closeMutex.Lock()
+	if !writer.closed {
+		writer.closed = true
+		close(writer.queue)
+	}
+	writer.closeMutex.Unlock()
+	<-writer.done
+	return CloseContext(ctx, writer.next)
+}
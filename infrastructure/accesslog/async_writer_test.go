@@ -0,0 +1,210 @@
+package accesslog_test
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/accesslog"
+)
+
+// recordingWriter เก็บบรรทัดที่ได้รับ; gate != nil = ค้างทุกการเขียนจนกว่าจะปิด gate (จำลองดิสก์ช้า)
+type recordingWriter struct {
+	mutex   sync.Mutex
+	lines   []string
+	batches int
+	closed  bool
+	gate    chan struct{}
+	delay   time.Duration
+}
+
+func (writer *recordingWriter) WriteLine(_ time.Time, line string) error {
+	return writer.WriteLines([]accesslog.Line{{Text: line}})
+}
+
+func (writer *recordingWriter) WriteLines(lines []accesslog.Line) error {
+	if writer.gate != nil {
+		<-writer.gate
+	}
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	time.Sleep(writer.delay) // ถือ lock ไว้ระหว่างหน่วง: ดิสก์เขียนได้ทีละครั้ง
+	for _, line := range lines {
+		writer.lines = append(writer.lines, line.Text)
+	}
+	writer.batches++
+	return nil
+}
+
+func (writer *recordingWriter) Close() error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	writer.closed = true
+	return nil
+}
+
+func (writer *recordingWriter) snapshot() ([]string, int) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	return slices.Clone(writer.lines), writer.batches
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAsyncWriterFlushesOnClose(t *testing.T) {
+	next := &recordingWriter{}
+	writer := accesslog.NewAsyncWriter(next, accesslog.AsyncConfig{BatchSize: 100, FlushInterval: time.Hour})
+
+	var want []string
+	for index := range 250 {
+		line := fmt.Sprintf("line %d", index)
+		want = append(want, line)
+		_ = writer.WriteLine(time.Now(), line)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	lines, batches := next.snapshot()
+	if !slices.Equal(lines, want) {
+		t.Fatalf("got %d lines, want all %d in order", len(lines), len(want))
+	}
+	if batches != 3 || !next.closed {
+		t.Fatalf("batches = %d, closed = %v; want 3 batches and the next writer closed", batches, next.closed)
+	}
+
+	// หลัง Close เขียนตรงไปที่ปลายทาง
+	_ = writer.WriteLine(time.Now(), "late")
+	if lines, _ := next.snapshot(); lines[len(lines)-1] != "late" {
+		t.Fatal("write after Close was lost")
+	}
+}
+
+func TestAsyncWriterFlushesOnInterval(t *testing.T) {
+	next := &recordingWriter{}
+	writer := accesslog.NewAsyncWriter(next, accesslog.AsyncConfig{BatchSize: 100, FlushInterval: 10 * time.Millisecond})
+	defer writer.Close()
+
+	_ = writer.WriteLine(time.Now(), "only line")
+	waitFor(t, func() bool { lines, _ := next.snapshot(); return len(lines) == 1 })
+}
+
+func TestAsyncWriterOverflowDrop(t *testing.T) {
+	next := &recordingWriter{gate: make(chan struct{})}
+	var dropped atomic.Int64
+	writer := accesslog.NewAsyncWriter(next, accesslog.AsyncConfig{
+		QueueSize: 2, BatchSize: 1, FlushInterval: time.Hour, OnDrop: func() { dropped.Add(1) },
+	})
+
+	started := time.Now()
+	for index := range 10 {
+		_ = writer.WriteLine(time.Now(), fmt.Sprintf("line %d", index))
+	}
+	if elapsed := time.Since(started); elapsed > 100*time.Millisecond {
+		t.Fatalf("WriteLine blocked for %s with the drop policy", elapsed)
+	}
+	close(next.gate)
+	_ = writer.Close()
+
+	lines, _ := next.snapshot()
+	if got := int64(len(lines)) + dropped.Load(); got != 10 || dropped.Load() == 0 {
+		t.Fatalf("written %d + dropped %d, want 10 with some dropped", len(lines), dropped.Load())
+	}
+}
+
+func TestAsyncWriterOverflowBlock(t *testing.T) {
+	next := &recordingWriter{delay: time.Millisecond}
+	writer := accesslog.NewAsyncWriter(next, accesslog.AsyncConfig{
+		QueueSize: 2, BatchSize: 1, FlushInterval: time.Hour, Overflow: accesslog.OverflowBlock,
+		OnDrop: func() { t.Error("line dropped with the block policy") },
+	})
+
+	var waitGroup sync.WaitGroup
+	for worker := range 4 {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for index := range 25 {
+				_ = writer.WriteLine(time.Now(), fmt.Sprintf("%d-%d", worker, index))
+			}
+		}()
+	}
+	waitGroup.Wait()
+	_ = writer.Close()
+
+	if lines, _ := next.snapshot(); len(lines) != 100 {
+		t.Fatalf("written %d lines, want 100", len(lines))
+	}
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	if policy, err := accesslog.ParseOverflowPolicy(""); err != nil || policy != accesslog.OverflowDrop {
+		t.Fatalf("empty = %q, %v; want drop", policy, err)
+	}
+	if _, err := accesslog.ParseOverflowPolicy("wait"); err == nil {
+		t.Fatal("unknown policy accepted")
+	}
+}
+
+// BenchmarkAccessLogWrite เทียบเวลาที่ request เสียไปกับการเขียน access log หนึ่งบรรทัด
+// ระหว่างเขียนตรง (sync, แบบเดิม) กับผ่าน AsyncWriter บนไฟล์จริงและดิสก์ช้าจำลอง (2ms ต่อการเขียน)
+//
+//	go test ./infrastructure/accesslog -run '^$' -bench AccessLogWrite
+func BenchmarkAccessLogWrite(b *testing.B) {
+	line := `2025-08-09 05:01:14.533 [books] [info] request_id=01J0000000000000000000000 status=200 method=GET ` +
+		`route=/api/v1/books ip=127.0.0.1 latency=412µs headers={"Accept":"*/*"} req= res=[{"id":1,"title":"Go"}]`
+
+	destinations := []struct {
+		name string
+		open func(b *testing.B) interfaces.AccessLogWriter
+	}{
+		{"file", func(b *testing.B) interfaces.AccessLogWriter {
+			rotatingFile, err := accesslog.NewRotatingFile(accesslog.Config{Directory: b.TempDir()})
+			if err != nil {
+				b.Fatal(err)
+			}
+			return rotatingFile
+		}},
+		{"slow-disk", func(*testing.B) interfaces.AccessLogWriter { return &recordingWriter{delay: 2 * time.Millisecond} }},
+	}
+	for _, destination := range destinations {
+		b.Run("sync/"+destination.name, func(b *testing.B) {
+			writer := destination.open(b)
+			defer writer.Close()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_ = writer.WriteLine(time.Now(), line)
+				}
+			})
+		})
+		for _, overflow := range []accesslog.OverflowPolicy{accesslog.OverflowDrop, accesslog.OverflowBlock} {
+			b.Run(fmt.Sprintf("async-%s/%s", overflow, destination.name), func(b *testing.B) {
+				var dropped atomic.Int64
+				writer := accesslog.NewAsyncWriter(destination.open(b), accesslog.AsyncConfig{
+					Overflow: overflow, OnDrop: func() { dropped.Add(1) },
+				})
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						_ = writer.WriteLine(time.Now(), line)
+					}
+				})
+				b.StopTimer()
+				_ = writer.Close()
+				b.ReportMetric(float64(dropped.Load())/float64(b.N), "dropped/op")
+			})
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package accesslog
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/config"
+)
+
+const megabyte = 1 << 20
+
+// ConfigFromEnv อ่านค่าไฟล์ access log จาก ACCESS_LOG_* (ค่าเริ่มต้น = พฤติกรรมเดิม: logs/ หมุนทุก 10 นาที ไม่ลบ ไม่บีบ)
+func ConfigFromEnv() Config {
+	return Config{
+		Directory:       config.String("ACCESS_LOG_DIR", "logs"),
+		Interval:        config.Duration("ACCESS_LOG_ROTATE_INTERVAL", 10*time.Minute),
+		MaxSize:         int64(config.Int("ACCESS_LOG_MAX_SIZE_MB", 0)) * megabyte,
+		Compress:        config.Bool("ACCESS_LOG_COMPRESS", false),
+		MaxAge:          config.Duration("ACCESS_LOG_MAX_AGE", 0),
+		MaxTotalSize:    int64(config.Int("ACCESS_LOG_MAX_TOTAL_SIZE_MB", 0)) * megabyte,
+		CleanupInterval: config.Duration("ACCESS_LOG_CLEANUP_INTERVAL", 10*time.Minute),
+		FileMode:        fileModeFromEnv("ACCESS_LOG_FILE_MODE", 0o644),
+		DirMode:         fileModeFromEnv("ACCESS_LOG_DIR_MODE", 0o755),
+	}
+}
+
+// fileModeFromEnv อ่านสิทธิ์แบบเลขฐานแปด (เช่น 0640) ถ้าว่างหรืออ่านไม่ได้คืน fallback
+func fileModeFromEnv(key string, fallback os.FileMode) os.FileMode {
+	value, err := strconv.ParseUint(os.Getenv(key), 8, 32)
+	if err != nil || value > 0o777 {
+		return fallback
+	}
+	return os.FileMode(value)
+}
+
+// AsyncConfigFromEnv อ่านค่าคิวของ AsyncWriter จาก ACCESS_LOG_QUEUE_SIZE / _BATCH_SIZE / _FLUSH_INTERVAL / _OVERFLOW
+func AsyncConfigFromEnv() (AsyncConfig, error) {
+	overflow, err := ParseOverflowPolicy(os.Getenv("ACCESS_LOG_OVERFLOW"))
+	if err != nil {
+		return AsyncConfig{}, err
+	}
+	return AsyncConfig{
+		QueueSize:     config.Int("ACCESS_LOG_QUEUE_SIZE", 10_000),
+		BatchSize:     config.Int("ACCESS_LOG_BATCH_SIZE", 256),
+		FlushInterval: config.Duration("ACCESS_LOG_FLUSH_INTERVAL", time.Second),
+		Overflow:      overflow,
+	}, nil
+}
+
+// OpenWriterFromEnv เปิดปลายทางตาม ACCESS_LOG_SINKS (คั่นด้วย , จาก file, stdout, syslog, http; ค่าเริ่มต้น file)
+// หลายปลายทาง = ส่งทุกบรรทัดไปทุกที่ผ่าน MultiWriter; onError รับ error จากการเขียนเบื้องหลังของแต่ละปลายทาง
+func OpenWriterFromEnv(onError func(error)) (interfaces.AccessLogWriter, error) {
+	var writers []interfaces.AccessLogWriter
+	closeAll := func() {
+		for _, writer := range writers {
+			_ = writer.Close()
+		}
+	}
+	for _, sink := range config.List("ACCESS_LOG_SINKS", ",", []string{"file"}) {
+		writer, err := openSinkFromEnv(strings.ToLower(sink), onError)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		writers = append(writers, writer)
+	}
+	if len(writers) == 1 {
+		return writers[0], nil
+	}
+	return NewMultiWriter(writers...), nil
+}
+
+func openSinkFromEnv(sink string, onError func(error)) (interfaces.AccessLogWriter, error) {
+	switch sink {
+	case "file":
+		fileConfig := ConfigFromEnv()
+		fileConfig.OnError = onError
+		return NewRotatingFile(fileConfig)
+	case "stdout":
+		return NewStreamWriter(os.Stdout), nil
+	case "syslog":
+		return NewSyslogWriter(SyslogConfig{
+			Address:  config.String("ACCESS_LOG_SYSLOG_ADDR", "udp://127.0.0.1:514"),
+			Facility: config.String("ACCESS_LOG_SYSLOG_FACILITY", "local0"),
+			AppName:  config.String("ACCESS_LOG_SYSLOG_APP_NAME", "books-api"),
+			// RFC 5424 ให้ receiver รับได้อย่างน้อย 480 byte และควรรับ 2048 byte; เกินนี้หลายตัวทิ้งทั้งข้อความ
+			MaxMessageBytes: config.Int("ACCESS_LOG_SYSLOG_MAX_MESSAGE_BYTES", 2048),
+		})
+	case "http":
+		headers, err := parseHeaders(os.Getenv("ACCESS_LOG_HTTP_HEADERS"))
+		if err != nil {
+			return nil, err
+		}
+		// บรรทัดแบบ text ไม่ใช่ JSON จึงห้ามประกาศว่าเป็น NDJSON ให้ collector parse ผิด
+		contentType := ContentTypeText
+		if strings.EqualFold(config.String("ACCESS_LOG_FORMAT", "text"), "json") {
+			contentType = ContentTypeNDJSON
+		}
+		return NewHTTPWriter(HTTPConfig{
+			URL:           os.Getenv("ACCESS_LOG_HTTP_URL"),
+			Headers:       headers,
+			ContentType:   contentType,
+			BatchSize:     config.Int("ACCESS_LOG_HTTP_BATCH_SIZE", 500),
+			FlushInterval: config.Duration("ACCESS_LOG_HTTP_FLUSH_INTERVAL", 5*time.Second),
+			Timeout:       config.Duration("ACCESS_LOG_HTTP_TIMEOUT", 5*time.Second),
+			MaxRetries:    config.Int("ACCESS_LOG_HTTP_MAX_RETRIES", 3),
+			OnError:       onError,
+		})
+	default:
+		return nil, fmt.Errorf("access log sink %q: want file, stdout, syslog or http", sink)
+	}
+}
+
+// parseHeaders อ่าน "Name=Value;Name2=Value2"
+func parseHeaders(spec string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(spec, ";") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, value, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("access log collector header %q: want Name=Value", pair)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
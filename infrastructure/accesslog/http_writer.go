@@ -0,0 +1,249 @@
+package accesslog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Content-Type ของ body ที่ HTTPWriter ส่ง: บรรทัด JSON (ACCESS_LOG_FORMAT=json) หรือข้อความธรรมดา (text)
+const (
+	ContentTypeNDJSON = "application/x-ndjson"
+	ContentTypeText   = "text/plain; charset=utf-8"
+)
+
+// HTTPConfig = collector ที่รับ access log เป็นชุดทาง HTTP POST
+type HTTPConfig struct {
+	URL           string
+	Headers       map[string]string // เช่น Authorization ของ collector
+	ContentType   string            // ชนิดของ body ตามรูปแบบบรรทัด (ค่าเริ่มต้น application/x-ndjson)
+	BatchSize     int               // ส่งทันทีเมื่อสะสมครบเท่านี้ (ค่าเริ่มต้น 500)
+	FlushInterval time.Duration     // ส่งบรรทัดที่ค้างอย่างน้อยทุกช่วงนี้ (ค่าเริ่มต้น 5s)
+	Timeout       time.Duration     // timeout ต่อหนึ่งครั้งที่ POST (ค่าเริ่มต้น 5s)
+	MaxRetries    int               // ลองใหม่เมื่อ network error / 429 / 5xx (ค่าเริ่มต้น 3)
+	MaxPending    int               // จำนวนชุดที่รอส่งได้ เกินนี้ทิ้งทั้งชุด (ค่าเริ่มต้น 8)
+	OnError       func(error)       // error จากการส่งเบื้องหลัง (รวมถึงชุดที่ถูกทิ้ง)
+	Client        *http.Client      // nil = client ใหม่ที่ใช้ Timeout
+}
+
+// HTTPWriter สะสมบรรทัดแล้ว POST เป็นชุด (หนึ่งบรรทัดต่อหนึ่ง entry) ไปที่ collector จาก goroutine เบื้องหลัง
+// WriteLine ไม่รอ network; collector ล่มนานจนคิวเต็ม = ทิ้งชุดเก่าแล้วแจ้งผ่าน OnError
+type HTTPWriter struct {
+	config  HTTPConfig
+	client  *http.Client
+	batches chan []string
+	done    chan struct{}
+	stop    chan struct{}
+	// abort ถูกยกเลิกเมื่อ CloseContext หมดเวลา: ตัด POST ที่ค้างและการรอ retry ทันที
+	abort       context.Context
+	cancelAbort context.CancelFunc
+
+	mutex   sync.Mutex
+	pending []string
+	closed  bool
+}
+
+// NewHTTPWriter เริ่ม goroutine ส่งข้อมูล (ต้องเรียก Close ตอนปิดโปรแกรมเพื่อส่งบรรทัดที่ค้าง)
+func NewHTTPWriter(config HTTPConfig) (*HTTPWriter, error) {
+	if !strings.HasPrefix(config.URL, "http://") && !strings.HasPrefix(config.URL, "https://") {
+		return nil, fmt.Errorf("access log collector url %q: want http:// or https://", config.URL)
+	}
+	if config.ContentType == "" {
+		config.ContentType = ContentTypeNDJSON
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 500
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 5 * time.Second
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = 3
+	}
+	if config.MaxPending <= 0 {
+		config.MaxPending = 8
+	}
+	client := config.Client
+	if client == nil {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+	writer := &HTTPWriter{
+		config:  config,
+		client:  client,
+		batches: make(chan []string, config.MaxPending),
+		done:    make(chan struct{}),
+		stop:    make(chan struct{}),
+	}
+	writer.abort, writer.cancelAbort = context.WithCancel(context.Background())
+	go writer.run()
+	return writer, nil
+}
+
+func (writer *HTTPWriter) WriteLine(_ time.Time, line string) error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	if writer.closed {
+		return errors.New("access log collector writer is closed")
+	}
+	writer.pending = append(writer.pending, line)
+	if len(writer.pending) >= writer.config.BatchSize {
+		writer.enqueueLocked()
+	}
+	return nil
+}
+
+func (writer *HTTPWriter) WriteLines(lines []Line) error {
+	for _, line := range lines {
+		if err := writer.WriteLine(line.Time, line.Text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueueLocked ย้ายบรรทัดที่สะสมไว้เข้าคิวส่ง (ต้องถือ mutex)
+func (writer *HTTPWriter) enqueueLocked() {
+	if len(writer.pending) == 0 {
+		return
+	}
+	batch := writer.pending
+	writer.pending = nil
+	select {
+	case writer.batches <- batch:
+	default:
+		writer.reportError(fmt.Errorf("access log collector queue full: dropped %d lines", len(batch)))
+	}
+}
+
+// run ส่งชุดตามลำดับ และดันบรรทัดที่ค้างเข้าคิวทุก FlushInterval
+func (writer *HTTPWriter) run() {
+	defer close(writer.done)
+	ticker := time.NewTicker(writer.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case batch := <-writer.batches:
+			writer.send(batch)
+		case <-ticker.C:
+			writer.mutex.Lock()
+			writer.enqueueLocked()
+			writer.mutex.Unlock()
+		case <-writer.stop:
+			for {
+				select {
+				case batch := <-writer.batches:
+					writer.send(batch)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// send POST หนึ่งชุด ลองใหม่แบบรอนานขึ้นเรื่อย ๆ เมื่อ collector ยังไม่พร้อม
+func (writer *HTTPWriter) send(batch []string) {
+	body := []byte(strings.Join(batch, "\n") + "\n")
+	var err error
+	for attempt := 0; attempt <= writer.config.MaxRetries; attempt++ {
+		if attempt > 0 && !writer.wait(time.Duration(attempt)*200*time.Millisecond) {
+			break
+		}
+		var retry bool
+		if retry, err = writer.post(body); err == nil || !retry {
+			break
+		}
+	}
+	if err != nil {
+		writer.reportError(fmt.Errorf("access log collector: dropped %d lines: %w", len(batch), err))
+	}
+}
+
+// wait รอก่อน retry; คืน false ถ้าถูก abort ระหว่างรอ (ปิดโปรแกรมเกินกำหนด)
+func (writer *HTTPWriter) wait(delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-writer.abort.Done():
+		return false
+	}
+}
+
+// post คืน retry = true เมื่อควรลองใหม่ (network error, 429, 5xx)
+func (writer *HTTPWriter) post(body []byte) (retry bool, err error) {
+	ctx, cancel := context.WithTimeout(writer.abort, writer.config.Timeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, writer.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Content-Type", writer.config.ContentType)
+	for name, value := range writer.config.Headers {
+		request.Header.Set(name, value)
+	}
+	response, err := writer.client.Do(request)
+	if err != nil {
+		return true, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return false, nil
+	}
+	retry = response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500
+	return retry, fmt.Errorf("collector responded %s", response.Status)
+}
+
+func (writer *HTTPWriter) reportError(err error) {
+	if writer.config.OnError != nil {
+		writer.config.OnError(err)
+	}
+}
+
+// Close ส่งบรรทัดที่ค้างทั้งหมดแล้วรอจนส่งเสร็จ (collector ล่มอาจรอนานตาม retry; ตอนปิดโปรแกรมใช้ CloseContext)
+func (writer *HTTPWriter) Close() error {
+	return writer.CloseContext(context.Background())
+}
+
+// CloseContext ส่งบรรทัดที่ค้างแล้วรอจนส่งเสร็จหรือ ctx หมดเวลา
+// หมดเวลา = ยกเลิก POST/retry ที่ค้าง ทิ้งชุดที่เหลือ (แจ้งผ่าน OnError) แล้วคืน error
+func (writer *HTTPWriter) CloseContext(ctx context.Context) error {
+	writer.mutex.Lock()
+	if writer.closed {
+		writer.mutex.Unlock()
+		return nil
+	}
+	writer.closed = true
+	batch := writer.pending
+	writer.pending = nil
+	writer.mutex.Unlock()
+
+	if len(batch) > 0 {
+		select {
+		case writer.batches <- batch:
+		case <-ctx.Done():
+			writer.reportError(fmt.Errorf("access log collector: dropped %d lines at shutdown", len(batch)))
+		}
+	}
+	close(writer.stop)
+	defer writer.cancelAbort()
+	select {
+	case <-writer.done:
+		return nil
+	case <-ctx.Done():
+		writer.cancelAbort()
+		<-writer.done
+		return fmt.Errorf("access log collector: close: %w", ctx.Err())
+	}
+}
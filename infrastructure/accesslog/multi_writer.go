@@ -0,0 +1,67 @@
+package accesslog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+)
+
+// MultiWriter ส่งทุกบรรทัดไปทุกปลายทาง ปลายทางหนึ่งล้มเหลวไม่กระทบตัวอื่น (error รวมด้วย errors.Join)
+type MultiWriter struct {
+	writers []interfaces.AccessLogWriter
+}
+
+func NewMultiWriter(writers ...interfaces.AccessLogWriter) *MultiWriter {
+	return &MultiWriter{writers: writers}
+}
+
+func (multi *MultiWriter) WriteLine(now time.Time, line string) error {
+	var errs []error
+	for _, writer := range multi.writers {
+		errs = append(errs, writer.WriteLine(now, line))
+	}
+	return errors.Join(errs...)
+}
+
+// WriteLines ส่งทั้งชุดให้ปลายทางที่รับเป็นชุดได้ ที่เหลือเขียนทีละบรรทัด
+func (multi *MultiWriter) WriteLines(lines []Line) error {
+	var errs []error
+	for _, writer := range multi.writers {
+		if batch, ok := writer.(batchWriter); ok {
+			errs = append(errs, batch.WriteLines(lines))
+			continue
+		}
+		for _, line := range lines {
+			errs = append(errs, writer.WriteLine(line.Time, line.Text))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (multi *MultiWriter) Close() error {
+	return multi.CloseContext(context.Background())
+}
+
+// CloseContext ปิดทุกปลายทางตามลำดับภายใน ctx เดียวกัน
+func (multi *MultiWriter) CloseContext(ctx context.Context) error {
+	var errs []error
+	for _, writer := range multi.writers {
+		errs = append(errs, CloseContext(ctx, writer))
+	}
+	return errors.Join(errs...)
+}
+
+// contextCloser = writer ที่จำกัดเวลาปิดได้ (ปลายทาง network ที่อาจรอ collector)
+type contextCloser interface {
+	CloseContext(ctx context.Context) error
+}
+
+// CloseContext ปิด writer ภายในเวลาของ ctx ถ้า writer รองรับ ไม่งั้นเรียก Close ตามปกติ
+func CloseContext(ctx context.Context, writer interfaces.AccessLogWriter) error {
+	if closer, ok := writer.(contextCloser); ok {
+		return closer.CloseContext(ctx)
+	}
+	return writer.Close()
+}
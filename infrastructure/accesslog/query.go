@@ -0,0 +1,386 @@
+package accesslog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record = access log หนึ่งบรรทัดที่อ่านกลับมาแล้ว (จากรูปแบบ text หรือ JSON Lines)
+type Record struct {
+	Time         time.Time
+	Level        string
+	Module       string
+	RequestID    string
+	Status       int
+	Method       string
+	Route        string
+	Path         string
+	IP           string
+	Latency      time.Duration
+	RequestBody  string
+	ResponseBody string
+	File         string // ไฟล์ที่อ่านบรรทัดนี้มา
+}
+
+// RouteOrPath = route ของ handler หรือ path จริงเมื่อไม่มี route (เช่น 404)
+func (record Record) RouteOrPath() string {
+	if record.Route != "" {
+		return record.Route
+	}
+	return record.Path
+}
+
+// textLinePattern = หัวบรรทัดแบบ text ก่อน headers/req/res (request_id, trace_id, headers ไม่มีในบรรทัดรุ่นเก่า)
+var textLinePattern = regexp.MustCompile(
+	`^(\d{4}-\d\d-\d\d \d\d:\d\d:\d\d\.\d{3}) \[([^\]]*)\] \[([^\]]*)\] (?:request_id=(\S*) )?status=(\d+) method=(\S*) route=(\S*) ip=(\S*) latency=(\S+)( trace_id=\S* span_id=\S*)?`)
+
+// ParseLine อ่านหนึ่งบรรทัดได้ทั้งแบบ JSON Lines และแบบ text (เวลาแบบ text ถือเป็นเวลาท้องถิ่น)
+func ParseLine(line string) (Record, error) {
+	if strings.HasPrefix(line, "{") {
+		return parseJSONLine(line)
+	}
+	return parseTextLine(line)
+}
+
+func parseJSONLine(line string) (Record, error) {
+	var fields struct {
+		Time      time.Time       `json:"time"`
+		Level     string          `json:"level"`
+		Module    string          `json:"module"`
+		RequestID string          `json:"request_id"`
+		Status    int             `json:"status"`
+		Method    string          `json:"method"`
+		Route     string          `json:"route"`
+		Path      string          `json:"path"`
+		IP        string          `json:"ip"`
+		LatencyMS float64         `json:"latency_ms"`
+		Request   json.RawMessage `json:"req"`
+		Response  json.RawMessage `json:"res"`
+	}
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return Record{}, fmt.Errorf("parse access log line: %w", err)
+	}
+	return Record{
+		Time: fields.Time, Level: fields.Level, Module: fields.Module, RequestID: fields.RequestID,
+		Status: fields.Status, Method: fields.Method, Route: fields.Route, Path: fields.Path, IP: fields.IP,
+		Latency:      time.Duration(fields.LatencyMS * float64(time.Millisecond)),
+		RequestBody:  rawBodyText(fields.Request),
+		ResponseBody: rawBodyText(fields.Response),
+	}, nil
+}
+
+// rawBodyText: body ที่เป็น string คืนข้อความ, body ที่เป็น JSON คืนตามที่เขียนไว้
+func rawBodyText(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	return string(raw)
+}
+
+func parseTextLine(line string) (Record, error) {
+	match := textLinePattern.FindStringSubmatch(line)
+	if match == nil {
+		return Record{}, errors.New("parse access log line: unknown format")
+	}
+	recordTime, err := time.ParseInLocation("2006-01-02 15:04:05.000", match[1], time.Local)
+	if err != nil {
+		return Record{}, fmt.Errorf("parse access log line: %w", err)
+	}
+	status, _ := strconv.Atoi(match[5])
+	latency, err := time.ParseDuration(match[9])
+	if err != nil {
+		return Record{}, fmt.Errorf("parse access log line: %w", err)
+	}
+	record := Record{
+		Time: recordTime, Module: match[2], Level: match[3], RequestID: match[4],
+		Status: status, Method: match[6], Route: match[7], IP: match[8], Latency: latency,
+	}
+
+	// ส่วนท้าย: [ headers={...}] req=... res=... (headers เป็น JSON ข้าม object ทั้งก้อนก่อนหา req=)
+	rest := line[len(match[0]):]
+	if after, found := strings.CutPrefix(rest, " headers="); found {
+		decoder := json.NewDecoder(strings.NewReader(after))
+		var headers json.RawMessage
+		if decoder.Decode(&headers) == nil {
+			rest = after[decoder.InputOffset():]
+		}
+	}
+	if after, found := strings.CutPrefix(rest, " req="); found {
+		record.RequestBody, record.ResponseBody, _ = strings.Cut(after, " res=")
+	}
+	return record, nil
+}
+
+// StatusFilter = ชุดช่วงของ status เช่น "5xx", "404", "400-499"
+type StatusFilter [][2]int
+
+// ParseStatusFilter อ่าน spec คั่นด้วย , (ว่าง = ทุก status)
+func ParseStatusFilter(spec string) (StatusFilter, error) {
+	var filter StatusFilter
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		if class, found := strings.CutSuffix(part, "xx"); found && len(class) == 1 && class[0] >= '1' && class[0] <= '5' {
+			low := int(class[0]-'0') * 100
+			filter = append(filter, [2]int{low, low + 99})
+			continue
+		}
+		lowText, highText, isRange := strings.Cut(part, "-")
+		if !isRange {
+			highText = lowText
+		}
+		low, lowError := strconv.Atoi(lowText)
+		high, highError := strconv.Atoi(highText)
+		if lowError != nil || highError != nil || low > high {
+			return nil, fmt.Errorf("status filter %q: want e.g. 404, 5xx or 400-499", part)
+		}
+		filter = append(filter, [2]int{low, high})
+	}
+	return filter, nil
+}
+
+func (filter StatusFilter) matches(status int) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, bounds := range filter {
+		if status >= bounds[0] && status <= bounds[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter = เงื่อนไขการค้น ฟิลด์ที่ว่างไม่กรอง; ในแต่ละรายการ (เช่น Methods) ตรงตัวใดตัวหนึ่งก็ผ่าน
+type Filter struct {
+	From, To     time.Time // ช่วงเวลา [From, To) (zero = ไม่จำกัด)
+	Statuses     StatusFilter
+	Methods      []string // ไม่สนตัวพิมพ์
+	Routes       []string // ตรงตัวหรือ pattern แบบ path.Match เช่น /api/v1/books/*
+	Modules      []string
+	IPs          []string
+	MinLatency   time.Duration
+	BodyContains string // หาใน req หรือ res
+}
+
+// Matches = record ผ่านทุกเงื่อนไข
+func (filter Filter) Matches(record Record) bool {
+	switch {
+	case !filter.From.IsZero() && record.Time.Before(filter.From),
+		!filter.To.IsZero() && !record.Time.Before(filter.To),
+		!filter.Statuses.matches(record.Status),
+		len(filter.Methods) > 0 && !slices.ContainsFunc(filter.Methods, func(method string) bool {
+			return strings.EqualFold(method, record.Method)
+		}),
+		len(filter.Routes) > 0 && !slices.ContainsFunc(filter.Routes, func(pattern string) bool {
+			matched, _ := path.Match(pattern, record.RouteOrPath())
+			return matched || pattern == record.RouteOrPath()
+		}),
+		len(filter.Modules) > 0 && !slices.Contains(filter.Modules, record.Module),
+		len(filter.IPs) > 0 && !slices.Contains(filter.IPs, record.IP),
+		record.Latency < filter.MinLatency,
+		filter.BodyContains != "" && !strings.Contains(record.RequestBody, filter.BodyContains) &&
+			!strings.Contains(record.ResponseBody, filter.BodyContains):
+		return false
+	}
+	return true
+}
+
+// logFileNamePattern = log_YYYY-MM-DD_HH-mm[_N].log[.gz]
+var logFileNamePattern = regexp.MustCompile(`^log_(\d{4}-\d\d-\d\d_\d\d-\d\d)(?:_(\d+))?\.log(?:\.gz)?$`)
+
+type queryFile struct {
+	path   string
+	bucket time.Time
+	index  int
+}
+
+// FindFiles คืนไฟล์ access log (ทั้ง .log และ .log.gz) ใต้ directory ที่อาจมีบรรทัดในช่วง [from, to) เรียงตามเวลา
+// ไฟล์ที่เริ่มหลัง to ตัดทิ้ง ส่วนไฟล์ก่อน from ตัดที่ระดับวัน (ไม่รู้ว่าช่วงของไฟล์ยาวเท่าไร)
+func FindFiles(directory string, from, to time.Time) ([]string, error) {
+	dates, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+	var files []queryFile
+	for _, date := range dates {
+		day, err := time.ParseInLocation(dateLayout, date.Name(), time.Local)
+		if err != nil || !date.IsDir() ||
+			(!from.IsZero() && !day.AddDate(0, 0, 1).After(from)) || (!to.IsZero() && !day.Before(to)) {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(directory, date.Name()))
+		if err != nil {
+			return nil, err
+		}
+		names := map[string]bool{}
+		for _, entry := range entries {
+			names[entry.Name()] = true
+		}
+		for _, entry := range entries {
+			match := logFileNamePattern.FindStringSubmatch(entry.Name())
+			// ระหว่างบีบอัดอาจมีทั้ง .log และ .log.gz ชั่วครู่: อ่านเฉพาะ .gz ที่เสร็จแล้ว
+			if match == nil || entry.IsDir() || names[entry.Name()+compressedSuffix] {
+				continue
+			}
+			bucket, err := time.ParseInLocation(bucketLayout, match[1], time.Local)
+			if err != nil || (!to.IsZero() && !bucket.Before(to)) {
+				continue
+			}
+			index, _ := strconv.Atoi(match[2])
+			files = append(files, queryFile{path: filepath.Join(directory, date.Name(), entry.Name()), bucket: bucket, index: index})
+		}
+	}
+	slices.SortFunc(files, func(a, b queryFile) int {
+		if compared := a.bucket.Compare(b.bucket); compared != 0 {
+			return compared
+		}
+		return a.index - b.index
+	})
+	paths := make([]string, len(files))
+	for index, file := range files {
+		paths[index] = file.path
+	}
+	return paths, nil
+}
+
+// maxLineBytes = บรรทัดยาวสุดที่อ่านได้ (body ทั้งสองฝั่ง + headers)
+const maxLineBytes = 8 << 20
+
+// Scan อ่านทุกไฟล์ในช่วงของ filter ตามลำดับเวลา ส่ง record ที่ผ่านเงื่อนไขให้ visit
+// บรรทัดที่อ่านไม่ออกข้ามไป (คืนจำนวนไว้); visit คืน error = หยุดค้น (เช่น ครบจำนวนที่ต้องการ)
+func Scan(directory string, filter Filter, visit func(Record) error) (skipped int, err error) {
+	paths, err := FindFiles(directory, filter.From, filter.To)
+	if err != nil {
+		return 0, err
+	}
+	for _, filePath := range paths {
+		fileSkipped, err := scanFile(filePath, filter, visit)
+		skipped += fileSkipped
+		if err != nil {
+			return skipped, err
+		}
+	}
+	return skipped, nil
+}
+
+func scanFile(filePath string, filter Filter, visit func(Record) error) (skipped int, err error) {
+	file, err := os.Open(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil // ถูกบีบอัดหรือลบระหว่างค้น
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(filePath, compressedSuffix) {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", filePath, err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64<<10), maxLineBytes)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record, err := ParseLine(scanner.Text())
+		if err != nil {
+			skipped++
+			continue
+		}
+		if !filter.Matches(record) {
+			continue
+		}
+		record.File = filePath
+		if err := visit(record); err != nil {
+			return skipped, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return skipped, fmt.Errorf("%s: %w", filePath, err)
+	}
+	return skipped, nil
+}
+
+// RouteSummary = จำนวน request และ latency percentile ของหนึ่ง method + route
+type RouteSummary struct {
+	Method        string
+	Route         string
+	Count         int
+	StatusClasses [6]int // [2] = 2xx, [4] = 4xx, [5] = 5xx ...
+	P50, P95, P99 time.Duration
+	latencies     []time.Duration
+}
+
+// Summary สะสม RouteSummary ทีละ record
+type Summary struct {
+	routes map[[2]string]*RouteSummary
+}
+
+func NewSummary() *Summary {
+	return &Summary{routes: map[[2]string]*RouteSummary{}}
+}
+
+func (summary *Summary) Add(record Record) {
+	key := [2]string{record.Method, record.RouteOrPath()}
+	route, found := summary.routes[key]
+	if !found {
+		route = &RouteSummary{Method: key[0], Route: key[1]}
+		summary.routes[key] = route
+	}
+	route.Count++
+	if class := record.Status / 100; class >= 1 && class <= 5 {
+		route.StatusClasses[class]++
+	}
+	route.latencies = append(route.latencies, record.Latency)
+}
+
+// Routes คืนผลต่อ route เรียงจาก request มากไปน้อย (percentile แบบ nearest-rank)
+func (summary *Summary) Routes() []RouteSummary {
+	routes := make([]RouteSummary, 0, len(summary.routes))
+	for _, route := range summary.routes {
+		latencies := slices.Clone(route.latencies)
+		slices.Sort(latencies)
+		result := *route
+		result.latencies = nil
+		result.P50, result.P95, result.P99 = percentile(latencies, 50), percentile(latencies, 95), percentile(latencies, 99)
+		routes = append(routes, result)
+	}
+	slices.SortFunc(routes, func(a, b RouteSummary) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Method+" "+a.Route, b.Method+" "+b.Route)
+	})
+	return routes
+}
+
+func percentile(sorted []time.Duration, rank int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := (rank*len(sorted)+99)/100 - 1
+	return sorted[max(index, 0)]
+}
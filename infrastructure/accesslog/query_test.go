@@ -0,0 +1,155 @@
+package accesslog_test
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/accesslog"
+)
+
+const (
+	textLine = `2025-08-09 05:01:14.533 [books] [info] request_id=01JTEXT status=201 method=POST route=/api/v1/books ip=10.0.0.1 ` +
+		`latency=1.5ms trace_id=abc span_id=def headers={"Content-Type":"application/json","X-Note":"a req= b"} ` +
+		`req={"title":"Go"} res={"id":7,"title":"Go"}`
+	jsonLine = `{"time":"2025-08-09T05:02:00Z","level":"warn","module":"-","request_id":"01JJSON","status":404,` +
+		`"method":"GET","path":"/nope","ip":"10.0.0.2","latency_ms":0.25,"res":"not found"}`
+)
+
+func TestParseLineText(t *testing.T) {
+	record, err := accesslog.ParseLine(textLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2025, 8, 9, 5, 1, 14, 533_000_000, time.Local)
+	if !record.Time.Equal(want) || record.Module != "books" || record.Level != "info" || record.Status != 201 ||
+		record.Method != "POST" || record.Route != "/api/v1/books" || record.IP != "10.0.0.1" ||
+		record.Latency != 1500*time.Microsecond {
+		t.Fatalf("record = %+v", record)
+	}
+	if record.RequestBody != `{"title":"Go"}` || record.ResponseBody != `{"id":7,"title":"Go"}` {
+		t.Fatalf("bodies = %q / %q", record.RequestBody, record.ResponseBody)
+	}
+}
+
+// บรรทัดจากรุ่นแรกสุด ไม่มี request_id/trace_id/headers
+func TestParseLineBaselineText(t *testing.T) {
+	record, err := accesslog.ParseLine(`2025-08-01 09:15:02.118 [books] [info] status=200 method=GET route=/api/v1/books ip=10.0.0.3 ` +
+		`latency=812.4µs req= res=[{"id":1}]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.RequestID != "" || record.Status != 200 || record.Method != "GET" || record.Route != "/api/v1/books" ||
+		record.IP != "10.0.0.3" || record.Latency != 812400*time.Nanosecond || record.ResponseBody != `[{"id":1}]` {
+		t.Fatalf("record = %+v", record)
+	}
+}
+
+func TestParseLineJSON(t *testing.T) {
+	record, err := accesslog.ParseLine(jsonLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Status != 404 || record.RouteOrPath() != "/nope" || record.Latency != 250*time.Microsecond ||
+		record.ResponseBody != "not found" {
+		t.Fatalf("record = %+v", record)
+	}
+	if _, err := accesslog.ParseLine("garbage"); err == nil {
+		t.Fatal("garbage parsed")
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	record, _ := accesslog.ParseLine(textLine)
+	statuses, err := accesslog.ParseStatusFilter("2xx, 404")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		filter accesslog.Filter
+		want   bool
+	}{
+		{accesslog.Filter{Statuses: statuses, Methods: []string{"post"}, Routes: []string{"/api/v1/*"}}, true},
+		{accesslog.Filter{Modules: []string{"books"}, IPs: []string{"10.0.0.1"}, BodyContains: `"id":7`}, true},
+		{accesslog.Filter{From: record.Time, To: record.Time.Add(time.Millisecond)}, true},
+		{accesslog.Filter{To: record.Time}, false},
+		{accesslog.Filter{MinLatency: 2 * time.Millisecond}, false},
+		{accesslog.Filter{Routes: []string{"/api/v1/books/*"}}, false},
+		{accesslog.Filter{BodyContains: "Rust"}, false},
+	}
+	for index, testCase := range cases {
+		if got := testCase.filter.Matches(record); got != testCase.want {
+			t.Errorf("case %d: Matches = %v, want %v", index, got, testCase.want)
+		}
+	}
+	if _, err := accesslog.ParseStatusFilter("5x"); err == nil {
+		t.Fatal("bad status spec accepted")
+	}
+}
+
+func writeLogFile(t *testing.T, filePath string, lines ...string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := []byte(strings.Join(lines, "\n") + "\n")
+	if strings.HasSuffix(filePath, ".gz") {
+		file, err := os.Create(filePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gzipWriter := gzip.NewWriter(file)
+		_, _ = gzipWriter.Write(content)
+		_ = gzipWriter.Close()
+		_ = file.Close()
+		return
+	}
+	if err := os.WriteFile(filePath, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func textLineAt(at time.Time, status int, route string, latency time.Duration) string {
+	return at.Format("2006-01-02 15:04:05.000") + " [books] [info] request_id=01J status=" + strconv.Itoa(status) +
+		" method=GET route=" + route + " ip=127.0.0.1 latency=" + latency.String() + " headers={} req= res="
+}
+
+func TestScanReadsPlainAndCompressedFilesInOrder(t *testing.T) {
+	directory := t.TempDir()
+	day := time.Date(2025, 8, 9, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	writeLogFile(t, filepath.Join(directory, "2025-08-09", "log_2025-08-09_05-10.log"), textLineAt(at(5, 11), 200, "/b", time.Millisecond))
+	writeLogFile(t, filepath.Join(directory, "2025-08-09", "log_2025-08-09_05-00.log.gz"), textLineAt(at(5, 1), 200, "/a", time.Millisecond))
+	writeLogFile(t, filepath.Join(directory, "2025-08-09", "log_2025-08-09_05-00_1.log"), textLineAt(at(5, 2), 500, "/a", 3*time.Millisecond), "not a log line")
+	writeLogFile(t, filepath.Join(directory, "2025-08-09", "log_2025-08-09_06-00.log"), textLineAt(at(6, 1), 200, "/c", time.Millisecond))
+	writeLogFile(t, filepath.Join(directory, "2025-08-08", "log_2025-08-08_23-50.log"), textLineAt(day.Add(-5*time.Minute), 200, "/old", time.Millisecond))
+	// .log ที่ค้างคู่กับ .gz ระหว่างบีบอัด ต้องไม่ถูกนับซ้ำ
+	writeLogFile(t, filepath.Join(directory, "2025-08-09", "log_2025-08-09_05-00.log"), textLineAt(at(5, 1), 200, "/a", time.Millisecond))
+
+	var routes []string
+	summary := accesslog.NewSummary()
+	skipped, err := accesslog.Scan(directory, accesslog.Filter{From: at(5, 0), To: at(6, 0)}, func(record accesslog.Record) error {
+		routes = append(routes, record.Route)
+		summary.Add(record)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(routes, []string{"/a", "/a", "/b"}) || skipped != 1 {
+		t.Fatalf("routes = %v, skipped = %d; want /a (gz), /a (_1), /b and one skipped line", routes, skipped)
+	}
+
+	top := summary.Routes()[0]
+	if top.Route != "/a" || top.Count != 2 || top.StatusClasses[5] != 1 || top.P50 != time.Millisecond || top.P99 != 3*time.Millisecond {
+		t.Fatalf("summary = %+v", top)
+	}
+}
@@ -0,0 +1,421 @@
+// Package accesslog = ปลายทางของ access log สำหรับ interfaces.AccessLogWriter
+package accesslog
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	filePrefix       = "log_"
+	fileSuffix       = ".log"
+	compressedSuffix = ".gz"
+	dateLayout       = "2006-01-02"
+	bucketLayout     = "2006-01-02_15-04"
+)
+
+// Config = การหมุนไฟล์ การบีบอัด และการเก็บรักษา
+type Config struct {
+	// Directory = โฟลเดอร์หลัก (ไฟล์อยู่ที่ <Directory>/YYYY-MM-DD/log_YYYY-MM-DD_HH-mm.log)
+	Directory string
+	// Interval = ขึ้นไฟล์ใหม่ทุกช่วงเวลานี้ นับจากเที่ยงคืน (0 หรือเกิน 24h = วันละไฟล์)
+	Interval time.Duration
+	// MaxSize = ขนาดสูงสุดต่อไฟล์ (ไบต์) เกินแล้วขึ้นไฟล์ใหม่ในช่วงเดิม log_..._1.log, _2 ... (0 = ไม่จำกัด)
+	MaxSize int64
+	// Compress = บีบไฟล์ที่ปิดแล้วเป็น .log.gz
+	Compress bool
+	// MaxAge = ลบไฟล์ที่แก้ไขล่าสุดเก่ากว่านี้ (0 = ไม่ลบตามอายุ)
+	MaxAge time.Duration
+	// MaxTotalSize = ขนาดรวมสูงสุดของทุกไฟล์ (ไบต์) เกินแล้วลบไฟล์เก่าสุดก่อน (0 = ไม่จำกัด)
+	MaxTotalSize int64
+	// CleanupInterval = ความถี่ของ janitor ที่บีบอัดไฟล์ค้างและลบไฟล์ตาม MaxAge/MaxTotalSize
+	CleanupInterval time.Duration
+	// FileMode / DirMode = สิทธิ์ของไฟล์และโฟลเดอร์ที่สร้าง
+	FileMode os.FileMode
+	DirMode  os.FileMode
+	// OnError รับ error ของงานเบื้องหลัง (บีบอัด/ลบไฟล์) ไม่ใส่ = เงียบ
+	OnError func(error)
+}
+
+// RotatingFile เขียน access log ลงไฟล์ที่หมุนตามเวลาและ/หรือขนาด พร้อม janitor เบื้องหลัง
+type RotatingFile struct {
+	config Config
+
+	mutex       sync.Mutex
+	file        *os.File
+	path        string
+	bucket      time.Time
+	index       int
+	size        int64
+	compressing map[string]bool
+
+	background sync.WaitGroup
+	stop       chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewRotatingFile สร้างโฟลเดอร์หลักและเริ่ม janitor (ต้องเรียก Close ตอนปิดโปรแกรม)
+func NewRotatingFile(config Config) (*RotatingFile, error) {
+	if config.Directory == "" {
+		config.Directory = "logs"
+	}
+	if config.Interval <= 0 || config.Interval > 24*time.Hour {
+		config.Interval = 24 * time.Hour
+	}
+	if config.FileMode == 0 {
+		config.FileMode = 0o644
+	}
+	if config.DirMode == 0 {
+		config.DirMode = 0o755
+	}
+	if config.CleanupInterval <= 0 {
+		config.CleanupInterval = 10 * time.Minute
+	}
+	if err := os.MkdirAll(config.Directory, config.DirMode); err != nil {
+		return nil, fmt.Errorf("access log directory: %w", err)
+	}
+
+	rotatingFile := &RotatingFile{config: config, compressing: make(map[string]bool), stop: make(chan struct{})}
+	if config.Compress || config.MaxAge > 0 || config.MaxTotalSize > 0 {
+		rotatingFile.background.Add(1)
+		go rotatingFile.runJanitor()
+	}
+	return rotatingFile, nil
+}
+
+// bucketStart = จุดเริ่มของช่วงเวลาที่ now อยู่ (นับจากเที่ยงคืนตามเวลาท้องถิ่นของ now)
+func (rotatingFile *RotatingFile) bucketStart(now time.Time) time.Time {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	interval := rotatingFile.config.Interval
+	return midnight.Add(now.Sub(midnight) / interval * interval)
+}
+
+// WriteLine เขียนหนึ่งบรรทัด ขึ้นไฟล์ใหม่เมื่อเข้าช่วงเวลาใหม่หรือไฟล์เต็ม
+func (rotatingFile *RotatingFile) WriteLine(now time.Time, line string) error {
+	rotatingFile.mutex.Lock()
+	defer rotatingFile.mutex.Unlock()
+
+	if rotatingFile.needsRotation(now, int64(len(line)+1)) {
+		if err := rotatingFile.rotate(now); err != nil {
+			return err
+		}
+	}
+	written, err := rotatingFile.file.WriteString(line + "\n")
+	rotatingFile.size += int64(written)
+	return err
+}
+
+// needsRotation = ยังไม่มีไฟล์ เข้าช่วงเวลาใหม่ หรือเขียนอีก pendingSize ไบต์แล้วเกิน MaxSize; เรียกขณะถือ mutex
+func (rotatingFile *RotatingFile) needsRotation(now time.Time, pendingSize int64) bool {
+	if rotatingFile.file == nil || !rotatingFile.bucketStart(now).Equal(rotatingFile.bucket) {
+		return true
+	}
+	maxSize := rotatingFile.config.MaxSize
+	return maxSize > 0 && rotatingFile.size > 0 && rotatingFile.size+pendingSize > maxSize
+}
+
+// rotate เปิดไฟล์ใหม่: ช่วงเวลาใหม่เริ่มที่ลำดับ 0, ช่วงเดิม (ไฟล์เต็ม) ใช้ลำดับถัดไป; เรียกขณะถือ mutex
+func (rotatingFile *RotatingFile) rotate(now time.Time) error {
+	bucket := rotatingFile.bucketStart(now)
+	if rotatingFile.file != nil && bucket.Equal(rotatingFile.bucket) {
+		return rotatingFile.openBucket(bucket, rotatingFile.index+1)
+	}
+	return rotatingFile.openBucket(bucket, 0)
+}
+
+// Line = หนึ่งบรรทัดพร้อมเวลา ใช้กับ WriteLines
+type Line struct {
+	Time time.Time
+	Text string
+}
+
+// WriteLines เขียนหลายบรรทัดโดยถือ mutex ครั้งเดียว บรรทัดที่ลงไฟล์เดียวกันรวมเป็น write ครั้งเดียว
+// (ใช้กับ AsyncWriter ที่ส่งมาเป็นชุด)
+func (rotatingFile *RotatingFile) WriteLines(lines []Line) error {
+	rotatingFile.mutex.Lock()
+	defer rotatingFile.mutex.Unlock()
+
+	var pending strings.Builder
+	var errs []error
+	flushPending := func() {
+		if pending.Len() == 0 {
+			return
+		}
+		written, err := rotatingFile.file.WriteString(pending.String())
+		rotatingFile.size += int64(written)
+		errs = append(errs, err)
+		pending.Reset()
+	}
+	for _, line := range lines {
+		if rotatingFile.needsRotation(line.Time, int64(pending.Len()+len(line.Text)+1)) {
+			flushPending()
+			if err := rotatingFile.rotate(line.Time); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		pending.WriteString(line.Text)
+		pending.WriteByte('\n')
+	}
+	if rotatingFile.file != nil {
+		flushPending()
+	}
+	return errors.Join(errs...)
+}
+
+func (rotatingFile *RotatingFile) fileName(bucket time.Time, index int) string {
+	name := filePrefix + bucket.Format(bucketLayout)
+	if index > 0 {
+		name += fmt.Sprintf("_%d", index)
+	}
+	return filepath.Join(rotatingFile.config.Directory, bucket.Format(dateLayout), name+fileSuffix)
+}
+
+// openBucket ปิดไฟล์ปัจจุบันแล้วเปิดไฟล์ของช่วง bucket ตั้งแต่ลำดับ index
+// ข้ามไฟล์ที่บีบอัดแล้ว/กำลังบีบ หรือเต็มแล้ว (เช่น หลังรีสตาร์ตในช่วงเวลาเดิม)
+func (rotatingFile *RotatingFile) openBucket(bucket time.Time, index int) error {
+	rotatingFile.reportError(rotatingFile.closeCurrent())
+
+	if err := os.MkdirAll(filepath.Join(rotatingFile.config.Directory, bucket.Format(dateLayout)), rotatingFile.config.DirMode); err != nil {
+		return err
+	}
+	for ; ; index++ {
+		path := rotatingFile.fileName(bucket, index)
+		if rotatingFile.compressing[path] {
+			continue
+		}
+		if _, err := os.Stat(path + compressedSuffix); err == nil {
+			continue
+		}
+		var size int64
+		if info, err := os.Stat(path); err == nil {
+			size = info.Size()
+			if rotatingFile.config.MaxSize > 0 && size >= rotatingFile.config.MaxSize {
+				continue
+			}
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, rotatingFile.config.FileMode)
+		if err != nil {
+			return err
+		}
+		_ = file.Chmod(rotatingFile.config.FileMode) // ไม่ให้ umask ตัดสิทธิ์ที่ตั้งไว้
+		rotatingFile.file, rotatingFile.path, rotatingFile.bucket, rotatingFile.index, rotatingFile.size = file, path, bucket, index, size
+		return nil
+	}
+}
+
+// closeCurrent ปิดไฟล์ปัจจุบัน (ถ้ามี) แล้วส่งไปบีบอัดเบื้องหลัง; เรียกขณะถือ mutex
+func (rotatingFile *RotatingFile) closeCurrent() error {
+	if rotatingFile.file == nil {
+		return nil
+	}
+	syncError := rotatingFile.file.Sync()
+	closeError := rotatingFile.file.Close()
+	closedPath := rotatingFile.path
+	rotatingFile.file, rotatingFile.path, rotatingFile.size = nil, "", 0
+
+	if rotatingFile.config.Compress {
+		rotatingFile.background.Add(1)
+		go func() {
+			defer rotatingFile.background.Done()
+			rotatingFile.compress(closedPath)
+		}()
+	}
+	return errors.Join(syncError, closeError)
+}
+
+// Close หยุด janitor ปิดไฟล์ปัจจุบัน และรอการบีบอัดที่ค้างอยู่
+// ถ้ามีบรรทัดมาเขียนอีกหลังจากนี้จะเปิดไฟล์ใหม่ให้เอง
+func (rotatingFile *RotatingFile) Close() error {
+	rotatingFile.stopOnce.Do(func() { close(rotatingFile.stop) })
+	rotatingFile.mutex.Lock()
+	err := rotatingFile.closeCurrent()
+	rotatingFile.mutex.Unlock()
+	rotatingFile.background.Wait()
+	return err
+}
+
+func (rotatingFile *RotatingFile) reportError(err error) {
+	if err != nil && rotatingFile.config.OnError != nil {
+		rotatingFile.config.OnError(err)
+	}
+}
+
+// compress บีบ path เป็น path.gz แล้วลบต้นฉบับ (ไฟล์ละครั้ง แม้ janitor กับการหมุนไฟล์เจอพร้อมกัน)
+func (rotatingFile *RotatingFile) compress(path string) {
+	rotatingFile.mutex.Lock()
+	if rotatingFile.compressing[path] || path == rotatingFile.path {
+		rotatingFile.mutex.Unlock()
+		return
+	}
+	rotatingFile.compressing[path] = true
+	rotatingFile.mutex.Unlock()
+	defer func() {
+		rotatingFile.mutex.Lock()
+		delete(rotatingFile.compressing, path)
+		rotatingFile.mutex.Unlock()
+	}()
+
+	if err := gzipFile(path, rotatingFile.config.FileMode); err != nil {
+		rotatingFile.reportError(fmt.Errorf("compress access log %s: %w", path, err))
+	}
+}
+
+// gzipFile เขียนลงไฟล์ชั่วคราวก่อนแล้วค่อย rename (ไม่มีไฟล์ .gz ครึ่ง ๆ กลาง ๆ ถ้าโปรเซสตายระหว่างทาง)
+func gzipFile(path string, fileMode os.FileMode) error {
+	source, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil // ถูกบีบหรือลบไปแล้ว
+		}
+		return err
+	}
+	defer source.Close()
+
+	temporaryPath := path + compressedSuffix + ".tmp"
+	target, err := os.OpenFile(temporaryPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, fileMode)
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(target)
+	_, copyError := io.Copy(writer, source)
+	if err := errors.Join(copyError, writer.Close(), target.Sync(), target.Close()); err != nil {
+		_ = os.Remove(temporaryPath)
+		return err
+	}
+	if info, err := source.Stat(); err == nil {
+		_ = os.Chtimes(temporaryPath, info.ModTime(), info.ModTime()) // เก็บเวลาเดิมไว้ให้ retention ตามอายุ
+	}
+	if err := os.Rename(temporaryPath, path+compressedSuffix); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func (rotatingFile *RotatingFile) runJanitor() {
+	defer rotatingFile.background.Done()
+	rotatingFile.Cleanup(time.Now())
+
+	ticker := time.NewTicker(rotatingFile.config.CleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-rotatingFile.stop:
+			return
+		case now := <-ticker.C:
+			rotatingFile.Cleanup(now)
+		}
+	}
+}
+
+type logFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// Cleanup = งานหนึ่งรอบของ janitor: บีบไฟล์ที่ปิดแล้วแต่ยังไม่บีบ (เช่น ค้างจากการรันครั้งก่อน)
+// แล้วลบไฟล์ที่เก่ากว่า MaxAge และลบไฟล์เก่าสุดจนขนาดรวมไม่เกิน MaxTotalSize (ไม่แตะไฟล์ที่กำลังเขียน)
+func (rotatingFile *RotatingFile) Cleanup(now time.Time) {
+	rotatingFile.mutex.Lock()
+	currentPath := rotatingFile.path
+	rotatingFile.mutex.Unlock()
+
+	files, err := rotatingFile.listFiles()
+	if err != nil {
+		rotatingFile.reportError(fmt.Errorf("list access logs: %w", err))
+		return
+	}
+	if rotatingFile.config.Compress {
+		for _, file := range files {
+			if file.path != currentPath && strings.HasSuffix(file.path, fileSuffix) {
+				rotatingFile.compress(file.path)
+			}
+		}
+		if files, err = rotatingFile.listFiles(); err != nil {
+			rotatingFile.reportError(fmt.Errorf("list access logs: %w", err))
+			return
+		}
+	}
+
+	var totalSize int64
+	kept := files[:0]
+	for _, file := range files {
+		if file.path != currentPath && rotatingFile.config.MaxAge > 0 && now.Sub(file.modTime) > rotatingFile.config.MaxAge {
+			rotatingFile.remove(file.path)
+			continue
+		}
+		totalSize += file.size
+		kept = append(kept, file)
+	}
+	if rotatingFile.config.MaxTotalSize > 0 {
+		sort.Slice(kept, func(left, right int) bool { return kept[left].modTime.Before(kept[right].modTime) })
+		for _, file := range kept {
+			if totalSize <= rotatingFile.config.MaxTotalSize {
+				break
+			}
+			if file.path == currentPath {
+				continue
+			}
+			rotatingFile.remove(file.path)
+			totalSize -= file.size
+		}
+	}
+	rotatingFile.removeEmptyDirectories()
+}
+
+func (rotatingFile *RotatingFile) remove(path string) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		rotatingFile.reportError(fmt.Errorf("remove access log: %w", err))
+	}
+}
+
+// listFiles คืนไฟล์ log_*.log และ log_*.log.gz ในโฟลเดอร์วันที่ทั้งหมด
+func (rotatingFile *RotatingFile) listFiles() ([]logFile, error) {
+	var files []logFile
+	err := filepath.WalkDir(rotatingFile.config.Directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) ||
+			!(strings.HasSuffix(name, fileSuffix) || strings.HasSuffix(name, fileSuffix+compressedSuffix)) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil // ถูกลบระหว่างไล่ดู
+		}
+		files = append(files, logFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	return files, err
+}
+
+// removeEmptyDirectories ลบโฟลเดอร์วันที่ที่ว่างแล้ว (os.Remove ไม่ลบโฟลเดอร์ที่ยังมีไฟล์)
+// ถือ mutex ไว้ กันลบโฟลเดอร์ที่ openBucket เพิ่งสร้างแต่ยังไม่ได้เปิดไฟล์
+func (rotatingFile *RotatingFile) removeEmptyDirectories() {
+	rotatingFile.mutex.Lock()
+	defer rotatingFile.mutex.Unlock()
+	entries, err := os.ReadDir(rotatingFile.config.Directory)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if _, err := time.Parse(dateLayout, entry.Name()); entry.IsDir() && err == nil {
+			_ = os.Remove(filepath.Join(rotatingFile.config.Directory, entry.Name()))
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package accesslog_test
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/accesslog"
+)
+
+var base = time.Date(2025, 8, 9, 5, 1, 0, 0, time.UTC)
+
+func newRotatingFile(t *testing.T, config accesslog.Config) *accesslog.RotatingFile {
+	t.Helper()
+	if config.Directory == "" {
+		config.Directory = t.TempDir()
+	}
+	config.OnError = func(err error) { t.Errorf("background error: %v", err) }
+	rotatingFile, err := accesslog.NewRotatingFile(config)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	t.Cleanup(func() { _ = rotatingFile.Close() })
+	return rotatingFile
+}
+
+func write(t *testing.T, rotatingFile *accesslog.RotatingFile, now time.Time, line string) {
+	t.Helper()
+	if err := rotatingFile.WriteLine(now, line); err != nil {
+		t.Fatalf("WriteLine: %v", err)
+	}
+}
+
+// files คืนชื่อไฟล์แบบ relative ต่อ directory เรียงตามชื่อ
+func files(t *testing.T, directory string) []string {
+	t.Helper()
+	var names []string
+	_ = filepath.WalkDir(directory, func(path string, entry os.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			relative, _ := filepath.Rel(directory, path)
+			names = append(names, filepath.ToSlash(relative))
+		}
+		return nil
+	})
+	slices.Sort(names)
+	return names
+}
+
+func TestRotatingFileRotatesByInterval(t *testing.T) {
+	directory := t.TempDir()
+	rotatingFile := newRotatingFile(t, accesslog.Config{Directory: directory, Interval: 10 * time.Minute})
+
+	write(t, rotatingFile, base, "a")
+	write(t, rotatingFile, base.Add(8*time.Minute), "b")
+	write(t, rotatingFile, base.Add(9*time.Minute), "c")
+	write(t, rotatingFile, base.Add(24*time.Hour), "d")
+	_ = rotatingFile.Close()
+
+	want := []string{
+		"2025-08-09/log_2025-08-09_05-00.log",
+		"2025-08-09/log_2025-08-09_05-10.log",
+		"2025-08-10/log_2025-08-10_05-00.log",
+	}
+	if got := files(t, directory); !slices.Equal(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	content, _ := os.ReadFile(filepath.Join(directory, want[0]))
+	if string(content) != "a\nb\n" {
+		t.Fatalf("content = %q", content)
+	}
+}
+
+func TestRotatingFileRotatesBySizeAndResumes(t *testing.T) {
+	directory := t.TempDir()
+	config := accesslog.Config{Directory: directory, Interval: time.Hour, MaxSize: 10}
+	rotatingFile := newRotatingFile(t, config)
+
+	for _, line := range []string{"1234", "5678", "abcd"} { // 5 ไบต์ต่อบรรทัด ไฟล์ละ 2 บรรทัด
+		write(t, rotatingFile, base, line)
+	}
+	_ = rotatingFile.Close()
+
+	// รีสตาร์ตในชั่วโมงเดิม: ไฟล์แรกเต็มแล้ว ต้องเขียนต่อที่ _1
+	restarted := newRotatingFile(t, config)
+	write(t, restarted, base, "efgh")
+	_ = restarted.Close()
+
+	want := []string{"2025-08-09/log_2025-08-09_05-00.log", "2025-08-09/log_2025-08-09_05-00_1.log"}
+	if got := files(t, directory); !slices.Equal(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	content, _ := os.ReadFile(filepath.Join(directory, want[1]))
+	if string(content) != "abcd\nefgh\n" {
+		t.Fatalf("content of %s = %q", want[1], content)
+	}
+}
+
+func TestRotatingFileCompressesClosedFiles(t *testing.T) {
+	directory := t.TempDir()
+	rotatingFile := newRotatingFile(t, accesslog.Config{Directory: directory, Interval: 10 * time.Minute, Compress: true})
+
+	write(t, rotatingFile, base, "first")
+	write(t, rotatingFile, base.Add(10*time.Minute), "second")
+	if err := rotatingFile.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	want := []string{"2025-08-09/log_2025-08-09_05-00.log.gz", "2025-08-09/log_2025-08-09_05-10.log.gz"}
+	if got := files(t, directory); !slices.Equal(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	compressed, err := os.Open(filepath.Join(directory, want[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer compressed.Close()
+	reader, err := gzip.NewReader(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, _ := io.ReadAll(reader); string(content) != "first\n" {
+		t.Fatalf("decompressed = %q", content)
+	}
+}
+
+func TestRotatingFileCleanupRetention(t *testing.T) {
+	directory := t.TempDir()
+	now := time.Now() // janitor ก็รันรอบแรกด้วยเวลาจริงตอนเริ่ม
+	writeOld := func(name string, size int, age time.Duration) {
+		path := filepath.Join(directory, name)
+		_ = os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := now.Add(-age)
+		_ = os.Chtimes(path, modTime, modTime)
+	}
+	writeOld("2025-08-01/log_2025-08-01_00-00.log.gz", 10, 8*24*time.Hour) // เกิน MaxAge
+	writeOld("2025-08-07/log_2025-08-07_00-00.log.gz", 60, 2*24*time.Hour) // เก่าสุดที่เหลือ โดนลบเพราะขนาดรวม
+	writeOld("2025-08-08/log_2025-08-08_00-00.log.gz", 60, 24*time.Hour)
+	writeOld("2025-08-08/notes.txt", 500, 30*24*time.Hour) // ไม่ใช่ไฟล์ log ไม่แตะ
+
+	rotatingFile := newRotatingFile(t, accesslog.Config{
+		Directory: directory, MaxAge: 7 * 24 * time.Hour, MaxTotalSize: 100, CleanupInterval: time.Hour,
+	})
+	rotatingFile.Cleanup(now)
+
+	want := []string{"2025-08-08/log_2025-08-08_00-00.log.gz", "2025-08-08/notes.txt"}
+	if got := files(t, directory); !slices.Equal(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(directory, "2025-08-01")); !os.IsNotExist(err) {
+		t.Fatalf("empty date directory not removed: %v", err)
+	}
+}
+
+func TestRotatingFileFileMode(t *testing.T) {
+	directory := t.TempDir()
+	rotatingFile := newRotatingFile(t, accesslog.Config{Directory: directory, FileMode: 0o600})
+	write(t, rotatingFile, base, "line")
+
+	info, err := os.Stat(filepath.Join(directory, "2025-08-09", "log_2025-08-09_00-00.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Fatalf("mode = %o, want 600", mode)
+	}
+}
@@ -0,0 +1,296 @@
+package accesslog_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/accesslog"
+)
+
+func TestSyslogWriterUDP(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	writer, err := accesslog.NewSyslogWriter(accesslog.SyslogConfig{
+		Address: "udp://" + listener.LocalAddr().String(), Facility: "local3", AppName: "books",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+
+	if err := writer.WriteLine(time.Now(), `{"level":"error","status":500}`); err != nil {
+		t.Fatalf("WriteLine: %v", err)
+	}
+	buffer := make([]byte, 2048)
+	_ = listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	size, _, err := listener.ReadFrom(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// local3 (19) * 8 + error (3) = 155
+	pattern := regexp.MustCompile(`^<155>1 \d{4}-\d\d-\d\dT[\d:.]+(Z|[+-]\d\d:\d\d) \S+ books \d+ access - \{"level":"error","status":500\}$`)
+	if message := string(buffer[:size]); !pattern.MatchString(message) {
+		t.Fatalf("message = %q, not RFC 5424", message)
+	}
+}
+
+// datagram ที่ยาวเกินต้องถูกตัดให้พอดี MaxMessageBytes ไม่งั้น EMSGSIZE หรือ receiver ทิ้งทั้งข้อความ
+func TestSyslogWriterUDPTruncatesLongLines(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	writer, err := accesslog.NewSyslogWriter(accesslog.SyslogConfig{
+		Address: "udp://" + listener.LocalAddr().String(), MaxMessageBytes: 512,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+
+	line := "[info] " + strings.Repeat("ก", 30_000) // ตัวอักษร 3 byte: ตัดกลางตัวไม่ได้
+	if err := writer.WriteLine(time.Now(), line); err != nil {
+		t.Fatalf("WriteLine: %v", err)
+	}
+	buffer := make([]byte, 65_536)
+	_ = listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+	size, _, err := listener.ReadFrom(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := string(buffer[:size])
+	if size > 512 || !strings.HasSuffix(message, fmt.Sprintf("...[truncated, %d bytes total]", len(line))) {
+		t.Fatalf("message of %d bytes = %q, want <= 512 bytes with a truncation marker", size, message)
+	}
+	if !utf8.ValidString(message) {
+		t.Fatal("truncation split a UTF-8 character")
+	}
+}
+
+func TestSyslogWriterTCPOctetCounting(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	received := make(chan string, 1)
+	go func() {
+		connection, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer connection.Close()
+		data, _ := io.ReadAll(connection)
+		received <- string(data)
+	}()
+
+	writer, err := accesslog.NewSyslogWriter(accesslog.SyslogConfig{Address: "tcp://" + listener.Addr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = writer.WriteLine(time.Now(), "first")
+	_ = writer.WriteLine(time.Now(), "second")
+	_ = writer.Close()
+
+	data := <-received
+	var messages []string
+	for data != "" {
+		length, rest, _ := strings.Cut(data, " ")
+		size := 0
+		for _, digit := range length {
+			size = size*10 + int(digit-'0')
+		}
+		if size == 0 || size > len(rest) {
+			t.Fatalf("bad octet-counted frame in %q", data)
+		}
+		messages = append(messages, rest[:size])
+		data = rest[size:]
+	}
+	if len(messages) != 2 || !strings.HasPrefix(messages[0], "<134>1 ") || !strings.HasSuffix(messages[1], " second") {
+		t.Fatalf("messages = %q", messages)
+	}
+}
+
+func TestNewSyslogWriterRejectsBadConfig(t *testing.T) {
+	for _, config := range []accesslog.SyslogConfig{
+		{Address: "127.0.0.1:514"},
+		{Address: "udp://"},
+		{Address: "udp://127.0.0.1:514", Facility: "mail2"},
+	} {
+		if _, err := accesslog.NewSyslogWriter(config); err == nil {
+			t.Errorf("%+v accepted", config)
+		}
+	}
+}
+
+func TestHTTPWriterBatchesAndRetries(t *testing.T) {
+	var mutex sync.Mutex
+	var bodies []string
+	failures := 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if r.Header.Get("Authorization") != "Bearer collector" || r.Header.Get("Content-Type") != "application/x-ndjson" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	writer, err := accesslog.NewHTTPWriter(accesslog.HTTPConfig{
+		URL: server.URL, Headers: map[string]string{"Authorization": "Bearer collector"},
+		BatchSize: 2, FlushInterval: time.Hour, OnError: func(err error) { t.Errorf("OnError: %v", err) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"a", "b", "c"} {
+		_ = writer.WriteLine(time.Now(), line)
+	}
+	_ = writer.Close()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if strings.Join(bodies, "|") != "a\nb\n|c\n" {
+		t.Fatalf("bodies = %q, want a full batch (after one retry) and the rest flushed on Close", bodies)
+	}
+}
+
+// ค่าเริ่มต้น ACCESS_LOG_FORMAT=text ไม่ใช่ JSON: ต้องไม่ส่งเป็น application/x-ndjson
+func TestHTTPSinkContentTypeFollowsFormat(t *testing.T) {
+	for format, want := range map[string]string{"": accesslog.ContentTypeText, "json": accesslog.ContentTypeNDJSON} {
+		contentTypes := make(chan string, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contentTypes <- r.Header.Get("Content-Type")
+		}))
+		t.Setenv("ACCESS_LOG_SINKS", "http")
+		t.Setenv("ACCESS_LOG_HTTP_URL", server.URL)
+		t.Setenv("ACCESS_LOG_FORMAT", format)
+		writer, err := accesslog.OpenWriterFromEnv(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = writer.WriteLine(time.Now(), "line")
+		_ = writer.Close()
+		server.Close()
+		if got := <-contentTypes; got != want {
+			t.Errorf("ACCESS_LOG_FORMAT=%q: Content-Type = %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestHTTPWriterReportsRejectedBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	var reported []error
+	writer, _ := accesslog.NewHTTPWriter(accesslog.HTTPConfig{
+		URL: server.URL, OnError: func(err error) { reported = append(reported, err) },
+	})
+	_ = writer.WriteLine(time.Now(), "line")
+	_ = writer.Close()
+
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "400") {
+		t.Fatalf("reported = %v, want one 400 without retries", reported)
+	}
+}
+
+// collector ค้าง (ไม่ตอบจนกว่า request ถูกยกเลิก): ปิดต้องเสร็จภายในกำหนด ไม่ใช่รอ timeout × retry
+func TestHTTPWriterCloseContextIsBounded(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	var mutex sync.Mutex
+	var reported []error
+	httpWriter, err := accesslog.NewHTTPWriter(accesslog.HTTPConfig{
+		URL: server.URL, Timeout: time.Minute, MaxRetries: 10, FlushInterval: time.Hour,
+		OnError: func(err error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			reported = append(reported, err)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := accesslog.NewAsyncWriter(accesslog.NewMultiWriter(httpWriter), accesslog.AsyncConfig{})
+	_ = writer.WriteLine(time.Now(), "line")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	closeError := accesslog.CloseContext(ctx, writer)
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Fatalf("Close took %v with a 200ms deadline", elapsed)
+	}
+	if !errors.Is(closeError, context.DeadlineExceeded) {
+		t.Fatalf("Close error = %v, want deadline exceeded", closeError)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "dropped 1 lines") {
+		t.Fatalf("reported = %v, want the unsent line reported as dropped", reported)
+	}
+}
+
+type failingWriter struct{ closed bool }
+
+func (writer *failingWriter) WriteLine(time.Time, string) error { return errors.New("disk full") }
+func (writer *failingWriter) Close() error                      { writer.closed = true; return nil }
+
+func TestMultiWriterKeepsWritingWhenOneSinkFails(t *testing.T) {
+	var output bytes.Buffer
+	failing := &failingWriter{}
+	batched := &recordingWriter{}
+	writer := accesslog.NewMultiWriter(failing, accesslog.NewStreamWriter(&output), batched)
+
+	if err := writer.WriteLine(time.Now(), "one"); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("WriteLine error = %v, want the failing sink's error", err)
+	}
+	_ = writer.WriteLines([]accesslog.Line{{Text: "two"}, {Text: "three"}})
+	_ = writer.Close()
+
+	var streamed []string
+	for scanner := bufio.NewScanner(&output); scanner.Scan(); {
+		streamed = append(streamed, scanner.Text())
+	}
+	lines, batches := batched.snapshot()
+	if strings.Join(streamed, ",") != "one,two,three" || len(lines) != 3 || batches != 2 {
+		t.Fatalf("stream = %v, recorded = %v in %d batches", streamed, lines, batches)
+	}
+	if !failing.closed || !batched.closed {
+		t.Fatal("Close did not reach every sink")
+	}
+}
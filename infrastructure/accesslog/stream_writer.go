@@ -0,0 +1,46 @@
+package accesslog
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// StreamWriter เขียนทีละบรรทัดลง io.Writer (เช่น os.Stdout ให้ container runtime เก็บ log เอง)
+type StreamWriter struct {
+	mutex  sync.Mutex
+	output io.Writer
+}
+
+func NewStreamWriter(output io.Writer) *StreamWriter {
+	return &StreamWriter{output: output}
+}
+
+func (writer *StreamWriter) WriteLine(_ time.Time, line string) error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	_, err := io.WriteString(writer.output, line+"\n")
+	return err
+}
+
+// WriteLines รวมทั้งชุดเป็น write ครั้งเดียว (บรรทัดไม่ปนกับ output อื่นที่เขียนพร้อมกัน)
+func (writer *StreamWriter) WriteLines(lines []Line) error {
+	size := 0
+	for _, line := range lines {
+		size += len(line.Text) + 1
+	}
+	buffer := make([]byte, 0, size)
+	for _, line := range lines {
+		buffer = append(buffer, line.Text...)
+		buffer = append(buffer, '\n')
+	}
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	_, err := writer.output.Write(buffer)
+	return err
+}
+
+// Close ไม่ปิด output (stdout เป็นของทั้งโปรเซส)
+func (writer *StreamWriter) Close() error {
+	return nil
+}
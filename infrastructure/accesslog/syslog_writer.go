@@ -0,0 +1,191 @@
+package accesslog
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// syslog severity (RFC 5424 หัวข้อ 6.2.1)
+const (
+	severityError   = 3
+	severityWarning = 4
+	severityInfo    = 6
+)
+
+var syslogFacilities = map[string]int{
+	"user": 1, "daemon": 3, "auth": 4, "local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// SyslogConfig = ปลายทาง syslog
+type SyslogConfig struct {
+	// Address = udp://host:514, tcp://host:601 หรือ unix:///dev/log
+	Address string
+	// Facility = user, daemon, auth, local0..local7 (ค่าเริ่มต้น local0)
+	Facility string
+	// AppName = APP-NAME ในหัวข้อความ (ค่าเริ่มต้น books-api)
+	AppName string
+	// Timeout = เวลาสูงสุดในการต่อ/เขียนแต่ละครั้ง (ค่าเริ่มต้น 5s)
+	Timeout time.Duration
+	// MaxMessageBytes = ขนาดสูงสุดของหนึ่ง datagram (udp, unixgram) รวมหัวข้อความ (ค่าเริ่มต้น 2048)
+	// บรรทัดที่ยาวกว่าถูกตัดพร้อมต่อท้าย ...[truncated, N bytes total] ไม่งั้นเขียนไม่ได้ (EMSGSIZE) หรือ receiver ทิ้ง
+	MaxMessageBytes int
+}
+
+// SyslogWriter ส่งบรรทัดละหนึ่งข้อความแบบ RFC 5424 ทาง UDP, TCP (octet counting ตาม RFC 6587) หรือ unix socket
+// ต่อใหม่ให้เองเมื่อการเขียนล้มเหลว (เช่น collector รีสตาร์ต)
+type SyslogWriter struct {
+	network  string
+	address  string
+	stream   bool
+	facility int
+	appName  string
+	hostname string
+	timeout  time.Duration
+	maxBytes int
+
+	mutex      sync.Mutex
+	connection net.Conn
+}
+
+// NewSyslogWriter ตรวจค่าแล้วคืน writer (ยังไม่ต่อจนกว่าจะมีบรรทัดแรก collector ยังไม่พร้อมก็เปิดโปรแกรมได้)
+func NewSyslogWriter(config SyslogConfig) (*SyslogWriter, error) {
+	parsed, err := url.Parse(config.Address)
+	if err != nil {
+		return nil, fmt.Errorf("syslog address %q: %w", config.Address, err)
+	}
+	writer := &SyslogWriter{appName: config.AppName, timeout: config.Timeout, maxBytes: config.MaxMessageBytes}
+	switch parsed.Scheme {
+	case "udp", "tcp":
+		if parsed.Host == "" {
+			return nil, fmt.Errorf("syslog address %q: missing host:port", config.Address)
+		}
+		writer.network, writer.address, writer.stream = parsed.Scheme, parsed.Host, parsed.Scheme == "tcp"
+	case "unix":
+		if parsed.Path == "" {
+			return nil, fmt.Errorf("syslog address %q: missing socket path", config.Address)
+		}
+		writer.network, writer.address = "unix", parsed.Path
+	default:
+		return nil, fmt.Errorf("syslog address %q: want udp://, tcp:// or unix://", config.Address)
+	}
+
+	facilityName := strings.ToLower(config.Facility)
+	if facilityName == "" {
+		facilityName = "local0"
+	}
+	facility, found := syslogFacilities[facilityName]
+	if !found {
+		return nil, fmt.Errorf("unknown syslog facility %q", config.Facility)
+	}
+	writer.facility = facility
+	if writer.appName == "" {
+		writer.appName = "books-api"
+	}
+	if len(writer.appName) > 48 { // ความยาวสูงสุดของ APP-NAME
+		writer.appName = writer.appName[:48]
+	}
+	if writer.timeout <= 0 {
+		writer.timeout = 5 * time.Second
+	}
+	if writer.maxBytes <= 0 {
+		writer.maxBytes = 2048
+	}
+	if writer.hostname, err = os.Hostname(); err != nil || writer.hostname == "" {
+		writer.hostname = "-"
+	}
+	return writer, nil
+}
+
+// dial ต่อปลายทาง; unix socket ลองแบบ datagram ก่อน (/dev/log ส่วนใหญ่) แล้วค่อยแบบ stream
+func (writer *SyslogWriter) dial() error {
+	if writer.network != "unix" {
+		connection, err := net.DialTimeout(writer.network, writer.address, writer.timeout)
+		writer.connection = connection
+		return err
+	}
+	if connection, err := net.DialTimeout("unixgram", writer.address, writer.timeout); err == nil {
+		writer.connection, writer.stream = connection, false
+		return nil
+	}
+	connection, err := net.DialTimeout("unix", writer.address, writer.timeout)
+	writer.connection, writer.stream = connection, true
+	return err
+}
+
+// format = <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+// แบบ datagram ตัด MSG ให้ทั้งข้อความไม่เกิน maxBytes
+func (writer *SyslogWriter) format(now time.Time, line string) string {
+	priority := writer.facility*8 + severityOf(line)
+	header := fmt.Sprintf("<%d>1 %s %s %s %d access - ",
+		priority, now.Format("2006-01-02T15:04:05.000000Z07:00"), writer.hostname, writer.appName, os.Getpid())
+	if writer.stream {
+		return fmt.Sprintf("%d %s%s", len(header)+len(line), header, line)
+	}
+	return header + truncateMessage(line, writer.maxBytes-len(header))
+}
+
+// truncateMessage ตัด line ให้ยาวไม่เกิน limit byte โดยไม่ตัดกลางตัวอักษร UTF-8
+func truncateMessage(line string, limit int) string {
+	if len(line) <= limit {
+		return line
+	}
+	marker := fmt.Sprintf("...[truncated, %d bytes total]", len(line))
+	keep := max(limit-len(marker), 0)
+	for keep > 0 && !utf8.RuneStart(line[keep]) {
+		keep--
+	}
+	return line[:keep] + marker
+}
+
+// severityOf อ่านระดับจากบรรทัดที่จัดรูปแบบแล้ว (text: "[error]", json: "level":"error")
+func severityOf(line string) int {
+	head := line[:min(len(line), 120)]
+	switch {
+	case strings.Contains(head, "[error]") || strings.Contains(head, `"level":"error"`):
+		return severityError
+	case strings.Contains(head, "[warn]") || strings.Contains(head, `"level":"warn"`):
+		return severityWarning
+	default:
+		return severityInfo
+	}
+}
+
+func (writer *SyslogWriter) WriteLine(now time.Time, line string) error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ { // ล้มเหลวแล้วต่อใหม่หนึ่งครั้ง
+		if writer.connection == nil {
+			if err = writer.dial(); err != nil {
+				writer.connection = nil
+				continue
+			}
+		}
+		_ = writer.connection.SetWriteDeadline(time.Now().Add(writer.timeout))
+		if _, err = writer.connection.Write([]byte(writer.format(now, line))); err == nil {
+			return nil
+		}
+		_ = writer.connection.Close()
+		writer.connection = nil
+	}
+	return fmt.Errorf("syslog %s://%s: %w", writer.network, writer.address, err)
+}
+
+func (writer *SyslogWriter) Close() error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	if writer.connection == nil {
+		return nil
+	}
+	err := writer.connection.Close()
+	writer.connection = nil
+	return err
+}
@@ -0,0 +1,25 @@
+package auth
+
+import "golang.org/x/crypto/bcrypt"
+
+// BcryptHasher = interfaces.SecretHasher ด้วย bcrypt
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher รับ cost ของ bcrypt (ค่าที่อยู่นอกช่วงที่รองรับจะใช้ bcrypt.DefaultCost)
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{cost: cost}
+}
+
+func (hasher *BcryptHasher) Hash(secret string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), hasher.cost)
+	return string(hash), err
+}
+
+func (hasher *BcryptHasher) Matches(hash, secret string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(secret)) == nil
+}
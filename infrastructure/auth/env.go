@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/config"
+)
+
+// JWTVerifierFromEnv สร้าง JWTVerifier จากตัวแปรแวดล้อม คืน nil ถ้าไม่ได้ตั้งกุญแจใด ๆ (ปิดการยืนยันตัวตน)
+//
+//	AUTH_JWT_HS256_SECRET     secret ของ HS256
+//	AUTH_JWT_PUBLIC_KEY_FILE  public key PEM (RSA/EC) สำหรับ RS256/ES256
+//	AUTH_JWT_JWKS_URL         JWKS endpoint ของ identity provider (แคช AUTH_JWT_JWKS_REFRESH)
+//	AUTH_JWT_ISSUER / AUTH_JWT_AUDIENCE / AUTH_JWT_LEEWAY / AUTH_JWT_ROLES_CLAIM / AUTH_JWT_TENANT_CLAIM
+func JWTVerifierFromEnv() (*JWTVerifier, error) {
+	jwtConfig := JWTConfig{
+		Issuer:      os.Getenv("AUTH_JWT_ISSUER"),
+		Audience:    os.Getenv("AUTH_JWT_AUDIENCE"),
+		Leeway:      config.Duration("AUTH_JWT_LEEWAY", 30*time.Second),
+		RolesClaim:  os.Getenv("AUTH_JWT_ROLES_CLAIM"),
+		TenantClaim: os.Getenv("AUTH_JWT_TENANT_CLAIM"),
+	}
+
+	var keySource KeySource
+	if jwksURL := os.Getenv("AUTH_JWT_JWKS_URL"); jwksURL != "" {
+		keySource = NewJWKSKeySource(jwksURL, config.Duration("AUTH_JWT_JWKS_REFRESH", 10*time.Minute))
+		jwtConfig.Algorithms = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+	} else {
+		staticKeys := NewStaticKeySource()
+		if secret := os.Getenv("AUTH_JWT_HS256_SECRET"); secret != "" {
+			staticKeys.AddHMACSecret("", []byte(secret))
+			jwtConfig.Algorithms = append(jwtConfig.Algorithms, "HS256")
+		}
+		if keyFile := os.Getenv("AUTH_JWT_PUBLIC_KEY_FILE"); keyFile != "" {
+			pemBytes, err := os.ReadFile(keyFile)
+			if err != nil {
+				return nil, err
+			}
+			if err := staticKeys.AddPublicKeyPEM("", pemBytes); err != nil {
+				return nil, err
+			}
+			jwtConfig.Algorithms = append(jwtConfig.Algorithms, "RS256", "ES256")
+		}
+		if len(jwtConfig.Algorithms) == 0 {
+			return nil, nil
+		}
+		keySource = staticKeys
+	}
+
+	// AUTH_JWT_ALGORITHMS=RS256 จำกัด alg ให้แคบลงได้ (คั่นด้วย ",")
+	if algorithms := os.Getenv("AUTH_JWT_ALGORITHMS"); algorithms != "" {
+		jwtConfig.Algorithms = strings.Split(algorithms, ",")
+	}
+	return NewJWTVerifier(keySource, jwtConfig), nil
+}
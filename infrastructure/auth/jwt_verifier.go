@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+)
+
+// JWTConfig = กติกาการตรวจ JWT
+type JWTConfig struct {
+	Algorithms  []string      // alg ที่ยอมรับ เช่น HS256, RS256, ES256
+	Issuer      string        // ว่าง = ไม่ตรวจ iss
+	Audience    string        // ว่าง = ไม่ตรวจ aud
+	Leeway      time.Duration // เผื่อเวลาเหลื่อมของนาฬิกา
+	RolesClaim  string        // ชื่อ claim ที่เก็บบทบาท (ค่าเริ่มต้น roles)
+	TenantClaim string        // ชื่อ claim ที่เก็บ tenant (ค่าเริ่มต้น tenant_id)
+}
+
+// JWTVerifier implement interfaces.CredentialVerifier สำหรับ Bearer token แบบ JWT
+type JWTVerifier struct {
+	keySource KeySource
+	config    JWTConfig
+	parser    *jwt.Parser
+}
+
+var _ interfaces.CredentialVerifier = (*JWTVerifier)(nil)
+
+func NewJWTVerifier(keySource KeySource, config JWTConfig) *JWTVerifier {
+	if config.RolesClaim == "" {
+		config.RolesClaim = "roles"
+	}
+	if config.TenantClaim == "" {
+		config.TenantClaim = "tenant_id"
+	}
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods(config.Algorithms),
+		jwt.WithLeeway(config.Leeway),
+		jwt.WithExpirationRequired(),
+	}
+	if config.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(config.Audience))
+	}
+	return &JWTVerifier{keySource: keySource, config: config, parser: jwt.NewParser(parserOptions...)}
+}
+
+// Verify ตรวจลายเซ็น/วันหมดอายุ/iss/aud แล้วแปลง claim เป็น Principal
+func (verifier *JWTVerifier) Verify(requestContext context.Context, credential string) (requestmeta.Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := verifier.parser.ParseWithClaims(credential, claims, func(token *jwt.Token) (any, error) {
+		keyID, _ := token.Header["kid"].(string)
+		return verifier.keySource.Key(requestContext, keyID, token.Method.Alg())
+	})
+	if err != nil {
+		return requestmeta.Principal{}, describeTokenError(err)
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return requestmeta.Principal{}, errors.New("token has no subject")
+	}
+	tenantID, _ := claims[verifier.config.TenantClaim].(string)
+	return requestmeta.Principal{
+		Subject:    subject,
+		AuthMethod: "jwt",
+		Roles:      stringList(claims[verifier.config.RolesClaim]),
+		Scopes:     scopes(claims),
+		TenantID:   tenantID,
+		Claims:     claims,
+	}, nil
+}
+
+// describeTokenError ย่อ error ของไลบรารีให้เหลือข้อความสั้นพอจะใส่ใน WWW-Authenticate
+func describeTokenError(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return errors.New("token expired")
+	case errors.Is(err, jwt.ErrTokenNotValidYet):
+		return errors.New("token not valid yet")
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return errors.New("invalid issuer")
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return errors.New("invalid audience")
+	case errors.Is(err, ErrUnknownKey), errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return errors.New("invalid signature")
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return errors.New("malformed token")
+	default:
+		return errors.New("invalid token")
+	}
+}
+
+// stringList รับได้ทั้ง ["a","b"] และ "a b"
+func stringList(value any) []string {
+	switch typed := value.(type) {
+	case string:
+		return strings.Fields(typed)
+	case []any:
+		result := make([]string, 0, len(typed))
+		for _, item := range typed {
+			if text, ok := item.(string); ok {
+				result = append(result, text)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// scopes อ่านจาก "scope" (OAuth2, คั่นด้วยช่องว่าง) หรือ "scp" (array)
+func scopes(claims jwt.MapClaims) []string {
+	if scope, ok := claims["scope"]; ok {
+		return stringList(scope)
+	}
+	return stringList(claims["scp"])
+}
@@ -0,0 +1,108 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/auth"
+)
+
+var ctx = context.Background()
+
+func signHS256(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestJWTVerifierStaticHS256(t *testing.T) {
+	keys := auth.NewStaticKeySource()
+	keys.AddHMACSecret("", []byte("s3cret"))
+	verifier := auth.NewJWTVerifier(keys, auth.JWTConfig{Algorithms: []string{"HS256"}, Issuer: "books-idp"})
+
+	valid := signHS256(t, "s3cret", jwt.MapClaims{
+		"sub": "user-1", "iss": "books-idp", "exp": time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"editor"}, "scope": "books:read books:write",
+	})
+	principal, err := verifier.Verify(ctx, valid)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if principal.Subject != "user-1" || !principal.HasRole("editor") || !principal.HasScope("books:write") {
+		t.Fatalf("unexpected principal %+v", principal)
+	}
+
+	rejected := map[string]string{
+		"expired":      signHS256(t, "s3cret", jwt.MapClaims{"sub": "u", "iss": "books-idp", "exp": time.Now().Add(-time.Hour).Unix()}),
+		"wrong secret": signHS256(t, "other", jwt.MapClaims{"sub": "u", "iss": "books-idp", "exp": time.Now().Add(time.Hour).Unix()}),
+		"wrong issuer": signHS256(t, "s3cret", jwt.MapClaims{"sub": "u", "iss": "evil", "exp": time.Now().Add(time.Hour).Unix()}),
+		"no exp":       signHS256(t, "s3cret", jwt.MapClaims{"sub": "u", "iss": "books-idp"}),
+		"garbage":      "not-a-token",
+	}
+	for name, token := range rejected {
+		if _, err := verifier.Verify(ctx, token); err == nil {
+			t.Errorf("%s: expected rejection", name)
+		}
+	}
+}
+
+// JWKS ถูกแคชไว้ และ kid ที่ไม่รู้จักไม่ทำให้ดึง JWKS ใหม่ถี่เกินไป
+func TestJWTVerifierJWKSCaching(t *testing.T) {
+	oldKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	newKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	var current atomic.Value
+	current.Store(map[string]*ecdsa.PrivateKey{"k1": oldKey})
+	var fetches atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		var keys []map[string]string
+		for kid, key := range current.Load().(map[string]*ecdsa.PrivateKey) {
+			keys = append(keys, map[string]string{
+				"kty": "EC", "crv": "P-256", "kid": kid, "use": "sig",
+				"x": base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+				"y": base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	}))
+	defer server.Close()
+
+	verifier := auth.NewJWTVerifier(auth.NewJWKSKeySource(server.URL, time.Hour), auth.JWTConfig{Algorithms: []string{"ES256"}})
+	sign := func(kid string, key *ecdsa.PrivateKey) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"sub": "svc", "exp": time.Now().Add(time.Hour).Unix()})
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	if _, err := verifier.Verify(ctx, sign("k1", oldKey)); err != nil {
+		t.Fatalf("old key: %v", err)
+	}
+	if _, err := verifier.Verify(ctx, sign("k1", oldKey)); err != nil || fetches.Load() != 1 {
+		t.Fatalf("expected cached JWKS (fetches=%d, err=%v)", fetches.Load(), err)
+	}
+
+	current.Store(map[string]*ecdsa.PrivateKey{"k2": newKey})
+	// เพิ่งดึง JWKS ไป ยังอยู่ในช่วงกันรีเฟรชถี่ kid ใหม่จึงยังถูกปฏิเสธ (กัน token ปลอมยิงให้ดึง JWKS รัว ๆ)
+	if _, err := verifier.Verify(ctx, sign("k2", newKey)); err == nil {
+		t.Fatal("expected unknown kid to be rejected inside the min refresh interval")
+	}
+}
@@ -0,0 +1,249 @@
+// Package auth = อแดปเตอร์ยืนยันตัวตน (ตรวจ JWT ด้วยกุญแจจาก config หรือ JWKS)
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrUnknownKey = ไม่มีกุญแจที่ตรงกับ kid/alg ของ token
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// KeySource หากุญแจสำหรับตรวจลายเซ็นตาม kid และ alg ของ token
+type KeySource interface {
+	Key(requestContext context.Context, keyID, algorithm string) (any, error)
+}
+
+// keyMatchesAlgorithm กันไม่ให้ใช้กุญแจผิดชนิด (เช่น เอา public key RSA ไปตรวจ HS256)
+func keyMatchesAlgorithm(key any, algorithm string) bool {
+	switch key.(type) {
+	case []byte:
+		return strings.HasPrefix(algorithm, "HS")
+	case *rsa.PublicKey:
+		return strings.HasPrefix(algorithm, "RS") || strings.HasPrefix(algorithm, "PS")
+	case *ecdsa.PublicKey:
+		return strings.HasPrefix(algorithm, "ES")
+	default:
+		return false
+	}
+}
+
+// StaticKeySource = กุญแจที่ตั้งค่าไว้ตายตัว (secret ของ HS256 หรือ public key PEM ของ RS/ES)
+// key ที่ kid = "" ใช้เป็นค่าเริ่มต้นเมื่อ token ไม่ระบุ kid
+type StaticKeySource struct {
+	keys map[string][]any
+}
+
+func NewStaticKeySource() *StaticKeySource {
+	return &StaticKeySource{keys: make(map[string][]any)}
+}
+
+// AddHMACSecret เพิ่ม secret สำหรับ HS256/384/512
+func (source *StaticKeySource) AddHMACSecret(keyID string, secret []byte) {
+	source.keys[keyID] = append(source.keys[keyID], secret)
+}
+
+// AddPublicKeyPEM เพิ่ม public key (RSA หรือ EC) จาก PEM
+func (source *StaticKeySource) AddPublicKeyPEM(keyID string, pemBytes []byte) error {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return errors.New("public key: no PEM block found")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("public key: %w", err)
+	}
+	switch publicKey.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		source.keys[keyID] = append(source.keys[keyID], publicKey)
+		return nil
+	default:
+		return fmt.Errorf("public key: unsupported type %T", publicKey)
+	}
+}
+
+func (source *StaticKeySource) Key(requestContext context.Context, keyID, algorithm string) (any, error) {
+	candidates := source.keys[keyID]
+	if keyID != "" && len(candidates) == 0 {
+		candidates = source.keys[""]
+	}
+	for _, key := range candidates {
+		if keyMatchesAlgorithm(key, algorithm) {
+			return key, nil
+		}
+	}
+	return nil, ErrUnknownKey
+}
+
+// JWKSKeySource ดึงกุญแจจาก JWKS URL แล้วแคชไว้
+// - รีเฟรชทุก refreshInterval
+// - เจอ kid ที่ไม่รู้จัก (กุญแจถูกหมุน) จะรีเฟรชทันที แต่ไม่ถี่กว่า minRefreshInterval
+type JWKSKeySource struct {
+	url                string
+	client             *http.Client
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+
+	mutex       sync.RWMutex
+	keys        map[string]any
+	fetchedAt   time.Time
+	lastAttempt time.Time
+	flight      singleflight.Group
+}
+
+func NewJWKSKeySource(url string, refreshInterval time.Duration) *JWKSKeySource {
+	return &JWKSKeySource{
+		url:                url,
+		client:             &http.Client{Timeout: 5 * time.Second},
+		refreshInterval:    refreshInterval,
+		minRefreshInterval: 30 * time.Second,
+		keys:               make(map[string]any),
+	}
+}
+
+func (source *JWKSKeySource) Key(requestContext context.Context, keyID, algorithm string) (any, error) {
+	source.mutex.RLock()
+	key, found := source.keys[keyID]
+	stale := time.Since(source.fetchedAt) > source.refreshInterval
+	canRetry := time.Since(source.lastAttempt) > source.minRefreshInterval
+	source.mutex.RUnlock()
+
+	if (stale || !found) && canRetry {
+		if err := source.refresh(requestContext); err != nil && !found {
+			return nil, err
+		}
+		source.mutex.RLock()
+		key, found = source.keys[keyID]
+		source.mutex.RUnlock()
+	}
+	if !found || !keyMatchesAlgorithm(key, algorithm) {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// refresh โหลด JWKS ใหม่ (request ที่มาพร้อมกันรอผลโหลดครั้งเดียวกัน)
+func (source *JWKSKeySource) refresh(requestContext context.Context) error {
+	_, err, _ := source.flight.Do("refresh", func() (any, error) {
+		source.mutex.Lock()
+		source.lastAttempt = time.Now()
+		source.mutex.Unlock()
+
+		keys, err := source.fetch(context.WithoutCancel(requestContext))
+		if err != nil {
+			return nil, err
+		}
+		source.mutex.Lock()
+		source.keys = keys
+		source.fetchedAt = time.Now()
+		source.mutex.Unlock()
+		return nil, nil
+	})
+	return err
+}
+
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+func (source *JWKSKeySource) fetch(requestContext context.Context) (map[string]any, error) {
+	request, err := http.NewRequestWithContext(requestContext, http.MethodGet, source.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := source.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks: unexpected status %d", response.StatusCode)
+	}
+
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&document); err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+	keys := make(map[string]any, len(document.Keys))
+	for _, webKey := range document.Keys {
+		if webKey.Use != "" && webKey.Use != "sig" {
+			continue
+		}
+		publicKey, err := webKey.publicKey()
+		if err != nil {
+			continue // ข้ามกุญแจที่ไม่รองรับ ไม่ให้ทั้งชุดใช้ไม่ได้
+		}
+		keys[webKey.KeyID] = publicKey
+	}
+	return keys, nil
+}
+
+func (webKey jsonWebKey) publicKey() (any, error) {
+	switch webKey.KeyType {
+	case "RSA":
+		modulus, err := decodeBigInt(webKey.N)
+		if err != nil {
+			return nil, err
+		}
+		exponent, err := decodeBigInt(webKey.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: modulus, E: int(exponent.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch webKey.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", webKey.Curve)
+		}
+		x, err := decodeBigInt(webKey.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(webKey.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", webKey.KeyType)
+	}
+}
+
+func decodeBigInt(encoded string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/authorization"
+)
+
+// policyFile = รูปแบบไฟล์นโยบายสิทธิ์ (YAML หรือ JSON)
+//
+//	roles:
+//	  viewer: [books:read]
+//	  editor: [books:read, books:write, books:delete]
+//	  admin:  ["*"]
+type policyFile struct {
+	Roles map[string][]string `yaml:"roles"`
+}
+
+// LoadPolicyFile อ่านนโยบายจากไฟล์ (แทนที่ authorization.DefaultPolicy ทั้งชุด)
+func LoadPolicyFile(path string) (*authorization.Policy, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var parsed policyFile
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		return nil, fmt.Errorf("policy file %s: %w", path, err)
+	}
+	if len(parsed.Roles) == 0 {
+		return nil, fmt.Errorf("policy file %s: no roles defined", path)
+	}
+	return authorization.NewPolicy(parsed.Roles), nil
+}
@@ -0,0 +1,252 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+// ค่าพิเศษที่เก็บแทน "ไม่มีเล่มนี้" (negative cache)
+var notFoundMarker = []byte("!notfound")
+
+// key แยกตาม tenant ไม่ให้ข้อมูลของ tenant หนึ่งถูกส่งให้อีก tenant
+func listKey(requestContext context.Context) string {
+	return "books:" + requestmeta.TenantID(requestContext) + ":list"
+}
+
+func bookKey(requestContext context.Context, id uint) string {
+	return "books:" + requestmeta.TenantID(requestContext) + ":id:" + strconv.FormatUint(uint64(id), 10)
+}
+
+// Config = อายุของแคช
+type Config struct {
+	TTL         time.Duration // อายุของข้อมูลที่เจอ
+	NegativeTTL time.Duration // อายุของผล ErrNotFound (ควรสั้นกว่า TTL)
+}
+
+// Stats = ตัวนับไว้ดูประสิทธิภาพของแคช
+type Stats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	Errors uint64 `json:"errors"` // store ใช้ไม่ได้ (ตกไปอ่าน repository ตรง ๆ)
+}
+
+// primaryPinner = repository ที่ปักหมุดการอ่านของ client ที่เพิ่งเขียนไว้ที่ primary (gormp.WithReadYourWrites)
+type primaryPinner interface {
+	PinnedToPrimary(requestContext context.Context) bool
+}
+
+// keyGeneration = รุ่นของ key ที่ invalidate เพิ่มขึ้น; มีอยู่เฉพาะตอนที่ key นั้นกำลังโหลด (loads > 0)
+type keyGeneration struct {
+	version uint64
+	loads   int
+}
+
+// BookRepositoryCache = decorator ครอบ interfaces.BookRepository
+// แคช GetByID/List, ลบแคชเมื่อ Create/Update/SoftDelete สำเร็จ
+// และรวม cache miss ที่มาพร้อมกันให้เหลือการอ่านจริงครั้งเดียว (singleflight)
+type BookRepositoryCache struct {
+	inner  interfaces.BookRepository
+	store  Store
+	config Config
+	flight singleflight.Group
+
+	// การโหลดที่อ่านค่าเก่าไปก่อน Update จะเขียนทับแคชที่เพิ่งลบไม่ได้ ถ้ารุ่นของ key เปลี่ยนระหว่างโหลด
+	generationMutex sync.Mutex
+	generations     map[string]*keyGeneration
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	errors atomic.Uint64
+}
+
+func NewBookRepositoryCache(inner interfaces.BookRepository, store Store, config Config) *BookRepositoryCache {
+	return &BookRepositoryCache{inner: inner, store: store, config: config, generations: make(map[string]*keyGeneration)}
+}
+
+// Stats คืนค่าตัวนับ ณ ตอนนี้
+func (repository *BookRepositoryCache) Stats() Stats {
+	return Stats{
+		Hits:   repository.hits.Load(),
+		Misses: repository.misses.Load(),
+		Errors: repository.errors.Load(),
+	}
+}
+
+// cached อ่านจากแคชก่อน ถ้าไม่เจอจะให้ load ทำงาน (ครั้งเดียวต่อ key แม้มีหลาย request พร้อมกัน)
+// แล้วเก็บผลลงแคช; ErrNotFound ถูกเก็บเป็น negative cache ด้วย
+func (repository *BookRepositoryCache) cached(
+	requestContext context.Context,
+	key string,
+	load func(loadContext context.Context) (any, error),
+	decode func(raw []byte) (any, error),
+) (any, error) {
+	// การอ่านใน write path และ client ที่เพิ่งเขียน (read-your-writes) ต้องการข้อมูลล่าสุด ข้ามแคชไปเลย
+	if requestmeta.StrongReadRequested(requestContext) || repository.pinnedToPrimary(requestContext) {
+		return load(requestContext)
+	}
+
+	raw, found, err := repository.store.Get(requestContext, key)
+	if err != nil {
+		repository.errors.Add(1)
+	}
+	if found {
+		if string(raw) == string(notFoundMarker) {
+			repository.hits.Add(1)
+			return nil, domain.ErrNotFound
+		}
+		if value, decodeError := decode(raw); decodeError == nil {
+			repository.hits.Add(1)
+			return value, nil
+		}
+		// ถอดรหัสไม่ได้ (เช่น รูปแบบเก่า) ถือเป็น miss แล้วเขียนทับ
+	}
+	repository.misses.Add(1)
+
+	value, err, _ := repository.flight.Do(key, func() (any, error) {
+		// ไม่ผูกกับการยกเลิกของ request แรก เพราะ request อื่นที่รอผลเดียวกันยังต้องการคำตอบ
+		// และเติมแคชจาก primary เท่านั้น (ค่าจาก replica ที่ยังตามไม่ทันจะค้างในแคชไปทั้ง TTL)
+		loadContext := requestmeta.WithStrongRead(context.WithoutCancel(requestContext))
+		version := repository.beginLoad(key)
+		defer repository.endLoad(key)
+		value, loadError := load(loadContext)
+		switch {
+		case errors.Is(loadError, domain.ErrNotFound):
+			repository.put(loadContext, key, version, notFoundMarker, repository.config.NegativeTTL)
+		case loadError == nil:
+			if encoded, encodeError := json.Marshal(value); encodeError == nil {
+				repository.put(loadContext, key, version, encoded, repository.config.TTL)
+			}
+		}
+		return value, loadError
+	})
+	return value, err
+}
+
+func (repository *BookRepositoryCache) pinnedToPrimary(requestContext context.Context) bool {
+	pinner, ok := repository.inner.(primaryPinner)
+	return ok && pinner.PinnedToPrimary(requestContext)
+}
+
+// beginLoad คืนรุ่นปัจจุบันของ key ไว้เทียบตอน put
+func (repository *BookRepositoryCache) beginLoad(key string) uint64 {
+	repository.generationMutex.Lock()
+	defer repository.generationMutex.Unlock()
+	generation, found := repository.generations[key]
+	if !found {
+		generation = &keyGeneration{}
+		repository.generations[key] = generation
+	}
+	generation.loads++
+	return generation.version
+}
+
+func (repository *BookRepositoryCache) endLoad(key string) {
+	repository.generationMutex.Lock()
+	defer repository.generationMutex.Unlock()
+	generation := repository.generations[key]
+	if generation.loads--; generation.loads == 0 {
+		delete(repository.generations, key)
+	}
+}
+
+// put เขียนลงแคชเฉพาะเมื่อไม่มี invalidate เกิดขึ้นระหว่างโหลด (ถือ lock ไว้ตอน Set
+// เพื่อให้ invalidate ที่ตามมาทีหลังลบค่านี้ได้เสมอ)
+func (repository *BookRepositoryCache) put(requestContext context.Context, key string, version uint64, value []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	repository.generationMutex.Lock()
+	defer repository.generationMutex.Unlock()
+	if repository.generations[key].version != version {
+		return
+	}
+	if err := repository.store.Set(requestContext, key, value, ttl); err != nil {
+		repository.errors.Add(1)
+	}
+}
+
+// invalidate เพิ่มรุ่นของ key ที่กำลังโหลด, ให้ request ถัดไปไม่ไปรอผลโหลดเก่า (Forget) แล้วลบแคช
+func (repository *BookRepositoryCache) invalidate(requestContext context.Context, keys ...string) {
+	repository.generationMutex.Lock()
+	for _, key := range keys {
+		if generation, found := repository.generations[key]; found {
+			generation.version++
+		}
+	}
+	repository.generationMutex.Unlock()
+	for _, key := range keys {
+		repository.flight.Forget(key)
+	}
+	if err := repository.store.Delete(requestContext, keys...); err != nil {
+		repository.errors.Add(1)
+	}
+}
+
+func (repository *BookRepositoryCache) List(requestContext context.Context) ([]domain.Book, error) {
+	value, err := repository.cached(requestContext, listKey(requestContext),
+		func(loadContext context.Context) (any, error) { return repository.inner.List(loadContext) },
+		func(raw []byte) (any, error) {
+			var books []domain.Book
+			err := json.Unmarshal(raw, &books)
+			return books, err
+		})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]domain.Book), nil
+}
+
+func (repository *BookRepositoryCache) GetByID(requestContext context.Context, id uint) (domain.Book, error) {
+	value, err := repository.cached(requestContext, bookKey(requestContext, id),
+		func(loadContext context.Context) (any, error) { return repository.inner.GetByID(loadContext, id) },
+		func(raw []byte) (any, error) {
+			var book domain.Book
+			err := json.Unmarshal(raw, &book)
+			return book, err
+		})
+	if err != nil {
+		return domain.Book{}, err
+	}
+	return value.(domain.Book), nil
+}
+
+// ExistsActiveByTitle ไม่แคช เพราะใช้ตัดสินใจก่อนเขียน ต้องสดเสมอ
+func (repository *BookRepositoryCache) ExistsActiveByTitle(requestContext context.Context, title string, excludeID *uint) (bool, error) {
+	return repository.inner.ExistsActiveByTitle(requestContext, title, excludeID)
+}
+
+func (repository *BookRepositoryCache) Create(requestContext context.Context, book *domain.Book) error {
+	if err := repository.inner.Create(requestContext, book); err != nil {
+		return err
+	}
+	// ลบ negative cache ของ id นี้ด้วย (เผื่อมีคนถาม id นี้ก่อนมันถูกสร้าง)
+	repository.invalidate(requestContext, listKey(requestContext), bookKey(requestContext, book.ID))
+	return nil
+}
+
+func (repository *BookRepositoryCache) Update(requestContext context.Context, book *domain.Book) error {
+	if err := repository.inner.Update(requestContext, book); err != nil {
+		return err
+	}
+	repository.invalidate(requestContext, listKey(requestContext), bookKey(requestContext, book.ID))
+	return nil
+}
+
+func (repository *BookRepositoryCache) SoftDelete(requestContext context.Context, id uint) error {
+	if err := repository.inner.SoftDelete(requestContext, id); err != nil {
+		return err
+	}
+	repository.invalidate(requestContext, listKey(requestContext), bookKey(requestContext, id))
+	return nil
+}
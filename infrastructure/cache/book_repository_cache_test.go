@@ -0,0 +1,251 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/cache"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/contract"
+	gormp "github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/gorm"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/memory"
+)
+
+var testConfig = cache.Config{TTL: time.Minute, NegativeTTL: time.Minute}
+
+// newRedisStore ใช้ miniredis เป็นตัวแทน Redis ในโปรเซส
+func newRedisStore(t *testing.T) cache.Store {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return cache.NewRedisStore(client, "test:")
+}
+
+var stores = map[string]func(t *testing.T) cache.Store{
+	"lru":   func(t *testing.T) cache.Store { return cache.NewLRUStore(100) },
+	"redis": newRedisStore,
+}
+
+func TestBookRepositoryCacheContract(t *testing.T) {
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			contract.RunBookRepositoryContract(t, func(t *testing.T) interfaces.BookRepository {
+				return cache.NewBookRepositoryCache(memory.NewBookRepositoryMemory(), newStore(t), testConfig)
+			})
+		})
+	}
+}
+
+// countingRepository นับจำนวนครั้งที่ GetByID ถึงตัวจริง
+type countingRepository struct {
+	interfaces.BookRepository
+	gets  atomic.Int64
+	delay time.Duration
+}
+
+func (repository *countingRepository) GetByID(requestContext context.Context, id uint) (domain.Book, error) {
+	repository.gets.Add(1)
+	time.Sleep(repository.delay)
+	return repository.BookRepository.GetByID(requestContext, id)
+}
+
+func TestBookRepositoryCacheBehaviour(t *testing.T) {
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			inner := &countingRepository{BookRepository: memory.NewBookRepositoryMemory()}
+			repository := cache.NewBookRepositoryCache(inner, newStore(t), testConfig)
+
+			book := domain.Book{Title: "Cached", Author: "Author", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+			if err := repository.Create(ctx, &book); err != nil {
+				t.Fatal(err)
+			}
+
+			for range 3 {
+				if _, err := repository.GetByID(ctx, book.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := inner.gets.Load(); got != 1 {
+				t.Fatalf("expected 1 repository read, got %d", got)
+			}
+			if stats := repository.Stats(); stats.Hits != 2 || stats.Misses != 1 {
+				t.Fatalf("unexpected stats %+v", stats)
+			}
+
+			// Update ต้องลบแคช ให้รอบถัดไปเห็นค่าใหม่
+			book.Title = "Cached v2"
+			if err := repository.Update(ctx, &book); err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := repository.GetByID(ctx, book.ID)
+			if err != nil || loaded.Title != "Cached v2" {
+				t.Fatalf("expected fresh value after update, got %+v (err %v)", loaded, err)
+			}
+
+			// negative cache: ErrNotFound ครั้งที่สองไม่ถึง repository
+			before := inner.gets.Load()
+			for range 2 {
+				if _, err := repository.GetByID(ctx, 424242); !errors.Is(err, domain.ErrNotFound) {
+					t.Fatalf("expected ErrNotFound, got %v", err)
+				}
+			}
+			if got := inner.gets.Load() - before; got != 1 {
+				t.Fatalf("expected not-found to be cached, got %d repository reads", got)
+			}
+		})
+	}
+}
+
+func TestBookRepositoryCacheCollapsesConcurrentMisses(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingRepository{BookRepository: memory.NewBookRepositoryMemory(), delay: 50 * time.Millisecond}
+	repository := cache.NewBookRepositoryCache(inner, cache.NewLRUStore(100), testConfig)
+
+	book := domain.Book{Title: "Hot", Author: "Author", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := repository.Create(ctx, &book); err != nil {
+		t.Fatal(err)
+	}
+
+	var waitGroup sync.WaitGroup
+	for range 20 {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			if _, err := repository.GetByID(ctx, book.ID); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	waitGroup.Wait()
+	if got := inner.gets.Load(); got != 1 {
+		t.Fatalf("expected concurrent misses to collapse into 1 read, got %d", got)
+	}
+}
+
+// stalledRepository: GetByID ครั้งแรกอ่านค่าแล้วค้างจนกว่าจะปิด release (จำลองการอ่านที่ช้ากว่า Update)
+type stalledRepository struct {
+	interfaces.BookRepository
+	once    sync.Once
+	started chan struct{}
+	release chan struct{}
+}
+
+func (repository *stalledRepository) GetByID(requestContext context.Context, id uint) (domain.Book, error) {
+	book, err := repository.BookRepository.GetByID(requestContext, id)
+	stalled := false
+	repository.once.Do(func() { stalled = true })
+	if stalled {
+		close(repository.started)
+		<-repository.release
+	}
+	return book, err
+}
+
+// Update ที่เกิดระหว่าง miss ที่ช้า: ผลเก่าต้องไม่ถูกเขียนกลับลงแคช และ request ใหม่ต้องไม่ไปรอผลเก่านั้น
+func TestBookRepositoryCacheDropsStaleFillAfterUpdate(t *testing.T) {
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			inner := &stalledRepository{BookRepository: memory.NewBookRepositoryMemory(),
+				started: make(chan struct{}), release: make(chan struct{})}
+			repository := cache.NewBookRepositoryCache(inner, newStore(t), testConfig)
+
+			book := domain.Book{Title: "Old", Author: "Author", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+			if err := repository.Create(ctx, &book); err != nil {
+				t.Fatal(err)
+			}
+
+			slowRead := make(chan domain.Book)
+			go func() {
+				loaded, _ := repository.GetByID(ctx, book.ID)
+				slowRead <- loaded
+			}()
+			<-inner.started
+
+			book.Title = "New"
+			if err := repository.Update(ctx, &book); err != nil {
+				t.Fatal(err)
+			}
+			if loaded, err := repository.GetByID(ctx, book.ID); err != nil || loaded.Title != "New" {
+				t.Fatalf("read after update joined the stale load: %+v (err %v)", loaded, err)
+			}
+
+			close(inner.release)
+			if loaded := <-slowRead; loaded.Title != "Old" {
+				t.Fatalf("slow read should still answer with what it loaded, got %+v", loaded)
+			}
+			if loaded, err := repository.GetByID(ctx, book.ID); err != nil || loaded.Title != "New" {
+				t.Fatalf("stale load overwrote the cache: %+v (err %v)", loaded, err)
+			}
+		})
+	}
+}
+
+func openMigratedSQLite(t *testing.T, path string) *gorm.DB {
+	t.Helper()
+	database, err := gormp.OpenWith(gormp.DriverSQLite, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := gormp.NewMigrator(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return database
+}
+
+// replica เป็นไฟล์ SQLite แยกที่ "ไม่เคย sync" แคชต้องไม่เก็บค่าเก่าจาก replica และไม่บังการเขียนของ client เอง
+func TestBookRepositoryCacheKeepsReadYourWritesWithReplicas(t *testing.T) {
+	primary := openMigratedSQLite(t, filepath.Join(t.TempDir(), "primary.db"))
+	replica := openMigratedSQLite(t, filepath.Join(t.TempDir(), "replica.db"))
+	replicas := gormp.NewReplicaSet([]*gorm.DB{replica}, 0)
+	t.Cleanup(func() { _ = replicas.Close() })
+
+	inner := gormp.NewBookRepositoryGorm(primary, gormp.WithReplicas(replicas), gormp.WithReadYourWrites(time.Minute))
+	repository := cache.NewBookRepositoryCache(inner, cache.NewLRUStore(100), testConfig)
+
+	writerContext := requestmeta.WithClientKey(context.Background(), "writer")
+	otherContext := requestmeta.WithClientKey(context.Background(), "someone-else")
+
+	now := time.Now()
+	book := domain.Book{Title: "Original", Author: "Author", CreatedAt: now, UpdatedAt: now}
+	if err := repository.Create(writerContext, &book); err != nil {
+		t.Fatal(err)
+	}
+
+	// client อื่นทำให้เกิด cache miss ก่อน: ต้องเติมแคชจาก primary ไม่ใช่ ErrNotFound/รายการว่างจาก replica
+	if _, err := repository.GetByID(otherContext, book.ID); err != nil {
+		t.Fatalf("cache miss must be filled from primary, got %v", err)
+	}
+	if books, err := repository.List(otherContext); err != nil || len(books) != 1 {
+		t.Fatalf("List filled from replica: %d books (err %v)", len(books), err)
+	}
+	if got, err := repository.GetByID(writerContext, book.ID); err != nil || got.Title != "Original" {
+		t.Fatalf("writer read %+v (err %v)", got, err)
+	}
+
+	// เขียนผ่านตัวจริงโดยไม่ลบแคช (เช่น instance อื่น) client ที่เพิ่งเขียนต้องข้ามแคชและเห็นค่าใหม่
+	book.Title = "Renamed"
+	book.UpdatedAt = time.Now()
+	if err := inner.Update(writerContext, &book); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := repository.GetByID(writerContext, book.ID); err != nil || got.Title != "Renamed" {
+		t.Fatalf("pinned writer got %+v (err %v), want the value it just wrote", got, err)
+	}
+}
@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/config"
+)
+
+// ConfigFromEnv อ่าน CACHE_TTL / CACHE_NEGATIVE_TTL
+func ConfigFromEnv() Config {
+	return Config{
+		TTL:         config.Duration("CACHE_TTL", time.Minute),
+		NegativeTTL: config.Duration("CACHE_NEGATIVE_TTL", 10*time.Second),
+	}
+}
+
+// OpenStoreFromEnv เลือกที่เก็บแคชตาม CACHE_BACKEND
+// ว่าง/none = ไม่แคช (คืน nil), memory = LRU ในโปรเซส, redis = เซิร์ฟเวอร์โปรโตคอล Redis
+func OpenStoreFromEnv() (Store, error) {
+	switch backend := os.Getenv("CACHE_BACKEND"); backend {
+	case "", "none":
+		return nil, nil
+	case "memory":
+		return NewLRUStore(config.Int("CACHE_MAX_ENTRIES", 10_000)), nil
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr:     os.Getenv("CACHE_REDIS_ADDR"),
+			Password: os.Getenv("CACHE_REDIS_PASSWORD"),
+			DB:       config.Int("CACHE_REDIS_DB", 0),
+		})
+		return NewRedisStore(client, "books-api:"), nil
+	default:
+		return nil, fmt.Errorf("unknown CACHE_BACKEND %q (want none, memory or redis)", backend)
+	}
+}
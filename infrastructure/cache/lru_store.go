@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRUStore = แคชในหน่วยความจำของโปรเซส จำกัดจำนวนรายการ (ทิ้งตัวที่ไม่ได้ใช้นานสุด) และมี TTL รายตัว
+type LRUStore struct {
+	mutex      sync.Mutex
+	maxEntries int
+	order      *list.List // หน้า = ใช้ล่าสุด
+	entries    map[string]*list.Element
+	now        func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRUStore สร้างแคชที่เก็บได้สูงสุด maxEntries รายการ (<= 0 = ไม่จำกัด)
+func NewLRUStore(maxEntries int) *LRUStore {
+	return &LRUStore{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+func (store *LRUStore) Get(requestContext context.Context, key string) ([]byte, bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	element, found := store.entries[key]
+	if !found {
+		return nil, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if !store.now().Before(entry.expiresAt) {
+		store.removeElement(element)
+		return nil, false, nil
+	}
+	store.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+func (store *LRUStore) Set(requestContext context.Context, key string, value []byte, ttl time.Duration) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	expiresAt := store.now().Add(ttl)
+	if element, found := store.entries[key]; found {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		store.order.MoveToFront(element)
+		return nil
+	}
+
+	store.entries[key] = store.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	if store.maxEntries > 0 && store.order.Len() > store.maxEntries {
+		store.removeElement(store.order.Back())
+	}
+	return nil
+}
+
+func (store *LRUStore) Delete(requestContext context.Context, keys ...string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	for _, key := range keys {
+		if element, found := store.entries[key]; found {
+			store.removeElement(element)
+		}
+	}
+	return nil
+}
+
+// Len = จำนวนรายการที่ถืออยู่ (รวมตัวที่หมดอายุแต่ยังไม่ถูกเรียกถึง)
+func (store *LRUStore) Len() int {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	return store.order.Len()
+}
+
+// removeElement ต้องถือ lock อยู่แล้วก่อนเรียก
+func (store *LRUStore) removeElement(element *list.Element) {
+	store.order.Remove(element)
+	delete(store.entries, element.Value.(*lruEntry).key)
+}
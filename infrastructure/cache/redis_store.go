@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore = แคชบนเซิร์ฟเวอร์ที่พูดโปรโตคอล Redis (Redis/KeyDB/Valkey ฯลฯ) ใช้ร่วมกันได้หลาย instance
+type RedisStore struct {
+	client    redis.UniversalClient
+	keyPrefix string
+}
+
+// NewRedisStore ใช้ client ที่สร้างไว้แล้ว keyPrefix กันชนกับข้อมูลอื่นใน Redis เดียวกัน
+func NewRedisStore(client redis.UniversalClient, keyPrefix string) *RedisStore {
+	return &RedisStore{client: client, keyPrefix: keyPrefix}
+}
+
+// Ping ตรวจว่าเชื่อมต่อ Redis ได้ (ใช้เป็น health check)
+func (store *RedisStore) Ping(requestContext context.Context) error {
+	return store.client.Ping(requestContext).Err()
+}
+
+func (store *RedisStore) Get(requestContext context.Context, key string) ([]byte, bool, error) {
+	value, err := store.client.Get(requestContext, store.keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (store *RedisStore) Set(requestContext context.Context, key string, value []byte, ttl time.Duration) error {
+	return store.client.Set(requestContext, store.keyPrefix+key, value, ttl).Err()
+}
+
+func (store *RedisStore) Delete(requestContext context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, 0, len(keys))
+	for _, key := range keys {
+		prefixed = append(prefixed, store.keyPrefix+key)
+	}
+	return store.client.Del(requestContext, prefixed...).Err()
+}
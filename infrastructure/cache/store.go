@@ -0,0 +1,15 @@
+// Package cache = ตัวแคชของ repository (decorator) และที่เก็บแคชแบบต่าง ๆ
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Store = ที่เก็บแคชแบบ key/value ไบต์ (in-process LRU หรือ Redis)
+type Store interface {
+	// Get คืน found=false ถ้าไม่มี key หรือหมดอายุแล้ว
+	Get(requestContext context.Context, key string) (value []byte, found bool, err error)
+	Set(requestContext context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(requestContext context.Context, keys ...string) error
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// LoadDotEnvIfExists ลองอ่านไฟล์ .env ถ้ามี
+func LoadDotEnvIfExists() {
+	_ = godotenv.Load()
+}
+
+// String อ่านตัวแปรแวดล้อม ถ้าว่างคืน fallback
+func String(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// Bool อ่านตัวแปรแวดล้อมแบบ true/false (1/0, yes ไม่รองรับ) ถ้าว่างหรืออ่านไม่ได้คืน fallback
+func Bool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// Int อ่านตัวแปรแวดล้อมเป็นจำนวนเต็ม ถ้าว่างหรืออ่านไม่ได้คืน fallback
+func Int(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// Float อ่านตัวแปรแวดล้อมเป็นทศนิยม (เช่น 0.25) ถ้าว่างหรืออ่านไม่ได้คืน fallback
+func Float(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// Duration อ่านตัวแปรแวดล้อมรูปแบบ time.ParseDuration (เช่น 500ms, 30s, 5m) ถ้าว่างหรืออ่านไม่ได้คืน fallback
+func Duration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// List อ่านตัวแปรแวดล้อมเป็นรายการคั่นด้วย separator (ตัดช่องว่าง ข้ามค่าว่าง) ถ้าว่างคืน fallback
+func List(key, separator string, fallback []string) []string {
+	value := os.Getenv(key)
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	var items []string
+	for _, item := range strings.Split(value, separator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,31 @@
+package idempotency
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/config"
+)
+
+// OpenStoreFromEnv เลือกที่เก็บตาม IDEMPOTENCY_BACKEND
+// ว่าง/none = ปิด (คืน nil), memory = ในโปรเซส, redis = ใช้ร่วมกันทุก instance
+func OpenStoreFromEnv(clock interfaces.Clock) (interfaces.IdempotencyStore, error) {
+	switch backend := os.Getenv("IDEMPOTENCY_BACKEND"); backend {
+	case "", "none":
+		return nil, nil
+	case "memory":
+		return NewMemoryStore(clock), nil
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr:     os.Getenv("IDEMPOTENCY_REDIS_ADDR"),
+			Password: os.Getenv("IDEMPOTENCY_REDIS_PASSWORD"),
+			DB:       config.Int("IDEMPOTENCY_REDIS_DB", 0),
+		})
+		return NewRedisStore(client, "books-api:idempotency:"), nil
+	default:
+		return nil, fmt.Errorf("unknown IDEMPOTENCY_BACKEND %q (want none, memory or redis)", backend)
+	}
+}
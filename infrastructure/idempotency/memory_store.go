@@ -0,0 +1,74 @@
+// Package idempotency = ที่เก็บ Idempotency-Key สำหรับ interfaces.IdempotencyStore
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+)
+
+// sweepInterval = ความถี่ในการกวาดคีย์ที่หมดอายุออกจากหน่วยความจำ
+const sweepInterval = time.Minute
+
+// MemoryStore = เก็บคีย์ในโปรเซส (retry ที่ไปตก instance อื่นจะไม่เห็นกัน)
+type MemoryStore struct {
+	clock     interfaces.Clock
+	mutex     sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+type memoryEntry struct {
+	record    interfaces.IdempotencyRecord
+	expiresAt time.Time
+}
+
+func NewMemoryStore(clock interfaces.Clock) *MemoryStore {
+	return &MemoryStore{clock: clock, entries: make(map[string]memoryEntry)}
+}
+
+func (store *MemoryStore) Reserve(_ context.Context, key, fingerprint string, lockTTL time.Duration) (interfaces.IdempotencyRecord, bool, error) {
+	now := store.clock.Now()
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.sweep(now)
+
+	if entry, found := store.entries[key]; found && now.Before(entry.expiresAt) {
+		return entry.record, false, nil
+	}
+	record := interfaces.IdempotencyRecord{Fingerprint: fingerprint}
+	store.entries[key] = memoryEntry{record: record, expiresAt: now.Add(lockTTL)}
+	return record, true, nil
+}
+
+func (store *MemoryStore) Complete(_ context.Context, key string, record interfaces.IdempotencyRecord, ttl time.Duration) error {
+	now := store.clock.Now()
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.entries[key] = memoryEntry{record: record, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (store *MemoryStore) Release(_ context.Context, key string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.entries, key)
+	return nil
+}
+
+// sweep ต้องถือ lock อยู่แล้ว
+func (store *MemoryStore) sweep(now time.Time) {
+	if now.Sub(store.lastSweep) < sweepInterval {
+		return
+	}
+	for key, entry := range store.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(store.entries, key)
+		}
+	}
+	store.lastSweep = now
+}
@@ -0,0 +1,70 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+)
+
+// RedisStore = เก็บคีย์บนเซิร์ฟเวอร์โปรโตคอล Redis ใช้ร่วมกันได้หลาย instance
+type RedisStore struct {
+	client    redis.UniversalClient
+	keyPrefix string
+}
+
+func NewRedisStore(client redis.UniversalClient, keyPrefix string) *RedisStore {
+	return &RedisStore{client: client, keyPrefix: keyPrefix}
+}
+
+// Ping ตรวจว่าเชื่อมต่อ Redis ได้ (ใช้เป็น health check)
+func (store *RedisStore) Ping(requestContext context.Context) error {
+	return store.client.Ping(requestContext).Err()
+}
+
+func (store *RedisStore) Reserve(requestContext context.Context, key, fingerprint string, lockTTL time.Duration) (interfaces.IdempotencyRecord, bool, error) {
+	record := interfaces.IdempotencyRecord{Fingerprint: fingerprint}
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return interfaces.IdempotencyRecord{}, false, err
+	}
+	// ลองสองรอบ เผื่อคีย์เดิมหมดอายุไประหว่าง SETNX กับ GET
+	for range 2 {
+		reserved, err := store.client.SetNX(requestContext, store.keyPrefix+key, encoded, lockTTL).Result()
+		if err != nil {
+			return interfaces.IdempotencyRecord{}, false, err
+		}
+		if reserved {
+			return record, true, nil
+		}
+		raw, err := store.client.Get(requestContext, store.keyPrefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return interfaces.IdempotencyRecord{}, false, err
+		}
+		var existing interfaces.IdempotencyRecord
+		if err := json.Unmarshal(raw, &existing); err != nil {
+			return interfaces.IdempotencyRecord{}, false, err
+		}
+		return existing, false, nil
+	}
+	return interfaces.IdempotencyRecord{}, false, errors.New("idempotency key changed concurrently")
+}
+
+func (store *RedisStore) Complete(requestContext context.Context, key string, record interfaces.IdempotencyRecord, ttl time.Duration) error {
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return store.client.Set(requestContext, store.keyPrefix+key, encoded, ttl).Err()
+}
+
+func (store *RedisStore) Release(requestContext context.Context, key string) error {
+	return store.client.Del(requestContext, store.keyPrefix+key).Err()
+}
@@ -0,0 +1,72 @@
+package idempotency_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/idempotency"
+)
+
+type manualClock struct{ now time.Time }
+
+func (clock *manualClock) Now() time.Time { return clock.now }
+
+// แต่ละ factory คืน store กับฟังก์ชันเดินเวลา (miniredis มีนาฬิกาของตัวเอง)
+var stores = map[string]func(t *testing.T) (interfaces.IdempotencyStore, func(time.Duration)){
+	"memory": func(t *testing.T) (interfaces.IdempotencyStore, func(time.Duration)) {
+		clock := &manualClock{now: time.Now()}
+		return idempotency.NewMemoryStore(clock), func(d time.Duration) { clock.now = clock.now.Add(d) }
+	},
+	"redis": func(t *testing.T) (interfaces.IdempotencyStore, func(time.Duration)) {
+		server := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { _ = client.Close() })
+		return idempotency.NewRedisStore(client, "test:"), server.FastForward
+	},
+}
+
+func TestIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store, advance := newStore(t)
+
+			if _, reserved, err := store.Reserve(ctx, "k", "fp", time.Minute); err != nil || !reserved {
+				t.Fatalf("first Reserve: reserved=%v err=%v", reserved, err)
+			}
+			existing, reserved, err := store.Reserve(ctx, "k", "fp", time.Minute)
+			if err != nil || reserved || existing.Completed || existing.Fingerprint != "fp" {
+				t.Fatalf("second Reserve must see the in-progress record, got %+v reserved=%v err=%v", existing, reserved, err)
+			}
+
+			completed := interfaces.IdempotencyRecord{
+				Fingerprint: "fp",
+				Completed:   true,
+				Response:    interfaces.IdempotentResponse{Status: 201, ContentType: "application/json", Body: []byte(`{"id":1}`)},
+			}
+			if err := store.Complete(ctx, "k", completed, time.Hour); err != nil {
+				t.Fatalf("Complete: %v", err)
+			}
+			existing, _, _ = store.Reserve(ctx, "k", "fp", time.Minute)
+			if !existing.Completed || existing.Response.Status != 201 || string(existing.Response.Body) != `{"id":1}` {
+				t.Fatalf("expected stored response, got %+v", existing)
+			}
+
+			advance(2 * time.Hour)
+			if _, reserved, _ := store.Reserve(ctx, "k", "other", time.Minute); !reserved {
+				t.Fatal("expired key must be reservable again")
+			}
+			if err := store.Release(ctx, "k"); err != nil {
+				t.Fatalf("Release: %v", err)
+			}
+			if _, reserved, _ := store.Reserve(ctx, "k", "fp", time.Minute); !reserved {
+				t.Fatal("released key must be reservable again")
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/config"
+)
+
+// Config = การตั้งค่า logger
+type Config struct {
+	Level    string // debug | info | warn | error
+	Encoding string // json | console
+	// Sampling: ข้อความเดียวกัน (ระดับเดียวกัน) ในหนึ่งวินาที เก็บ SamplingInitial บรรทัดแรก
+	// จากนั้นเก็บทุก ๆ SamplingThereafter บรรทัด; SamplingInitial = 0 คือไม่สุ่มทิ้ง
+	SamplingInitial    int
+	SamplingThereafter int
+}
+
+// ConfigFromEnv อ่าน LOG_LEVEL / LOG_ENCODING / LOG_SAMPLING_INITIAL / LOG_SAMPLING_THEREAFTER
+func ConfigFromEnv() Config {
+	return Config{
+		Level:              config.String("LOG_LEVEL", "info"),
+		Encoding:           config.String("LOG_ENCODING", "json"),
+		SamplingInitial:    config.Int("LOG_SAMPLING_INITIAL", 100),
+		SamplingThereafter: config.Int("LOG_SAMPLING_THEREAFTER", 100),
+	}
+}
+
+// ZapLogger คืออแดปเตอร์ logger ที่ implement interfaces.Logger และ interfaces.LogLevelController
+// logger ที่ได้จาก With ใช้ระดับ log ร่วมกับตัวแม่ (เปลี่ยนที่เดียวมีผลทั้งหมด)
+type ZapLogger struct {
+	inner *zap.SugaredLogger
+	level zap.AtomicLevel
+}
+
+// NewZapLogger สร้าง logger ตาม loggerConfig คืนฟังก์ชัน flush ไว้เรียกตอนปิดโปรแกรม
+func NewZapLogger(loggerConfig Config) (*ZapLogger, func() error, error) {
+	level, err := zap.ParseAtomicLevel(loggerConfig.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var encoder zapcore.Encoder
+	switch loggerConfig.Encoding {
+	case "", "json":
+		encoder = zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	case "console":
+		encoderConfig := zap.NewDevelopmentEncoderConfig()
+		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return nil, nil, fmt.Errorf("unknown LOG_ENCODING %q (want json or console)", loggerConfig.Encoding)
+	}
+
+	core := zapcore.NewCore(encoder, zapcore.Lock(os.Stderr), level)
+	if loggerConfig.SamplingInitial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, 1e9, loggerConfig.SamplingInitial, loggerConfig.SamplingThereafter)
+	}
+	logger := newZapLogger(core, level)
+	return logger, logger.inner.Desugar().Sync, nil
+}
+
+// newZapLogger ห่อ core (AddCallerSkip ให้ caller ชี้ไปที่ผู้เรียก ไม่ใช่ไฟล์นี้)
+func newZapLogger(core zapcore.Core, level zap.AtomicLevel) *ZapLogger {
+	return &ZapLogger{
+		inner: zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1), zap.AddStacktrace(zap.ErrorLevel)).Sugar(),
+		level: level,
+	}
+}
+
+// withContext เติม request id, ข้อมูลผู้เรียก (ถ้ายืนยันตัวตนแล้ว), tenant และ trace/span id (ถ้ามี span) ต่อท้าย keyValues
+func withContext(requestContext context.Context, keyValues []any) []any {
+	if requestContext == nil {
+		return keyValues
+	}
+	if requestID := requestmeta.RequestID(requestContext); requestID != "" {
+		keyValues = append(keyValues, "request_id", requestID)
+	}
+	if principal, ok := requestmeta.PrincipalFrom(requestContext); ok {
+		keyValues = append(keyValues, "user", principal.Subject, "auth", principal.AuthMethod)
+	}
+	if tenantID, ok := requestmeta.TenantFrom(requestContext); ok {
+		keyValues = append(keyValues, "tenant", tenantID)
+	}
+	if spanContext := trace.SpanContextFromContext(requestContext); spanContext.IsValid() {
+		keyValues = append(keyValues, "trace_id", spanContext.TraceID().String(), "span_id", spanContext.SpanID().String())
+	}
+	return keyValues
+}
+
+func (logger *ZapLogger) Debug(requestContext context.Context, message string, keyValues ...any) {
+	// เช็คระดับก่อน ไม่ต้องเสียเวลาประกอบ field ของ context เมื่อปิด debug อยู่
+	if !logger.level.Enabled(zap.DebugLevel) {
+		return
+	}
+	logger.inner.Debugw(message, withContext(requestContext, keyValues)...)
+}
+
+func (logger *ZapLogger) Info(requestContext context.Context, message string, keyValues ...any) {
+	logger.inner.Infow(message, withContext(requestContext, keyValues)...)
+}
+
+func (logger *ZapLogger) Warn(requestContext context.Context, message string, keyValues ...any) {
+	logger.inner.Warnw(message, withContext(requestContext, keyValues)...)
+}
+
+func (logger *ZapLogger) Error(requestContext context.Context, message string, keyValues ...any) {
+	logger.inner.Errorw(message, withContext(requestContext, keyValues)...)
+}
+
+func (logger *ZapLogger) With(keyValues ...any) interfaces.Logger {
+	return &ZapLogger{inner: logger.inner.With(keyValues...), level: logger.level}
+}
+
+// Level คืนระดับ log ปัจจุบัน
+func (logger *ZapLogger) Level() string {
+	return logger.level.String()
+}
+
+// SetLevel เปลี่ยนระดับ log ของทุก logger ที่มาจากตัวเดียวกัน
+func (logger *ZapLogger) SetLevel(level string) error {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	logger.level.SetLevel(parsed)
+	return nil
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+)
+
+func newObservedLogger(level string) (*ZapLogger, *observer.ObservedLogs) {
+	atomicLevel := zap.NewAtomicLevelAt(zap.InfoLevel)
+	_ = atomicLevel.UnmarshalText([]byte(level))
+	core, logs := observer.New(atomicLevel)
+	return newZapLogger(core, atomicLevel), logs
+}
+
+func TestZapLoggerAddsContextFields(t *testing.T) {
+	logger, logs := newObservedLogger("info")
+
+	requestContext := requestmeta.WithRequestID(context.Background(), "req-1")
+	requestContext = requestmeta.WithTenant(requestContext, "acme")
+	requestContext = requestmeta.WithPrincipal(requestContext, requestmeta.Principal{Subject: "alice", AuthMethod: "jwt"})
+	requestContext = trace.ContextWithSpanContext(requestContext, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1}, SpanID: trace.SpanID{2},
+	}))
+	logger.With("component", "books").Info(requestContext, "book created", "id", 7)
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	want := map[string]any{
+		"component":  "books",
+		"id":         int64(7),
+		"request_id": "req-1",
+		"tenant":     "acme",
+		"user":       "alice",
+		"auth":       "jwt",
+		"trace_id":   trace.TraceID{1}.String(),
+		"span_id":    trace.SpanID{2}.String(),
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Fatalf("field %s = %v, want %v (all: %v)", key, fields[key], value, fields)
+		}
+	}
+}
+
+func TestZapLoggerOmitsUnsetTenant(t *testing.T) {
+	logger, logs := newObservedLogger("info")
+	logger.Info(context.Background(), "started")
+
+	if _, found := logs.All()[0].ContextMap()["tenant"]; found {
+		t.Fatal("tenant field should be omitted when the request has no tenant")
+	}
+}
+
+func TestZapLoggerSetLevelAffectsDerivedLoggers(t *testing.T) {
+	logger, logs := newObservedLogger("info")
+	derived := logger.With("component", "cache")
+
+	derived.Debug(context.Background(), "hidden")
+	if logs.Len() != 0 {
+		t.Fatalf("debug logged at info level")
+	}
+	if err := logger.SetLevel("debug"); err != nil {
+		t.Fatal(err)
+	}
+	derived.Debug(context.Background(), "visible")
+	if logs.Len() != 1 || logger.Level() != "debug" {
+		t.Fatalf("after SetLevel(debug): %d entries, level %s", logs.Len(), logger.Level())
+	}
+	if err := logger.SetLevel("verbose"); err == nil {
+		t.Fatal("SetLevel accepted an unknown level")
+	}
+}
+
+func TestNewZapLoggerRejectsUnknownEncoding(t *testing.T) {
+	if _, _, err := NewZapLogger(Config{Level: "info", Encoding: "xml"}); err == nil {
+		t.Fatal("NewZapLogger accepted encoding xml")
+	}
+	if _, _, err := NewZapLogger(Config{Level: "loud", Encoding: "json"}); err == nil {
+		t.Fatal("NewZapLogger accepted level loud")
+	}
+}
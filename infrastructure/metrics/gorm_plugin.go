@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const queryStartedKey = "metrics:query_started"
+
+// gormPlugin จับเวลาทุก query ของ GORM ผ่าน callback ก่อน/หลังของแต่ละ processor
+type gormPlugin struct {
+	metrics *Metrics
+	role    string
+}
+
+// InstrumentGorm ติด plugin จับเวลา query ให้ database (role = primary/replica ไว้แยกใน label)
+func (metrics *Metrics) InstrumentGorm(database *gorm.DB, role string) error {
+	return database.Use(&gormPlugin{metrics: metrics, role: role})
+}
+
+func (plugin *gormPlugin) Name() string { return "metrics:" + plugin.role }
+
+func (plugin *gormPlugin) Initialize(database *gorm.DB) error {
+	callbacks := database.Callback()
+	prefix := plugin.Name()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register(prefix+":before_create", startQuery),
+		callbacks.Create().After("gorm:create").Register(prefix+":after_create", plugin.finishQuery("create")),
+		callbacks.Query().Before("gorm:query").Register(prefix+":before_query", startQuery),
+		callbacks.Query().After("gorm:query").Register(prefix+":after_query", plugin.finishQuery("query")),
+		callbacks.Update().Before("gorm:update").Register(prefix+":before_update", startQuery),
+		callbacks.Update().After("gorm:update").Register(prefix+":after_update", plugin.finishQuery("update")),
+		callbacks.Delete().Before("gorm:delete").Register(prefix+":before_delete", startQuery),
+		callbacks.Delete().After("gorm:delete").Register(prefix+":after_delete", plugin.finishQuery("delete")),
+		callbacks.Row().Before("gorm:row").Register(prefix+":before_row", startQuery),
+		callbacks.Row().After("gorm:row").Register(prefix+":after_row", plugin.finishQuery("row")),
+		callbacks.Raw().Before("gorm:raw").Register(prefix+":before_raw", startQuery),
+		callbacks.Raw().After("gorm:raw").Register(prefix+":after_raw", plugin.finishQuery("raw")),
+	)
+}
+
+func startQuery(database *gorm.DB) {
+	database.InstanceSet(queryStartedKey, time.Now())
+}
+
+func (plugin *gormPlugin) finishQuery(operation string) func(database *gorm.DB) {
+	return func(database *gorm.DB) { plugin.observeQuery(database, operation) }
+}
+
+func (plugin *gormPlugin) observeQuery(database *gorm.DB, operation string) {
+	value, found := database.InstanceGet(queryStartedKey)
+	started, isTime := value.(time.Time)
+	if !found || !isTime {
+		return
+	}
+	table := database.Statement.Table
+	if table == "" {
+		table = "-"
+	}
+	plugin.metrics.queryDuration.WithLabelValues(plugin.role, operation, table).Observe(time.Since(started).Seconds())
+}
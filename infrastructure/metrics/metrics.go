@@ -0,0 +1,126 @@
+// Package metrics = อแดปเตอร์ Prometheus: ตัวนับ HTTP, use case, query ของ GORM, connection pool และ runtime ของ Go
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics รวม collector ทั้งหมดไว้ใน registry ของตัวเอง (ไม่ใช้ global ของ prometheus ให้เทสสร้างใหม่ได้)
+// implement interfaces.OperationMetrics และ middleware.RequestObserver
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests      *prometheus.CounterVec
+	httpDuration      *prometheus.HistogramVec
+	operations        *prometheus.CounterVec
+	operationErrors   *prometheus.CounterVec
+	operationDuration *prometheus.HistogramVec
+	queryDuration     *prometheus.HistogramVec
+	accessLogFailures prometheus.Counter
+	accessLogDropped  prometheus.Counter
+}
+
+// New สร้าง Metrics พร้อม collector ของ Go runtime และ process
+func New() *Metrics {
+	metrics := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method, route template and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "usecase_operations_total",
+			Help: "Use case operations by operation and outcome (success or error).",
+		}, []string{"operation", "outcome"}),
+		operationErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "usecase_errors_total",
+			Help: "Use case errors by operation and domain error type.",
+		}, []string{"operation", "error"}),
+		operationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "usecase_operation_duration_seconds",
+			Help:    "Use case operation latency.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "GORM query latency by database role, operation and table.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"role", "operation", "table"}),
+		accessLogFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "accesslog_write_failures_total",
+			Help: "Access log lines that could not be written.",
+		}),
+		accessLogDropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "accesslog_dropped_lines_total",
+			Help: "Access log lines dropped because the write queue was full.",
+		}),
+	}
+	metrics.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		metrics.httpRequests,
+		metrics.httpDuration,
+		metrics.operations,
+		metrics.operationErrors,
+		metrics.operationDuration,
+		metrics.queryDuration,
+		metrics.accessLogFailures,
+		metrics.accessLogDropped,
+	)
+	return metrics
+}
+
+// Handler = GET /metrics ในรูปแบบ Prometheus text exposition
+func (metrics *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(metrics.registry, promhttp.HandlerOpts{Registry: metrics.registry})
+}
+
+// Gatherer คืน registry ไว้ให้ exporter อื่นหรือเทสอ่านค่าได้
+func (metrics *Metrics) Gatherer() prometheus.Gatherer {
+	return metrics.registry
+}
+
+// ObserveRequest บันทึก request หนึ่งครั้ง (route = template ของ gin เช่น /api/v1/books/:id)
+func (metrics *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	statusLabel := strconv.Itoa(status)
+	metrics.httpRequests.WithLabelValues(method, route, statusLabel).Inc()
+	metrics.httpDuration.WithLabelValues(method, route, statusLabel).Observe(duration.Seconds())
+}
+
+// ObserveOperation บันทึกการทำงานของ use case หนึ่งครั้ง (errorType ว่าง = สำเร็จ)
+func (metrics *Metrics) ObserveOperation(operation string, duration time.Duration, errorType string) {
+	outcome := "success"
+	if errorType != "" {
+		outcome = "error"
+		metrics.operationErrors.WithLabelValues(operation, errorType).Inc()
+	}
+	metrics.operations.WithLabelValues(operation, outcome).Inc()
+	metrics.operationDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// AccessLogWriteFailed นับบรรทัด access log ที่เขียนไม่สำเร็จ
+func (metrics *Metrics) AccessLogWriteFailed(error) {
+	metrics.accessLogFailures.Inc()
+}
+
+// AccessLogLineDropped นับบรรทัด access log ที่ถูกทิ้งเพราะคิวเต็ม
+func (metrics *Metrics) AccessLogLineDropped() {
+	metrics.accessLogDropped.Inc()
+}
+
+// RegisterDBStats เปิด metrics ของ connection pool (go_sql_*) ติดป้าย db_name
+func (metrics *Metrics) RegisterDBStats(sqlDatabase *sql.DB, name string) error {
+	return metrics.registry.Register(collectors.NewDBStatsCollector(sqlDatabase, name))
+}
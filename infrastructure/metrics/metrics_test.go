@@ -0,0 +1,83 @@
+package metrics_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/metrics"
+	gormp "github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/gorm"
+)
+
+func TestMetricsUseCaseCounters(t *testing.T) {
+	appMetrics := metrics.New()
+	appMetrics.ObserveOperation("books.get", time.Millisecond, "")
+	appMetrics.ObserveOperation("books.get", time.Millisecond, "not_found")
+
+	expected := `
+# HELP usecase_errors_total Use case errors by operation and domain error type.
+# TYPE usecase_errors_total counter
+usecase_errors_total{error="not_found",operation="books.get"} 1
+# HELP usecase_operations_total Use case operations by operation and outcome (success or error).
+# TYPE usecase_operations_total counter
+usecase_operations_total{operation="books.get",outcome="error"} 1
+usecase_operations_total{operation="books.get",outcome="success"} 1
+`
+	if err := testutil.GatherAndCompare(appMetrics.Gatherer(), strings.NewReader(expected),
+		"usecase_errors_total", "usecase_operations_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMetricsInstrumentGorm(t *testing.T) {
+	database, err := gormp.OpenWith(gormp.DriverSQLite, filepath.Join(t.TempDir(), "metrics.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	appMetrics := metrics.New()
+	if err := appMetrics.InstrumentGorm(database, "primary"); err != nil {
+		t.Fatal(err)
+	}
+	sqlDatabase, err := database.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := appMetrics.RegisterDBStats(sqlDatabase, "primary"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := database.Exec("CREATE TABLE widgets (id INTEGER PRIMARY KEY)").Error; err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	if err := database.Table("widgets").Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	families, err := appMetrics.Gatherer().Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]bool{}
+	for _, family := range families {
+		found[family.GetName()] = true
+		if family.GetName() != "db_query_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			found[labels["operation"]+" "+labels["table"]] = metric.GetHistogram().GetSampleCount() > 0
+		}
+	}
+	for _, name := range []string{"db_query_duration_seconds", "go_sql_open_connections", "go_goroutines", "raw -", "query widgets"} {
+		if !found[name] {
+			t.Fatalf("missing %q in gathered metrics", name)
+		}
+	}
+}
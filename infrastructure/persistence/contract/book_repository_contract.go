@@ -0,0 +1,219 @@
+// Package contract รวมชุดเทสพฤติกรรมที่ทุกอแดปเตอร์ของ interfaces.BookRepository ต้องผ่าน
+// (แพ็กเกจนี้ถูก import จากไฟล์ _test.go ของแต่ละอแดปเตอร์เท่านั้น)
+package contract
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+// ctx = context ที่ใช้ทุกการเรียกในชุดเทส
+var ctx = context.Background()
+
+// RepositoryFactory ต้องคืน repository ที่ "ว่างเปล่า" ใหม่ทุกครั้งที่ถูกเรียก
+type RepositoryFactory func(t *testing.T) interfaces.BookRepository
+
+// RunBookRepositoryContract รันทุกกรณีของสัญญา BookRepository กับอแดปเตอร์ที่ได้จาก factory
+func RunBookRepositoryContract(t *testing.T, newRepository RepositoryFactory) {
+	t.Run("CreateAssignsIncreasingIDs", func(t *testing.T) {
+		repository := newRepository(t)
+		first := mustCreate(t, repository, "Clean Code", "Robert C. Martin")
+		second := mustCreate(t, repository, "Refactoring", "Martin Fowler")
+		if first.ID == 0 || second.ID <= first.ID {
+			t.Fatalf("expected increasing non-zero ids, got %d then %d", first.ID, second.ID)
+		}
+	})
+
+	t.Run("GetByIDReturnsStoredBook", func(t *testing.T) {
+		repository := newRepository(t)
+		created := mustCreate(t, repository, "Domain-Driven Design", "Eric Evans")
+
+		loaded, err := repository.GetByID(ctx, created.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if loaded.Title != created.Title || loaded.Author != created.Author {
+			t.Fatalf("unexpected book: %+v", loaded)
+		}
+		if !loaded.CreatedAt.Equal(created.CreatedAt) || !loaded.UpdatedAt.Equal(created.UpdatedAt) {
+			t.Fatalf("timestamps not preserved: got %v/%v want %v/%v",
+				loaded.CreatedAt, loaded.UpdatedAt, created.CreatedAt, created.UpdatedAt)
+		}
+		if loaded.DeletedAt != nil {
+			t.Fatalf("expected DeletedAt nil, got %v", *loaded.DeletedAt)
+		}
+	})
+
+	t.Run("GetByIDUnknownReturnsErrNotFound", func(t *testing.T) {
+		repository := newRepository(t)
+		if _, err := repository.GetByID(ctx, 999999); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("ListReturnsActiveBooksOrderedByID", func(t *testing.T) {
+		repository := newRepository(t)
+		first := mustCreate(t, repository, "Book A", "Author A")
+		deleted := mustCreate(t, repository, "Book B", "Author B")
+		third := mustCreate(t, repository, "Book C", "Author C")
+		if err := repository.SoftDelete(ctx, deleted.ID); err != nil {
+			t.Fatalf("SoftDelete: %v", err)
+		}
+
+		books, err := repository.List(ctx)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(books) != 2 || books[0].ID != first.ID || books[1].ID != third.ID {
+			t.Fatalf("unexpected list: %+v", books)
+		}
+	})
+
+	t.Run("SoftDeleteHidesBook", func(t *testing.T) {
+		repository := newRepository(t)
+		created := mustCreate(t, repository, "Temporary", "Someone")
+		if err := repository.SoftDelete(ctx, created.ID); err != nil {
+			t.Fatalf("SoftDelete: %v", err)
+		}
+		if _, err := repository.GetByID(ctx, created.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound after delete, got %v", err)
+		}
+		if err := repository.SoftDelete(ctx, created.ID); err != nil {
+			t.Fatalf("second SoftDelete should be a no-op, got %v", err)
+		}
+	})
+
+	t.Run("ExistsActiveByTitleIsCaseInsensitive", func(t *testing.T) {
+		repository := newRepository(t)
+		created := mustCreate(t, repository, "The Pragmatic Programmer", "Hunt & Thomas")
+
+		exists, err := repository.ExistsActiveByTitle(ctx, "the pragmatic programmer", nil)
+		if err != nil || !exists {
+			t.Fatalf("expected title to exist, got %v (err %v)", exists, err)
+		}
+		exists, err = repository.ExistsActiveByTitle(ctx, "the pragmatic programmer", &created.ID)
+		if err != nil || exists {
+			t.Fatalf("expected excluded id to be ignored, got %v (err %v)", exists, err)
+		}
+	})
+
+	t.Run("SoftDeletedTitleCanBeReused", func(t *testing.T) {
+		repository := newRepository(t)
+		created := mustCreate(t, repository, "Reusable", "First Author")
+		if err := repository.SoftDelete(ctx, created.ID); err != nil {
+			t.Fatalf("SoftDelete: %v", err)
+		}
+
+		exists, err := repository.ExistsActiveByTitle(ctx, "reusable", nil)
+		if err != nil || exists {
+			t.Fatalf("deleted title must not count, got %v (err %v)", exists, err)
+		}
+		reused := mustCreate(t, repository, "REUSABLE", "Second Author")
+		if reused.ID == created.ID {
+			t.Fatalf("ids must not be reused, got %d twice", reused.ID)
+		}
+	})
+
+	t.Run("CreateDuplicateActiveTitleFails", func(t *testing.T) {
+		repository := newRepository(t)
+		mustCreate(t, repository, "Unique Title", "Author")
+
+		duplicate := newBook("unique title", "Other Author")
+		if err := repository.Create(ctx, &duplicate); !errors.Is(err, domain.ErrTitleExists) {
+			t.Fatalf("expected ErrTitleExists, got %v", err)
+		}
+	})
+
+	t.Run("UpdateChangesFields", func(t *testing.T) {
+		repository := newRepository(t)
+		created := mustCreate(t, repository, "Old Title", "Old Author")
+
+		created.Title = "New Title"
+		created.Author = "New Author"
+		created.UpdatedAt = created.UpdatedAt.Add(time.Hour)
+		if err := repository.Update(ctx, &created); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		loaded, err := repository.GetByID(ctx, created.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if loaded.Title != "New Title" || loaded.Author != "New Author" {
+			t.Fatalf("update not applied: %+v", loaded)
+		}
+		if !loaded.UpdatedAt.Equal(created.UpdatedAt) {
+			t.Fatalf("UpdatedAt not applied: got %v want %v", loaded.UpdatedAt, created.UpdatedAt)
+		}
+	})
+
+	t.Run("UpdateToDuplicateTitleFails", func(t *testing.T) {
+		repository := newRepository(t)
+		mustCreate(t, repository, "Taken", "Author")
+		other := mustCreate(t, repository, "Free", "Author")
+
+		other.Title = "TAKEN"
+		if err := repository.Update(ctx, &other); !errors.Is(err, domain.ErrTitleExists) {
+			t.Fatalf("expected ErrTitleExists, got %v", err)
+		}
+	})
+
+	t.Run("TenantsAreIsolated", func(t *testing.T) {
+		repository := newRepository(t)
+		tenantA := requestmeta.WithTenant(ctx, "tenant-a")
+		tenantB := requestmeta.WithTenant(ctx, "tenant-b")
+
+		bookA := newBook("Shared Title", "Author A")
+		if err := repository.Create(tenantA, &bookA); err != nil {
+			t.Fatalf("Create in tenant A: %v", err)
+		}
+		bookB := newBook("shared title", "Author B")
+		if err := repository.Create(tenantB, &bookB); err != nil {
+			t.Fatalf("same title in another tenant must be allowed, got %v", err)
+		}
+
+		if _, err := repository.GetByID(tenantB, bookA.ID); !errors.Is(err, domain.ErrNotFound) {
+			t.Fatalf("tenant B must not see tenant A's book, got %v", err)
+		}
+		books, err := repository.List(tenantA)
+		if err != nil || len(books) != 1 || books[0].ID != bookA.ID {
+			t.Fatalf("tenant A list: %+v (err %v)", books, err)
+		}
+		if books, _ := repository.List(ctx); len(books) != 0 {
+			t.Fatalf("default tenant must be empty, got %+v", books)
+		}
+
+		bookA.Title = "Hijacked"
+		if err := repository.Update(tenantB, &bookA); err != nil {
+			t.Fatalf("cross-tenant Update should be a no-op, got %v", err)
+		}
+		if err := repository.SoftDelete(tenantB, bookA.ID); err != nil {
+			t.Fatalf("cross-tenant SoftDelete should be a no-op, got %v", err)
+		}
+		loaded, err := repository.GetByID(tenantA, bookA.ID)
+		if err != nil || loaded.Title != "Shared Title" {
+			t.Fatalf("tenant A's book must be untouched, got %+v (err %v)", loaded, err)
+		}
+	})
+}
+
+// newBook ตัดความละเอียดเวลาเหลือไมโครวินาที ให้ตรงกับที่ฐานข้อมูลเก็บได้
+func newBook(title, author string) domain.Book {
+	now := time.Now().Truncate(time.Microsecond)
+	return domain.Book{Title: title, Author: author, CreatedAt: now, UpdatedAt: now}
+}
+
+func mustCreate(t *testing.T, repository interfaces.BookRepository, title, author string) domain.Book {
+	t.Helper()
+	book := newBook(title, author)
+	if err := repository.Create(ctx, &book); err != nil {
+		t.Fatalf("Create(%q): %v", title, err)
+	}
+	return book
+}
@@ -0,0 +1,126 @@
+package gormp
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+// apiKeyRecord = ตาราง api_keys (secret เก็บเป็น hash เท่านั้น)
+type apiKeyRecord struct {
+	ID         uint   `gorm:"primaryKey"`
+	TenantID   string `gorm:"not null"`
+	Name       string `gorm:"not null"`
+	Prefix     string `gorm:"not null"`
+	SecretHash string `gorm:"not null"`
+	Scope      string `gorm:"not null"`
+	CreatedBy  string `gorm:"not null"`
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+func (apiKeyRecord) TableName() string { return "api_keys" }
+
+// APIKeyRepositoryGorm = อแดปเตอร์ของ interfaces.APIKeyRepository
+// อ่าน/เขียนที่ primary เสมอ เพื่อให้การเพิกถอนมีผลทันที (ไม่ติด replica lag)
+type APIKeyRepositoryGorm struct {
+	database *gorm.DB
+}
+
+func NewAPIKeyRepositoryGorm(database *gorm.DB) interfaces.APIKeyRepository {
+	return &APIKeyRepositoryGorm{database: database}
+}
+
+func apiKeyToDomain(record apiKeyRecord) domain.APIKey {
+	return domain.APIKey{
+		ID:         record.ID,
+		TenantID:   record.TenantID,
+		Name:       record.Name,
+		Prefix:     record.Prefix,
+		SecretHash: record.SecretHash,
+		Scope:      record.Scope,
+		CreatedBy:  record.CreatedBy,
+		CreatedAt:  record.CreatedAt,
+		ExpiresAt:  record.ExpiresAt,
+		LastUsedAt: record.LastUsedAt,
+		RevokedAt:  record.RevokedAt,
+	}
+}
+
+func (repository *APIKeyRepositoryGorm) Create(requestContext context.Context, key *domain.APIKey) error {
+	record := apiKeyRecord{
+		TenantID:   key.TenantID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		SecretHash: key.SecretHash,
+		Scope:      key.Scope,
+		CreatedBy:  key.CreatedBy,
+		CreatedAt:  key.CreatedAt,
+		ExpiresAt:  key.ExpiresAt,
+	}
+	if err := repository.database.WithContext(requestContext).Create(&record).Error; err != nil {
+		return err
+	}
+	key.ID = record.ID
+	return nil
+}
+
+func (repository *APIKeyRepositoryGorm) GetByPrefix(requestContext context.Context, prefix string) (domain.APIKey, error) {
+	var record apiKeyRecord
+	err := repository.database.WithContext(requestContext).Where("prefix = ?", prefix).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.APIKey{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	return apiKeyToDomain(record), nil
+}
+
+func (repository *APIKeyRepositoryGorm) List(requestContext context.Context) ([]domain.APIKey, error) {
+	var records []apiKeyRecord
+	err := repository.database.WithContext(requestContext).
+		Where("tenant_id = ?", requestmeta.TenantID(requestContext)).
+		Order("id").Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make([]domain.APIKey, 0, len(records))
+	for _, record := range records {
+		result = append(result, apiKeyToDomain(record))
+	}
+	return result, nil
+}
+
+func (repository *APIKeyRepositoryGorm) Revoke(requestContext context.Context, id uint, revokedAt time.Time) error {
+	return repository.database.WithContext(requestContext).Transaction(func(transaction *gorm.DB) error {
+		var record apiKeyRecord
+		err := transaction.Select("id").
+			Where("tenant_id = ?", requestmeta.TenantID(requestContext)).
+			First(&record, id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrNotFound
+			}
+			return err
+		}
+		return transaction.Model(&apiKeyRecord{}).
+			Where("id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", revokedAt).Error
+	})
+}
+
+func (repository *APIKeyRepositoryGorm) TouchLastUsed(requestContext context.Context, id uint, usedAt time.Time) error {
+	return repository.database.WithContext(requestContext).
+		Model(&apiKeyRecord{}).
+		Where("id = ?", id).
+		Update("last_used_at", usedAt).Error
+}
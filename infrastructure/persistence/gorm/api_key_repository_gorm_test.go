@@ -0,0 +1,50 @@
+package gormp_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+	gormp "github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/gorm"
+)
+
+func TestAPIKeyRepositoryGormSQLite(t *testing.T) {
+	ctx := context.Background()
+	database := openMigrated(t, gormp.DriverSQLite, filepath.Join(t.TempDir(), "keys.db"))
+	repository := gormp.NewAPIKeyRepositoryGorm(database)
+
+	now := time.Now().Truncate(time.Microsecond)
+	key := domain.APIKey{TenantID: requestmeta.DefaultTenant, Name: "import", Prefix: "abcd1234", SecretHash: "hash", Scope: domain.APIKeyScopeReadOnly, CreatedAt: now}
+	if err := repository.Create(ctx, &key); err != nil || key.ID == 0 {
+		t.Fatalf("Create: id=%d err=%v", key.ID, err)
+	}
+	duplicate := key
+	if err := repository.Create(ctx, &duplicate); err == nil {
+		t.Fatal("expected duplicate prefix to be rejected")
+	}
+
+	if err := repository.TouchLastUsed(ctx, key.ID, now.Add(time.Minute)); err != nil {
+		t.Fatalf("TouchLastUsed: %v", err)
+	}
+	if err := repository.Revoke(ctx, key.ID, now.Add(2*time.Minute)); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if err := repository.Revoke(ctx, 999, now); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	loaded, err := repository.GetByPrefix(ctx, "abcd1234")
+	if err != nil {
+		t.Fatalf("GetByPrefix: %v", err)
+	}
+	if loaded.LastUsedAt == nil || loaded.RevokedAt == nil || loaded.IsUsableAt(now.Add(time.Hour)) {
+		t.Fatalf("unexpected key state %+v", loaded)
+	}
+	if _, err := repository.GetByPrefix(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+package gormp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+// bookRecord = โครงสร้างตารางจริงในฐานข้อมูล (เลเยอร์ infrastructure)
+// แยกออกจาก domain.Book เพื่อให้ mapping/constraint เป็นเรื่องฝั่ง infra
+type bookRecord struct {
+	ID        uint           `gorm:"primaryKey"`
+	TenantID  string         `gorm:"not null"`
+	Title     string         `gorm:"not null"`
+	Author    string         `gorm:"not null"`
+	CreatedAt time.Time      `gorm:"not null"`
+	UpdatedAt time.Time      `gorm:"not null"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (bookRecord) TableName() string { return "books" }
+
+// BookRepositoryGorm = อแดปเตอร์ที่ implement พอร์ต interfaces.BookRepository
+// ทุก query ถูกกรองด้วย tenant ของ request (requestmeta.TenantID)
+// การเขียนและ ExistsActiveByTitle (ใช้ใน write path) ไปที่ primary เสมอ
+// List/GetByID ไปที่ replica ถ้ามี (ยกเว้น client ที่เพิ่งเขียน หรือ context ขอ WithStrongRead)
+type BookRepositoryGorm struct {
+	database     *gorm.DB
+	replicas     *ReplicaSet
+	writeTracker *writeTracker
+	retryPolicy  RetryPolicy
+	rowSecurity  bool
+}
+
+// Option ปรับแต่ง BookRepositoryGorm ตอนสร้าง
+type Option func(repository *BookRepositoryGorm)
+
+// WithRetryPolicy กำหนดการลองใหม่เมื่อเจอ error ชั่วคราว (ค่าเริ่มต้นคือไม่ retry)
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(repository *BookRepositoryGorm) { repository.retryPolicy = policy }
+}
+
+// WithReplicas ส่งการอ่านไปยัง read replica (round-robin, ข้ามตัวที่ไม่ healthy)
+func WithReplicas(replicas *ReplicaSet) Option {
+	return func(repository *BookRepositoryGorm) { repository.replicas = replicas }
+}
+
+// WithReadYourWrites ปักหมุด client (requestmeta.ClientKey) ให้อ่านจาก primary
+// เป็นเวลา window หลังจากที่เขียนสำเร็จ กันอ่านเจอข้อมูลเก่าเพราะ replica lag
+func WithReadYourWrites(window time.Duration) Option {
+	return func(repository *BookRepositoryGorm) {
+		if window > 0 {
+			repository.writeTracker = newWriteTracker(window)
+		}
+	}
+}
+
+// WithRowLevelSecurity ให้ทุก query รันใน transaction ที่ตั้ง app.tenant_id ไว้
+// เพื่อให้ policy books_tenant_isolation ของ Postgres บังคับอีกชั้น (ดู ConfigureRowLevelSecurity)
+func WithRowLevelSecurity() Option {
+	return func(repository *BookRepositoryGorm) { repository.rowSecurity = true }
+}
+
+func NewBookRepositoryGorm(database *gorm.DB, options ...Option) interfaces.BookRepository {
+	repository := &BookRepositoryGorm{
+		database:    database,
+		retryPolicy: RetryPolicy{MaxAttempts: 1},
+	}
+	for _, option := range options {
+		option(repository)
+	}
+	return repository
+}
+
+// PinnedToPrimary = true ถ้า client ของ request นี้เพิ่งเขียนและยังอยู่ในช่วง read-your-writes
+// (decorator อย่างแคชใช้ตัดสินใจข้ามแคช ไม่ให้ client เห็นข้อมูลก่อนที่ตัวเองเขียน)
+func (repository *BookRepositoryGorm) PinnedToPrimary(requestContext context.Context) bool {
+	return repository.writeTracker.isPinned(requestmeta.ClientKey(requestContext))
+}
+
+// reader เลือกฐานข้อมูลสำหรับการอ่าน
+func (repository *BookRepositoryGorm) reader(requestContext context.Context) *gorm.DB {
+	if requestmeta.StrongReadRequested(requestContext) || repository.PinnedToPrimary(requestContext) {
+		return repository.writer(requestContext)
+	}
+	if replica := repository.replicas.pick(); replica != nil {
+		return replica.WithContext(requestContext)
+	}
+	return repository.writer(requestContext)
+}
+
+// writer = primary
+func (repository *BookRepositoryGorm) writer(requestContext context.Context) *gorm.DB {
+	return repository.database.WithContext(requestContext)
+}
+
+// inTenant รัน query ที่กรองด้วย tenant ของ request แล้ว
+// โหมด row-level security: ครอบด้วย transaction และตั้ง app.tenant_id (มีผลแค่ใน transaction นั้น)
+func (repository *BookRepositoryGorm) inTenant(requestContext context.Context, database *gorm.DB, run func(query *gorm.DB) error) error {
+	tenantID := requestmeta.TenantID(requestContext)
+	if !repository.rowSecurity {
+		return run(database.Where("tenant_id = ?", tenantID))
+	}
+	return database.Transaction(func(transaction *gorm.DB) error {
+		if err := transaction.Exec("SELECT set_config('app.tenant_id', ?, true)", tenantID).Error; err != nil {
+			return err
+		}
+		return run(transaction.Where("tenant_id = ?", tenantID))
+	})
+}
+
+// afterWrite จดว่า client นี้เพิ่งเขียน (ใช้กับ read-your-writes)
+func (repository *BookRepositoryGorm) afterWrite(requestContext context.Context, err error) error {
+	if err == nil {
+		repository.writeTracker.recordWrite(requestmeta.ClientKey(requestContext))
+	}
+	return err
+}
+
+func toDomain(record bookRecord) domain.Book {
+	var deletedAt *time.Time
+	if record.DeletedAt.Valid {
+		t := record.DeletedAt.Time
+		deletedAt = &t
+	}
+	return domain.Book{
+		ID:        record.ID,
+		Title:     record.Title,
+		Author:    record.Author,
+		CreatedAt: record.CreatedAt,
+		UpdatedAt: record.UpdatedAt,
+		DeletedAt: deletedAt,
+	}
+}
+
+// translateError แปลง error ของ driver ให้เป็น error ระดับโดเมน
+// (ต้องเปิด TranslateError ใน gorm.Config ถึงจะได้ gorm.ErrDuplicatedKey)
+func translateError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrTitleExists // ชน unique index ux_books_title_active
+	}
+	return err
+}
+
+func (repository *BookRepositoryGorm) List(requestContext context.Context) ([]domain.Book, error) {
+	var records []bookRecord
+	err := repository.retryPolicy.retryRead(func() error {
+		records = nil
+		return repository.inTenant(requestContext, repository.reader(requestContext), func(query *gorm.DB) error {
+			return query.Order("id").Find(&records).Error
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]domain.Book, 0, len(records))
+	for _, r := range records {
+		result = append(result, toDomain(r))
+	}
+	return result, nil
+}
+
+func (repository *BookRepositoryGorm) GetByID(requestContext context.Context, id uint) (domain.Book, error) {
+	var record bookRecord
+	err := repository.retryPolicy.retryRead(func() error {
+		return repository.inTenant(requestContext, repository.reader(requestContext), func(query *gorm.DB) error {
+			return query.First(&record, id).Error
+		})
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return domain.Book{}, domain.ErrNotFound
+		}
+		return domain.Book{}, err
+	}
+	return toDomain(record), nil
+}
+
+// ExistsActiveByTitle อ่านจาก primary เสมอ เพราะถูกใช้ตัดสินใจก่อนเขียน
+func (repository *BookRepositoryGorm) ExistsActiveByTitle(requestContext context.Context, title string, excludeID *uint) (bool, error) {
+	var count int64
+	err := repository.retryPolicy.retryRead(func() error {
+		// สร้าง query ใหม่ทุกรอบ (statement ของ gorm ใช้ซ้ำข้ามการ retry ไม่ได้)
+		return repository.inTenant(requestContext, repository.writer(requestContext), func(query *gorm.DB) error {
+			query = query.Model(&bookRecord{}).
+				Where("lower(title) = ? AND deleted_at IS NULL", strings.ToLower(title))
+			if excludeID != nil {
+				query = query.Where("id <> ?", *excludeID)
+			}
+			return query.Count(&count).Error
+		})
+	})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (repository *BookRepositoryGorm) Create(requestContext context.Context, book *domain.Book) error {
+	record := bookRecord{
+		TenantID:  requestmeta.TenantID(requestContext),
+		Title:     book.Title,
+		Author:    book.Author,
+		CreatedAt: book.CreatedAt,
+		UpdatedAt: book.UpdatedAt,
+	}
+	err := repository.retryPolicy.retryWrite(func() error {
+		record.ID = 0 // ให้ฐานข้อมูลออก id ใหม่ถ้ารอบก่อน rollback ไป
+		return repository.inTenant(requestContext, repository.writer(requestContext), func(query *gorm.DB) error {
+			return query.Create(&record).Error
+		})
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	book.ID = record.ID
+	return repository.afterWrite(requestContext, nil)
+}
+
+func (repository *BookRepositoryGorm) Update(requestContext context.Context, book *domain.Book) error {
+	err := repository.retryPolicy.retryWrite(func() error {
+		return repository.inTenant(requestContext, repository.writer(requestContext), func(query *gorm.DB) error {
+			return query.Model(&bookRecord{}).
+				Where("id = ?", book.ID).
+				Updates(map[string]any{
+					"title":      book.Title,
+					"author":     book.Author,
+					"updated_at": book.UpdatedAt,
+				}).Error
+		})
+	})
+	return repository.afterWrite(requestContext, translateError(err))
+}
+
+func (repository *BookRepositoryGorm) SoftDelete(requestContext context.Context, id uint) error {
+	err := repository.retryPolicy.retryWrite(func() error {
+		return repository.inTenant(requestContext, repository.writer(requestContext), func(query *gorm.DB) error {
+			return query.Delete(&bookRecord{}, id).Error
+		})
+	})
+	return repository.afterWrite(requestContext, err)
+}
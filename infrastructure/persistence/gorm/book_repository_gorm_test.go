@@ -0,0 +1,61 @@
+package gormp_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/contract"
+	gormp "github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/gorm"
+)
+
+func openMigrated(t *testing.T, driverName, dataSourceName string) *gorm.DB {
+	t.Helper()
+	database, err := gormp.OpenWith(driverName, dataSourceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := gormp.NewMigrator(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return database
+}
+
+// SQLite รันในโปรเซสได้เลย แต่ละกรณีทดสอบได้ไฟล์ฐานข้อมูลใหม่
+func TestBookRepositoryGormSQLiteContract(t *testing.T) {
+	contract.RunBookRepositoryContract(t, func(t *testing.T) interfaces.BookRepository {
+		database := openMigrated(t, gormp.DriverSQLite, filepath.Join(t.TempDir(), "books.db"))
+		t.Cleanup(func() {
+			if sqlDatabase, err := database.DB(); err == nil {
+				_ = sqlDatabase.Close()
+			}
+		})
+		return gormp.NewBookRepositoryGorm(database)
+	})
+}
+
+// Postgres/MySQL ต้องตั้ง TEST_DB_DRIVER + TEST_DB_DSN ชี้ไปยังฐานข้อมูลทิ้งได้
+// (ตาราง books จะถูกล้างทุกกรณีทดสอบ)
+func TestBookRepositoryGormServerContract(t *testing.T) {
+	dataSourceName := os.Getenv("TEST_DB_DSN")
+	if dataSourceName == "" {
+		t.Skip("TEST_DB_DSN not set")
+	}
+	database := openMigrated(t, os.Getenv("TEST_DB_DRIVER"), dataSourceName)
+
+	contract.RunBookRepositoryContract(t, func(t *testing.T) interfaces.BookRepository {
+		// DELETE แทน TRUNCATE เพราะใช้ได้ทุก engine; id ต่อเนื่องไม่เป็นไรเพราะสัญญาไม่ผูกค่า id
+		if err := database.Exec("DELETE FROM books").Error; err != nil {
+			t.Fatal(err)
+		}
+		return gormp.NewBookRepositoryGorm(database)
+	})
+}
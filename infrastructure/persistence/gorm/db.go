@@ -0,0 +1,157 @@
+package gormp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/config"
+)
+
+// ชื่อ driver ที่รองรับ (ตรงกับค่า DB_DRIVER และ database.Dialector.Name())
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMySQL    = "mysql"
+)
+
+// PoolConfig = ค่าของ connection pool (database/sql) และการรอฐานข้อมูลตอนสตาร์ต
+type PoolConfig struct {
+	MaxOpenConns    int           // DB_MAX_OPEN_CONNS (0 = ไม่จำกัด)
+	MaxIdleConns    int           // DB_MAX_IDLE_CONNS
+	ConnMaxLifetime time.Duration // DB_CONN_MAX_LIFETIME (0 = ไม่หมดอายุ)
+	ConnMaxIdleTime time.Duration // DB_CONN_MAX_IDLE_TIME (0 = ไม่หมดอายุ)
+
+	ConnectTimeout    time.Duration // DB_CONNECT_TIMEOUT: รอฐานข้อมูลตอนบูตได้นานสุดเท่าไร
+	ConnectBackoff    time.Duration // DB_CONNECT_BACKOFF: ระยะรอครั้งแรก (เพิ่มเท่าตัวทุกครั้ง)
+	ConnectBackoffMax time.Duration // DB_CONNECT_BACKOFF_MAX: ระยะรอสูงสุดต่อครั้ง
+}
+
+// PoolConfigFromEnv อ่าน PoolConfig จากตัวแปรแวดล้อม (มีค่าเริ่มต้นที่ใช้ได้เลย)
+func PoolConfigFromEnv() PoolConfig {
+	return PoolConfig{
+		MaxOpenConns:      config.Int("DB_MAX_OPEN_CONNS", 25),
+		MaxIdleConns:      config.Int("DB_MAX_IDLE_CONNS", 10),
+		ConnMaxLifetime:   config.Duration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+		ConnMaxIdleTime:   config.Duration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+		ConnectTimeout:    config.Duration("DB_CONNECT_TIMEOUT", time.Minute),
+		ConnectBackoff:    config.Duration("DB_CONNECT_BACKOFF", 500*time.Millisecond),
+		ConnectBackoffMax: config.Duration("DB_CONNECT_BACKOFF_MAX", 10*time.Second),
+	}
+}
+
+// Open เปิดการเชื่อมต่อฐานข้อมูลตาม DB_DRIVER (ค่าเริ่มต้น postgres) และ DSN จาก DB_DSN
+// ถ้าฐานข้อมูลยังไม่พร้อม (เช่น docker-compose สตาร์ตพร้อมกัน) จะรอแบบ exponential backoff
+// จนครบ DB_CONNECT_TIMEOUT แล้วค่อยยอมแพ้
+// ตัวอย่าง .env:
+// DB_DRIVER=postgres
+// DB_DSN=host=localhost user=postgres password=postgres dbname=books port=5432 sslmode=disable TimeZone=Asia/Bangkok
+//
+// DB_DRIVER=sqlite  DB_DSN=books.db
+// DB_DRIVER=mysql   DB_DSN=root:root@tcp(localhost:3306)/books?parseTime=true
+func Open(logger interfaces.Logger) (*gorm.DB, error) {
+	return OpenWithPool(os.Getenv("DB_DRIVER"), os.Getenv("DB_DSN"), PoolConfigFromEnv(), logger)
+}
+
+// OpenWith เหมือน Open แต่รับ driver/DSN ตรง ๆ และลองแค่ครั้งเดียว (ใช้ในเทสหรือเครื่องมืออื่น)
+func OpenWith(driverName, dataSourceName string) (*gorm.DB, error) {
+	dialector, err := dialectorFor(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	// TranslateError ทำให้ได้ gorm.ErrDuplicatedKey เหมือนกันทุก driver
+	return gorm.Open(dialector, &gorm.Config{TranslateError: true})
+}
+
+// minConnectBackoff = ระยะรอต่ำสุดระหว่างการลองต่อฐานข้อมูลตอนบูต
+const minConnectBackoff = 50 * time.Millisecond
+
+// OpenWithPool เปิดการเชื่อมต่อแบบรอฐานข้อมูล แล้วตั้งค่า pool ตาม poolConfig
+// การลองซ้ำแต่ละครั้งเขียนลง logger (nil = ไม่เขียน)
+func OpenWithPool(driverName, dataSourceName string, poolConfig PoolConfig, logger interfaces.Logger) (*gorm.DB, error) {
+	if _, err := dialectorFor(driverName, dataSourceName); err != nil {
+		return nil, err // ตั้งค่าผิด รอไปก็ไม่หาย
+	}
+	deadline := time.Now().Add(poolConfig.ConnectTimeout)
+	// backoff 0 (หรือเพดาน 0) จะวนต่อฐานข้อมูลรัว ๆ ไม่มีพัก จึงบังคับขั้นต่ำไว้
+	backoff := max(poolConfig.ConnectBackoff, minConnectBackoff)
+	backoffMax := max(poolConfig.ConnectBackoffMax, backoff)
+	for attempt := 1; ; attempt++ {
+		database, err := OpenWith(driverName, dataSourceName)
+		if err == nil {
+			if err := applyPoolConfig(database, poolConfig); err != nil {
+				return nil, err
+			}
+			return database, nil
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return nil, fmt.Errorf("database not reachable after %d attempts: %w", attempt, err)
+		}
+		if logger != nil {
+			logger.Warn(context.Background(), "database not ready, retrying",
+				"attempt", attempt, "retry_in", backoff.String(), "error", err)
+		}
+		time.Sleep(backoff)
+		backoff = min(backoff*2, backoffMax)
+	}
+}
+
+func applyPoolConfig(database *gorm.DB, poolConfig PoolConfig) error {
+	sqlDatabase, err := database.DB()
+	if err != nil {
+		return err
+	}
+	sqlDatabase.SetMaxOpenConns(poolConfig.MaxOpenConns)
+	sqlDatabase.SetMaxIdleConns(poolConfig.MaxIdleConns)
+	sqlDatabase.SetConnMaxLifetime(poolConfig.ConnMaxLifetime)
+	sqlDatabase.SetConnMaxIdleTime(poolConfig.ConnMaxIdleTime)
+	return nil
+}
+
+// PoolStats คืนสถิติของ connection pool (จำนวน open/in-use/idle, เวลารอ ฯลฯ) ไว้ให้ monitoring
+func PoolStats(database *gorm.DB) (sql.DBStats, error) {
+	sqlDatabase, err := database.DB()
+	if err != nil {
+		return sql.DBStats{}, err
+	}
+	return sqlDatabase.Stats(), nil
+}
+
+// Ping ตรวจว่าฐานข้อมูลยังตอบสนอง (ใช้เป็น health check)
+func Ping(requestContext context.Context, database *gorm.DB) error {
+	sqlDatabase, err := database.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDatabase.PingContext(requestContext)
+}
+
+// Close ปิด connection pool ของ database (เรียกตอนปิดโปรแกรมหลัง request ค้างเสร็จหมดแล้ว)
+func Close(database *gorm.DB) error {
+	sqlDatabase, err := database.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDatabase.Close()
+}
+
+func dialectorFor(driverName, dataSourceName string) (gorm.Dialector, error) {
+	switch driverName {
+	case "", DriverPostgres:
+		return postgres.Open(dataSourceName), nil
+	case DriverSQLite:
+		return sqlite.Open(dataSourceName), nil
+	case DriverMySQL:
+		return mysql.Open(dataSourceName), nil
+	default:
+		return nil, fmt.Errorf("unknown DB_DRIVER %q (want postgres, sqlite or mysql)", driverName)
+	}
+}
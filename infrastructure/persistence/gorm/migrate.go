@@ -0,0 +1,328 @@
+package gormp
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ไฟล์ migration แยกตาม dialect: migrations/<driver>/<version>_<name>.(up|down).sql
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// กุญแจของ advisory lock (Postgres ใช้ตัวเลข, MySQL ใช้ชื่อ)
+const (
+	postgresMigrationLockKey = 7_274_101
+	mysqlMigrationLockName   = "books_schema_migrations"
+	mysqlMigrationLockWait   = 60 // วินาที
+)
+
+// Migration = 1 เวอร์ชันของ schema (up/down เป็น SQL ของ dialect ที่ใช้อยู่)
+type Migration struct {
+	Version int
+	Name    string
+	UpSQL   string
+	DownSQL string
+}
+
+// MigrationStatus = สถานะของแต่ละเวอร์ชันสำหรับคำสั่ง migrate status
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time // nil = ยังไม่ถูก apply
+}
+
+// schemaMigrationRecord = ตารางจดว่าเวอร์ชันไหนถูก apply ไปแล้ว
+type schemaMigrationRecord struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigrationRecord) TableName() string { return "schema_migrations" }
+
+// Migrator รัน migration ที่ฝังมากับ binary ภายใต้ lock เพื่อไม่ให้หลาย instance migrate พร้อมกัน
+type Migrator struct {
+	database   *gorm.DB
+	migrations []Migration
+	upToDate   atomic.Bool // EnsureMigrated เคยผ่านแล้ว (ล้างเมื่อ migrator นี้ย้าย schema)
+}
+
+// NewMigrator โหลด migration ของ dialect ที่ database ใช้อยู่
+func NewMigrator(database *gorm.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(database.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{database: database, migrations: migrations}, nil
+}
+
+func loadMigrations(dialect string) ([]Migration, error) {
+	directory := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFiles, directory)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for dialect %q: %w", dialect, err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+		versionText, name, found := strings.Cut(strings.TrimSuffix(fileName, "."+direction+".sql"), "_")
+		if !found {
+			return nil, fmt.Errorf("migration %s: want <version>_<name>.%s.sql", fileName, direction)
+		}
+		version, err := strconv.Atoi(versionText)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: invalid version %q", fileName, versionText)
+		}
+		content, err := fs.ReadFile(migrationFiles, path.Join(directory, fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if direction == "up" {
+			migration.UpSQL = string(content)
+		} else {
+			migration.DownSQL = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.UpSQL == "" || migration.DownSQL == "" {
+			return nil, fmt.Errorf("migration %04d_%s: both up and down files are required", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// LatestVersion คือเวอร์ชันสูงสุดที่ binary นี้รู้จัก
+func (migrator *Migrator) LatestVersion() int {
+	if len(migrator.migrations) == 0 {
+		return 0
+	}
+	return migrator.migrations[len(migrator.migrations)-1].Version
+}
+
+// Up apply ทุกเวอร์ชันที่ยังไม่ถูก apply
+func (migrator *Migrator) Up(requestContext context.Context) error {
+	return migrator.To(requestContext, migrator.LatestVersion())
+}
+
+// Down ย้อนเวอร์ชันล่าสุดที่ถูก apply ไป 1 ขั้น
+func (migrator *Migrator) Down(requestContext context.Context) error {
+	return migrator.withLock(requestContext, func(connection *gorm.DB) error {
+		applied, err := appliedVersions(connection)
+		if err != nil {
+			return err
+		}
+		for index := len(migrator.migrations) - 1; index >= 0; index-- {
+			migration := migrator.migrations[index]
+			if _, ok := applied[migration.Version]; ok {
+				return revertMigration(connection, migration)
+			}
+		}
+		return nil // ไม่มีอะไรให้ย้อน
+	})
+}
+
+// To ย้าย schema ไปที่เวอร์ชัน target (ขึ้นหรือลงก็ได้, 0 = ย้อนทั้งหมด)
+func (migrator *Migrator) To(requestContext context.Context, target int) error {
+	if target < 0 || target > migrator.LatestVersion() {
+		return fmt.Errorf("unknown migration version %d (latest is %d)", target, migrator.LatestVersion())
+	}
+	return migrator.withLock(requestContext, func(connection *gorm.DB) error {
+		applied, err := appliedVersions(connection)
+		if err != nil {
+			return err
+		}
+		// ลงก่อน (จากใหม่ไปเก่า) แล้วค่อยขึ้น (จากเก่าไปใหม่)
+		for index := len(migrator.migrations) - 1; index >= 0; index-- {
+			migration := migrator.migrations[index]
+			if _, ok := applied[migration.Version]; ok && migration.Version > target {
+				if err := revertMigration(connection, migration); err != nil {
+					return err
+				}
+			}
+		}
+		for _, migration := range migrator.migrations {
+			if _, ok := applied[migration.Version]; !ok && migration.Version <= target {
+				if err := applyMigration(connection, migration); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Status คืนทุกเวอร์ชันที่รู้จักพร้อมเวลาที่ถูก apply
+func (migrator *Migrator) Status(requestContext context.Context) ([]MigrationStatus, error) {
+	connection := migrator.database.WithContext(requestContext)
+	if err := connection.AutoMigrate(&schemaMigrationRecord{}); err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(connection)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(migrator.migrations))
+	for _, migration := range migrator.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// withLock จองการเชื่อมต่อเดียวตลอดงาน แล้วถือ advisory lock ระหว่างรัน
+// instance อื่นที่บูตพร้อมกันจะรอจน lock ว่าง แล้วเห็นว่า migration ถูก apply ไปแล้ว
+func (migrator *Migrator) withLock(requestContext context.Context, run func(connection *gorm.DB) error) error {
+	defer migrator.upToDate.Store(false)
+	return migrator.database.WithContext(requestContext).Connection(func(connection *gorm.DB) error {
+		unlock, err := acquireMigrationLock(connection)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if err := connection.AutoMigrate(&schemaMigrationRecord{}); err != nil {
+			return err
+		}
+		return run(connection)
+	})
+}
+
+func acquireMigrationLock(connection *gorm.DB) (func(), error) {
+	switch connection.Dialector.Name() {
+	case DriverPostgres:
+		if err := connection.Exec("SELECT pg_advisory_lock(?)", postgresMigrationLockKey).Error; err != nil {
+			return nil, err
+		}
+		return func() { connection.Exec("SELECT pg_advisory_unlock(?)", postgresMigrationLockKey) }, nil
+	case DriverMySQL:
+		var acquired int
+		if err := connection.Raw("SELECT GET_LOCK(?, ?)", mysqlMigrationLockName, mysqlMigrationLockWait).
+			Scan(&acquired).Error; err != nil {
+			return nil, err
+		}
+		if acquired != 1 {
+			return nil, errors.New("timed out waiting for migration lock")
+		}
+		return func() { connection.Exec("SELECT RELEASE_LOCK(?)", mysqlMigrationLockName) }, nil
+	default:
+		// SQLite เป็นไฟล์เดียวและ transaction ถือ write lock ทั้งไฟล์อยู่แล้ว
+		return func() {}, nil
+	}
+}
+
+func appliedVersions(connection *gorm.DB) (map[int]time.Time, error) {
+	var records []schemaMigrationRecord
+	if err := connection.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int]time.Time, len(records))
+	for _, record := range records {
+		applied[record.Version] = record.AppliedAt
+	}
+	return applied, nil
+}
+
+// applyMigration/revertMigration รันใน transaction
+// (หมายเหตุ: MySQL commit DDL ทันที ถ้าพังกลางไฟล์ต้องแก้ด้วยมือ)
+func applyMigration(connection *gorm.DB, migration Migration) error {
+	return connection.Transaction(func(transaction *gorm.DB) error {
+		if err := execStatements(transaction, migration.UpSQL); err != nil {
+			return fmt.Errorf("migration %04d_%s up: %w", migration.Version, migration.Name, err)
+		}
+		return transaction.Create(&schemaMigrationRecord{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now(),
+		}).Error
+	})
+}
+
+func revertMigration(connection *gorm.DB, migration Migration) error {
+	return connection.Transaction(func(transaction *gorm.DB) error {
+		if err := execStatements(transaction, migration.DownSQL); err != nil {
+			return fmt.Errorf("migration %04d_%s down: %w", migration.Version, migration.Name, err)
+		}
+		return transaction.Delete(&schemaMigrationRecord{}, migration.Version).Error
+	})
+}
+
+// execStatements แยกคำสั่งด้วย ";" ท้ายบรรทัด เพราะบาง driver (MySQL) รันได้ทีละคำสั่ง
+func execStatements(transaction *gorm.DB, script string) error {
+	var statement strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		statement.WriteString(line)
+		statement.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			if err := transaction.Exec(statement.String()).Error; err != nil {
+				return err
+			}
+			statement.Reset()
+		}
+	}
+	if strings.TrimSpace(statement.String()) != "" {
+		return transaction.Exec(statement.String()).Error
+	}
+	return nil
+}
+
+// EnsureMigrated คืน error ถ้า schema ยังไม่ถึงเวอร์ชันล่าสุด (ใช้ตอนปิด auto-migrate และเป็น readiness check)
+// อ่านอย่างเดียว ไม่สร้างตาราง schema_migrations ให้ และผ่านครั้งแรกแล้วจำไว้ ไม่ query ซ้ำทุก probe
+func (migrator *Migrator) EnsureMigrated(requestContext context.Context) error {
+	if migrator.upToDate.Load() {
+		return nil
+	}
+	connection := migrator.database.WithContext(requestContext)
+	applied := map[int]time.Time{}
+	if connection.Migrator().HasTable(&schemaMigrationRecord{}) {
+		var err error
+		if applied, err = appliedVersions(connection); err != nil {
+			return err
+		}
+	}
+	for _, migration := range migrator.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			return fmt.Errorf("migration %04d_%s not applied (run: migrate up)", migration.Version, migration.Name)
+		}
+	}
+	migrator.upToDate.Store(true)
+	return nil
+}
@@ -0,0 +1,88 @@
+package gormp_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	gormp "github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/gorm"
+)
+
+func TestMigratorUpDownRoundTrip(t *testing.T) {
+	database, err := gormp.OpenWith(gormp.DriverSQLite, filepath.Join(t.TempDir(), "books.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := gormp.NewMigrator(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requestContext := context.Background()
+
+	if err := migrator.Up(requestContext); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if err := migrator.Up(requestContext); err != nil {
+		t.Fatalf("second Up must be a no-op: %v", err)
+	}
+	if err := migrator.EnsureMigrated(requestContext); err != nil {
+		t.Fatalf("EnsureMigrated after Up: %v", err)
+	}
+	if !database.Migrator().HasTable("books") {
+		t.Fatal("books table missing after Up")
+	}
+
+	if err := migrator.To(requestContext, 0); err != nil {
+		t.Fatalf("To(0): %v", err)
+	}
+	if database.Migrator().HasTable("books") {
+		t.Fatal("books table still present after rolling back everything")
+	}
+	if err := migrator.EnsureMigrated(requestContext); err == nil {
+		t.Fatal("EnsureMigrated must fail when migrations are pending")
+	}
+
+	statuses, err := migrator.Status(requestContext)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			t.Fatalf("version %d still marked applied", status.Version)
+		}
+	}
+}
+
+// EnsureMigrated เป็น readiness check: ฐานข้อมูลว่างต้องไม่ถูกสร้างตารางใด ๆ จากการเช็ค
+func TestMigratorEnsureMigratedIsReadOnly(t *testing.T) {
+	database, err := gormp.OpenWith(gormp.DriverSQLite, filepath.Join(t.TempDir(), "books.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := gormp.NewMigrator(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requestContext := context.Background()
+
+	if err := migrator.EnsureMigrated(requestContext); err == nil {
+		t.Fatal("EnsureMigrated must fail on an empty database")
+	}
+	if database.Migrator().HasTable("schema_migrations") {
+		t.Fatal("EnsureMigrated created schema_migrations")
+	}
+
+	if err := migrator.Up(requestContext); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.EnsureMigrated(requestContext); err != nil {
+		t.Fatalf("EnsureMigrated after Up: %v", err)
+	}
+	// ผ่านแล้วจำไว้: probe ถัดไปไม่แตะฐานข้อมูล
+	if err := database.Exec("DROP TABLE schema_migrations").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.EnsureMigrated(requestContext); err != nil {
+		t.Fatalf("cached EnsureMigrated should not query again: %v", err)
+	}
+}
@@ -0,0 +1,13 @@
+-- MySQL ไม่มี partial index: title_active เป็น lower(title) ตอนยังไม่ถูกลบ และเป็น NULL เมื่อลบแล้ว
+-- unique index ยอมให้ NULL ซ้ำได้ เล่มที่ลบแล้วจึงไม่ชนกัน
+CREATE TABLE IF NOT EXISTS books (
+    id           BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    title        LONGTEXT        NOT NULL,
+    author       LONGTEXT        NOT NULL,
+    created_at   DATETIME(6)     NOT NULL,
+    updated_at   DATETIME(6)     NOT NULL,
+    deleted_at   DATETIME(6)     NULL,
+    title_active VARCHAR(255) GENERATED ALWAYS AS (IF(deleted_at IS NULL, LOWER(title), NULL)) VIRTUAL,
+    INDEX idx_books_deleted_at (deleted_at),
+    UNIQUE INDEX ux_books_title_active (title_active)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE api_keys (
+    id            BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    name          VARCHAR(255)    NOT NULL,
+    prefix        VARCHAR(32)     NOT NULL,
+    secret_hash   VARCHAR(255)    NOT NULL,
+    scope         VARCHAR(32)     NOT NULL,
+    created_by    VARCHAR(255)    NOT NULL,
+    created_at    DATETIME(6)     NOT NULL,
+    expires_at    DATETIME(6)     NULL,
+    last_used_at  DATETIME(6)     NULL,
+    revoked_at    DATETIME(6)     NULL,
+    UNIQUE INDEX ux_api_keys_prefix (prefix)
+);
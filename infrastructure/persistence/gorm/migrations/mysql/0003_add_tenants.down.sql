@@ -0,0 +1,6 @@
+ALTER TABLE books
+    DROP INDEX ux_books_title_active,
+    ADD UNIQUE INDEX ux_books_title_active (title_active);
+
+ALTER TABLE api_keys DROP COLUMN tenant_id;
+ALTER TABLE books DROP COLUMN tenant_id;
@@ -0,0 +1,8 @@
+-- ข้อมูลเดิมทั้งหมดเป็นของ tenant "default"
+ALTER TABLE books ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE api_keys ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+-- ชื่อห้ามซ้ำภายใน tenant เดียวกันเท่านั้น (title_active เป็น NULL เมื่อถูกลบ จึงไม่ชนกัน)
+ALTER TABLE books
+    DROP INDEX ux_books_title_active,
+    ADD UNIQUE INDEX ux_books_title_active (tenant_id, title_active);
@@ -0,0 +1,14 @@
+-- IF NOT EXISTS เพื่อรับช่วงฐานเดิมที่เคยสร้างด้วย AutoMigrate/EnsureIndexes
+CREATE TABLE IF NOT EXISTS books (
+    id          BIGSERIAL   PRIMARY KEY,
+    title       TEXT        NOT NULL,
+    author      TEXT        NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL,
+    deleted_at  TIMESTAMPTZ NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_books_deleted_at ON books (deleted_at);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_books_title_active
+    ON books (lower(title)) WHERE deleted_at IS NULL;
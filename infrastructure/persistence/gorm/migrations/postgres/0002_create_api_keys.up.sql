@@ -0,0 +1,14 @@
+CREATE TABLE api_keys (
+    id            BIGSERIAL   PRIMARY KEY,
+    name          TEXT        NOT NULL,
+    prefix        TEXT        NOT NULL,
+    secret_hash   TEXT        NOT NULL,
+    scope         TEXT        NOT NULL,
+    created_by    TEXT        NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL,
+    expires_at    TIMESTAMPTZ NULL,
+    last_used_at  TIMESTAMPTZ NULL,
+    revoked_at    TIMESTAMPTZ NULL
+);
+
+CREATE UNIQUE INDEX ux_api_keys_prefix ON api_keys (prefix);
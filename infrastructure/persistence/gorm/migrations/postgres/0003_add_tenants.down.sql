@@ -0,0 +1,9 @@
+ALTER TABLE books NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS books_tenant_isolation ON books;
+
+DROP INDEX IF EXISTS ux_books_title_active;
+CREATE UNIQUE INDEX ux_books_title_active
+    ON books (lower(title)) WHERE deleted_at IS NULL;
+
+ALTER TABLE api_keys DROP COLUMN tenant_id;
+ALTER TABLE books DROP COLUMN tenant_id;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS books (
+    id          INTEGER  PRIMARY KEY AUTOINCREMENT,
+    title       TEXT     NOT NULL,
+    author      TEXT     NOT NULL,
+    created_at  DATETIME NOT NULL,
+    updated_at  DATETIME NOT NULL,
+    deleted_at  DATETIME NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_books_deleted_at ON books (deleted_at);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_books_title_active
+    ON books (lower(title)) WHERE deleted_at IS NULL;
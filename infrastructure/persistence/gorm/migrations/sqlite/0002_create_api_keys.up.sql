@@ -0,0 +1,14 @@
+CREATE TABLE api_keys (
+    id            INTEGER  PRIMARY KEY AUTOINCREMENT,
+    name          TEXT     NOT NULL,
+    prefix        TEXT     NOT NULL,
+    secret_hash   TEXT     NOT NULL,
+    scope         TEXT     NOT NULL,
+    created_by    TEXT     NOT NULL,
+    created_at    DATETIME NOT NULL,
+    expires_at    DATETIME NULL,
+    last_used_at  DATETIME NULL,
+    revoked_at    DATETIME NULL
+);
+
+CREATE UNIQUE INDEX ux_api_keys_prefix ON api_keys (prefix);
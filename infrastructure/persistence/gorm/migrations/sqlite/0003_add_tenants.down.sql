@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS ux_books_title_active;
+CREATE UNIQUE INDEX ux_books_title_active
+    ON books (lower(title)) WHERE deleted_at IS NULL;
+
+ALTER TABLE api_keys DROP COLUMN tenant_id;
+ALTER TABLE books DROP COLUMN tenant_id;
@@ -0,0 +1,8 @@
+-- ข้อมูลเดิมทั้งหมดเป็นของ tenant "default"
+ALTER TABLE books ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE api_keys ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default';
+
+-- ชื่อห้ามซ้ำภายใน tenant เดียวกันเท่านั้น
+DROP INDEX IF EXISTS ux_books_title_active;
+CREATE UNIQUE INDEX ux_books_title_active
+    ON books (tenant_id, lower(title)) WHERE deleted_at IS NULL;
@@ -0,0 +1,191 @@
+package gormp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/config"
+)
+
+// ReplicaSet = กลุ่ม read replica ที่เลือกแบบ round-robin และข้ามตัวที่ health check ไม่ผ่าน
+type ReplicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+type replica struct {
+	database *gorm.DB
+	healthy  atomic.Bool
+}
+
+// NewReplicaSet เริ่ม health check เบื้องหลังทุก ๆ healthInterval (ต้องเรียก Close ตอนปิดโปรแกรม)
+func NewReplicaSet(databases []*gorm.DB, healthInterval time.Duration) *ReplicaSet {
+	replicaSet := &ReplicaSet{stop: make(chan struct{})}
+	for _, database := range databases {
+		member := &replica{database: database}
+		member.healthy.Store(true) // เพิ่งต่อสำเร็จตอน Open ถือว่าพร้อม
+		replicaSet.replicas = append(replicaSet.replicas, member)
+	}
+	if len(replicaSet.replicas) > 0 && healthInterval > 0 {
+		go replicaSet.healthLoop(healthInterval)
+	}
+	return replicaSet
+}
+
+// pick คืน replica ตัวถัดไปที่ยังดีอยู่ หรือ nil ถ้าไม่มีเลย (ให้ผู้เรียกใช้ primary แทน)
+func (replicaSet *ReplicaSet) pick() *gorm.DB {
+	if replicaSet == nil {
+		return nil
+	}
+	count := uint64(len(replicaSet.replicas))
+	for range count {
+		member := replicaSet.replicas[replicaSet.next.Add(1)%count]
+		if member.healthy.Load() {
+			return member.database
+		}
+	}
+	return nil
+}
+
+func (replicaSet *ReplicaSet) healthLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-replicaSet.stop:
+			return
+		case <-ticker.C:
+			for _, member := range replicaSet.replicas {
+				member.healthy.Store(pingDatabase(member.database, interval))
+			}
+		}
+	}
+}
+
+func pingDatabase(database *gorm.DB, timeout time.Duration) bool {
+	sqlDatabase, err := database.DB()
+	if err != nil {
+		return false
+	}
+	pingContext, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return sqlDatabase.PingContext(pingContext) == nil
+}
+
+// Databases คืน *gorm.DB ของทุก replica (ไว้ติด instrumentation/metrics)
+func (replicaSet *ReplicaSet) Databases() []*gorm.DB {
+	databases := make([]*gorm.DB, 0, len(replicaSet.replicas))
+	for _, member := range replicaSet.replicas {
+		databases = append(databases, member.database)
+	}
+	return databases
+}
+
+// Check คืน error เมื่อไม่มี replica ที่ผ่าน health check รอบล่าสุดเลย (การอ่านจะตกไปที่ primary ทั้งหมด)
+func (replicaSet *ReplicaSet) Check(context.Context) error {
+	healthy := 0
+	for _, member := range replicaSet.replicas {
+		if member.healthy.Load() {
+			healthy++
+		}
+	}
+	if healthy == 0 {
+		return fmt.Errorf("no healthy replica (0/%d)", len(replicaSet.replicas))
+	}
+	return nil
+}
+
+// Close หยุด health check และปิด pool ของทุก replica
+func (replicaSet *ReplicaSet) Close() error {
+	replicaSet.stopOnce.Do(func() { close(replicaSet.stop) })
+	var firstError error
+	for _, member := range replicaSet.replicas {
+		sqlDatabase, err := member.database.DB()
+		if err == nil {
+			err = sqlDatabase.Close()
+		}
+		if err != nil && firstError == nil {
+			firstError = err
+		}
+	}
+	return firstError
+}
+
+// writeTracker จำว่า client ไหนเพิ่งเขียน เพื่อปักหมุดให้อ่านจาก primary ไปอีกช่วงหนึ่ง (read-your-writes)
+type writeTracker struct {
+	window      time.Duration
+	mutex       sync.Mutex
+	pinnedUntil map[string]time.Time
+	lastSweep   time.Time
+}
+
+func newWriteTracker(window time.Duration) *writeTracker {
+	return &writeTracker{window: window, pinnedUntil: make(map[string]time.Time)}
+}
+
+func (tracker *writeTracker) recordWrite(clientKey string) {
+	if tracker == nil || clientKey == "" {
+		return
+	}
+	now := time.Now()
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	tracker.pinnedUntil[clientKey] = now.Add(tracker.window)
+	// กวาดรายการที่หมดอายุเป็นระยะ ไม่ให้ map โตไม่หยุด
+	if now.Sub(tracker.lastSweep) > tracker.window {
+		for key, until := range tracker.pinnedUntil {
+			if now.After(until) {
+				delete(tracker.pinnedUntil, key)
+			}
+		}
+		tracker.lastSweep = now
+	}
+}
+
+func (tracker *writeTracker) isPinned(clientKey string) bool {
+	if tracker == nil || clientKey == "" {
+		return false
+	}
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	until, found := tracker.pinnedUntil[clientKey]
+	return found && time.Now().Before(until)
+}
+
+// OpenReplicasFromEnv เปิด replica ตาม DB_REPLICA_DSNS (หลาย DSN คั่นด้วย ";") ด้วย driver/pool เดียวกับ primary
+// คืน nil ถ้าไม่ได้ตั้งค่า replica ไว้
+func OpenReplicasFromEnv(logger interfaces.Logger) (*ReplicaSet, error) {
+	var databases []*gorm.DB
+	for _, dataSourceName := range strings.Split(os.Getenv("DB_REPLICA_DSNS"), ";") {
+		dataSourceName = strings.TrimSpace(dataSourceName)
+		if dataSourceName == "" {
+			continue
+		}
+		database, err := OpenWithPool(os.Getenv("DB_DRIVER"), dataSourceName, PoolConfigFromEnv(), logger)
+		if err != nil {
+			for _, opened := range databases {
+				if sqlDatabase, closeError := opened.DB(); closeError == nil {
+					_ = sqlDatabase.Close()
+				}
+			}
+			return nil, fmt.Errorf("replica: %w", err)
+		}
+		databases = append(databases, database)
+	}
+	if len(databases) == 0 {
+		return nil, nil
+	}
+	return NewReplicaSet(databases, config.Duration("DB_REPLICA_HEALTH_INTERVAL", 5*time.Second)), nil
+}
@@ -0,0 +1,51 @@
+package gormp_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+	gormp "github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/gorm"
+)
+
+// ใช้ SQLite สองไฟล์แยกกันแทน primary/replica ที่ "ยังไม่ sync" เพื่อดูว่าการอ่านไปลงที่ไหน
+func TestReadsGoToReplicaUnlessClientJustWrote(t *testing.T) {
+	primary := openMigrated(t, gormp.DriverSQLite, filepath.Join(t.TempDir(), "primary.db"))
+	replica := openMigrated(t, gormp.DriverSQLite, filepath.Join(t.TempDir(), "replica.db"))
+	replicas := gormp.NewReplicaSet([]*gorm.DB{replica}, 0)
+	t.Cleanup(func() { _ = replicas.Close() })
+
+	repository := gormp.NewBookRepositoryGorm(primary,
+		gormp.WithReplicas(replicas),
+		gormp.WithReadYourWrites(time.Minute),
+	)
+
+	writerContext := requestmeta.WithClientKey(context.Background(), "writer")
+	otherContext := requestmeta.WithClientKey(context.Background(), "someone-else")
+
+	now := time.Now()
+	book := domain.Book{Title: "Replicated", Author: "Author", CreatedAt: now, UpdatedAt: now}
+	if err := repository.Create(writerContext, &book); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if books, err := repository.List(otherContext); err != nil || len(books) != 0 {
+		t.Fatalf("other client should read the (empty) replica, got %d books (err %v)", len(books), err)
+	}
+	if books, err := repository.List(writerContext); err != nil || len(books) != 1 {
+		t.Fatalf("writer should be pinned to primary, got %d books (err %v)", len(books), err)
+	}
+	strongContext := requestmeta.WithStrongRead(otherContext)
+	if _, err := repository.GetByID(strongContext, book.ID); err != nil {
+		t.Fatalf("strong read should hit primary: %v", err)
+	}
+	exists, err := repository.ExistsActiveByTitle(otherContext, "replicated", nil)
+	if err != nil || !exists {
+		t.Fatalf("ExistsActiveByTitle must check primary, got %v (err %v)", exists, err)
+	}
+}
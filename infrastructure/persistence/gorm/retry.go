@@ -0,0 +1,107 @@
+package gormp
+
+import (
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"syscall"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/config"
+)
+
+// RetryPolicy = จำนวนครั้ง/ระยะรอ เมื่อเจอ error ชั่วคราวจากฐานข้อมูล
+type RetryPolicy struct {
+	MaxAttempts int           // DB_RETRY_ATTEMPTS (รวมครั้งแรก, 1 = ไม่ retry)
+	BaseDelay   time.Duration // DB_RETRY_BASE_DELAY (เพิ่มเท่าตัวทุกครั้ง + jitter)
+	MaxDelay    time.Duration // DB_RETRY_MAX_DELAY
+}
+
+// RetryPolicyFromEnv อ่าน RetryPolicy จากตัวแปรแวดล้อม
+func RetryPolicyFromEnv() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: config.Int("DB_RETRY_ATTEMPTS", 3),
+		BaseDelay:   config.Duration("DB_RETRY_BASE_DELAY", 50*time.Millisecond),
+		MaxDelay:    config.Duration("DB_RETRY_MAX_DELAY", time.Second),
+	}
+}
+
+// run เรียก operation ซ้ำตาม policy ตราบใดที่ isRetryable(err) ยังเป็นจริง
+func (policy RetryPolicy) run(isRetryable func(error) bool, operation func() error) error {
+	delay := policy.BaseDelay
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = operation()
+		if err == nil || attempt >= policy.MaxAttempts || !isRetryable(err) {
+			return err
+		}
+		jitter := time.Duration(0)
+		if half := delay / 2; half > 0 { // rand.N(0) panic (เช่น delay = 1ns)
+			jitter = rand.N(half)
+		}
+		time.Sleep(delay + jitter)
+		delay = min(delay*2, policy.MaxDelay)
+	}
+}
+
+// retryRead ใช้กับการอ่าน: ปลอดภัยที่จะลองใหม่ทุก error ชั่วคราว
+func (policy RetryPolicy) retryRead(operation func() error) error {
+	return policy.run(isTransientError, operation)
+}
+
+// retryWrite ใช้กับการเขียน: ลองใหม่เฉพาะกรณีที่รู้แน่ว่าฐานข้อมูล rollback ไปแล้ว
+// (ถ้าการเชื่อมต่อหลุดกลางคำสั่ง อาจ commit ไปแล้ว การลองซ้ำจะทำให้เขียนซ้ำ)
+func (policy RetryPolicy) retryWrite(operation func() error) error {
+	return policy.run(isRolledBackError, operation)
+}
+
+// isRolledBackError = serialization failure / deadlock / การเชื่อมต่อเสียก่อนส่งคำสั่ง
+func isRolledBackError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	var postgresError *pgconn.PgError
+	if errors.As(err, &postgresError) {
+		switch postgresError.Code {
+		case "40001", // serialization_failure
+			"40P01": // deadlock_detected
+			return true
+		}
+	}
+	var mysqlError *mysql.MySQLError
+	if errors.As(err, &mysqlError) {
+		switch mysqlError.Number {
+		case 1213, // ER_LOCK_DEADLOCK
+			1205: // ER_LOCK_WAIT_TIMEOUT
+			return true
+		}
+	}
+	return false
+}
+
+// isTransientError = isRolledBackError + การเชื่อมต่อหลุด/ถูกรีเซ็ต/ฐานข้อมูลกำลังรีสตาร์ต
+func isTransientError(err error) bool {
+	if isRolledBackError(err) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var postgresError *pgconn.PgError
+	if errors.As(err, &postgresError) {
+		switch postgresError.Code {
+		case "57P01", // admin_shutdown
+			"57P03",                   // cannot_connect_now
+			"08000", "08003", "08006": // connection_exception
+			return true
+		}
+	}
+	return false
+}
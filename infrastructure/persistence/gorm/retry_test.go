@@ -0,0 +1,53 @@
+package gormp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+)
+
+func TestRetryPolicyTinyDelayDoesNotPanic(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Nanosecond, MaxDelay: time.Nanosecond}
+	attempts := 0
+	err := policy.run(func(error) bool { return true }, func() error {
+		attempts++
+		return errors.New("temporary")
+	})
+	if err == nil || attempts != 3 {
+		t.Fatalf("attempts = %d, err = %v; want 3 attempts and the last error", attempts, err)
+	}
+}
+
+// warningCounter นับ Warn ที่ได้รับ (ข้อความอื่นทิ้ง)
+type warningCounter struct{ warnings []string }
+
+func (counter *warningCounter) Debug(context.Context, string, ...any) {}
+func (counter *warningCounter) Info(context.Context, string, ...any)  {}
+func (counter *warningCounter) Warn(_ context.Context, message string, _ ...any) {
+	counter.warnings = append(counter.warnings, message)
+}
+func (counter *warningCounter) Error(context.Context, string, ...any) {}
+func (counter *warningCounter) With(...any) interfaces.Logger         { return counter }
+
+func TestOpenWithPoolZeroBackoffDoesNotBusyLoop(t *testing.T) {
+	logger := &warningCounter{}
+	_, err := OpenWithPool(DriverPostgres, "host=127.0.0.1 port=1 user=x dbname=x sslmode=disable connect_timeout=1",
+		PoolConfig{ConnectTimeout: 300 * time.Millisecond}, logger)
+	var attempts int
+	if err == nil || !strings.Contains(err.Error(), "after") {
+		t.Fatalf("err = %v, want not reachable", err)
+	}
+	_, _ = fmt.Sscanf(err.Error(), "database not reachable after %d attempts", &attempts)
+	if attempts == 0 || attempts > 10 {
+		t.Fatalf("attempts = %d in 300ms with zero backoff, want a handful", attempts)
+	}
+	// ทุกครั้งที่ลองซ้ำต้องผ่าน logger ที่ส่งเข้ามา (ครั้งสุดท้ายคืนเป็น error แทน)
+	if len(logger.warnings) != attempts-1 {
+		t.Fatalf("logged %d retry warnings for %d attempts, want %d", len(logger.warnings), attempts, attempts-1)
+	}
+}
@@ -0,0 +1,36 @@
+package gormp
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ConfigureRowLevelSecurity เปิด/ปิด row-level security ของตาราง books (Postgres เท่านั้น)
+// policy books_tenant_isolation ถูกสร้างไว้แล้วใน migration 0003 แต่จะมีผลเมื่อเปิดที่นี่
+// FORCE ทำให้เจ้าของตาราง (ผู้ใช้ที่แอปใช้ต่อฐานข้อมูล) ก็ถูกบังคับด้วย
+// เมื่อเปิดต้องสร้าง repository ด้วย WithRowLevelSecurity ไม่งั้นทุก query จะไม่เห็นข้อมูล
+// ALTER TABLE ถือ lock ทั้งตาราง จึงรันเฉพาะตอนที่สถานะในฐานข้อมูลไม่ตรงกับ enabled (ไม่ใช่ทุกครั้งที่บูต)
+func ConfigureRowLevelSecurity(requestContext context.Context, database *gorm.DB, enabled bool) error {
+	if database.Dialector.Name() != DriverPostgres {
+		if enabled {
+			return fmt.Errorf("row-level security requires %s (got %s)", DriverPostgres, database.Dialector.Name())
+		}
+		return nil
+	}
+	var rowSecurity, forceRowSecurity bool
+	if err := database.WithContext(requestContext).
+		Raw("SELECT relrowsecurity, relforcerowsecurity FROM pg_class WHERE oid = 'books'::regclass").
+		Row().Scan(&rowSecurity, &forceRowSecurity); err != nil {
+		return fmt.Errorf("read row-level security state: %w", err)
+	}
+	if rowSecurity == enabled && forceRowSecurity == enabled {
+		return nil
+	}
+	statement := "ALTER TABLE books NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY"
+	if enabled {
+		statement = "ALTER TABLE books ENABLE ROW LEVEL SECURITY, FORCE ROW LEVEL SECURITY"
+	}
+	return database.WithContext(requestContext).Exec(statement).Error
+}
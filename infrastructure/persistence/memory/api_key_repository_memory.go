@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+// APIKeyRepositoryMemory = ที่เก็บ API key ในหน่วยความจำ (หายเมื่อรีสตาร์ต ใช้ตอนเทส/เดโม)
+type APIKeyRepositoryMemory struct {
+	mutex  sync.RWMutex
+	keys   map[uint]domain.APIKey
+	lastID uint
+}
+
+func NewAPIKeyRepositoryMemory() interfaces.APIKeyRepository {
+	return &APIKeyRepositoryMemory{keys: make(map[uint]domain.APIKey)}
+}
+
+// cloneAPIKey คัดลอก field ที่เป็น pointer ออกไปใหม่ ไม่ให้ผู้เรียกแก้ของใน map ได้
+func cloneAPIKey(key domain.APIKey) domain.APIKey {
+	key.ExpiresAt = cloneTime(key.ExpiresAt)
+	key.LastUsedAt = cloneTime(key.LastUsedAt)
+	key.RevokedAt = cloneTime(key.RevokedAt)
+	return key
+}
+
+func cloneTime(value *time.Time) *time.Time {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+func (repository *APIKeyRepositoryMemory) Create(_ context.Context, key *domain.APIKey) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	repository.lastID++
+	key.ID = repository.lastID
+	repository.keys[key.ID] = cloneAPIKey(*key)
+	return nil
+}
+
+func (repository *APIKeyRepositoryMemory) GetByPrefix(_ context.Context, prefix string) (domain.APIKey, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	for _, key := range repository.keys {
+		if key.Prefix == prefix {
+			return cloneAPIKey(key), nil
+		}
+	}
+	return domain.APIKey{}, domain.ErrNotFound
+}
+
+func (repository *APIKeyRepositoryMemory) List(requestContext context.Context) ([]domain.APIKey, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	tenantID := requestmeta.TenantID(requestContext)
+	result := make([]domain.APIKey, 0, len(repository.keys))
+	for _, key := range repository.keys {
+		if key.TenantID == tenantID {
+			result = append(result, cloneAPIKey(key))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (repository *APIKeyRepositoryMemory) Revoke(requestContext context.Context, id uint, revokedAt time.Time) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	key, found := repository.keys[id]
+	if !found || key.TenantID != requestmeta.TenantID(requestContext) {
+		return domain.ErrNotFound
+	}
+	if key.RevokedAt == nil {
+		key.RevokedAt = &revokedAt
+		repository.keys[id] = key
+	}
+	return nil
+}
+
+func (repository *APIKeyRepositoryMemory) TouchLastUsed(_ context.Context, id uint, usedAt time.Time) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	if key, found := repository.keys[id]; found {
+		key.LastUsedAt = &usedAt
+		repository.keys[id] = key
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+// BookRepositoryMemory = อแดปเตอร์เก็บข้อมูลในหน่วยความจำ (ใช้ตอนเทส/โหมดเดโม)
+// พฤติกรรมเลียนแบบ BookRepositoryGorm: soft delete, ชื่อห้ามซ้ำแบบไม่สนตัวพิมพ์
+// (เฉพาะเล่มที่ยังไม่ถูกลบ ภายใน tenant เดียวกัน) และ id แบบ auto-increment ที่ไม่นำกลับมาใช้ซ้ำ
+type BookRepositoryMemory struct {
+	mutex   sync.RWMutex
+	books   map[uint]domain.Book
+	tenants map[uint]string // tenant ของแต่ละเล่ม
+	lastID  uint
+}
+
+func NewBookRepositoryMemory() interfaces.BookRepository {
+	return &BookRepositoryMemory{books: make(map[uint]domain.Book), tenants: make(map[uint]string)}
+}
+
+// activeBook คืนเล่มที่ยังไม่ถูกลบของ tenant นี้ (ต้องถือ lock อยู่แล้วก่อนเรียก)
+func (repository *BookRepositoryMemory) activeBook(tenantID string, id uint) (domain.Book, bool) {
+	book, found := repository.books[id]
+	if !found || book.DeletedAt != nil || repository.tenants[id] != tenantID {
+		return domain.Book{}, false
+	}
+	return book, true
+}
+
+// cloneBook คัดลอก DeletedAt ออกไปใหม่ ไม่ให้ผู้เรียกแก้ของใน map ได้
+func cloneBook(book domain.Book) domain.Book {
+	if book.DeletedAt != nil {
+		deletedAt := *book.DeletedAt
+		book.DeletedAt = &deletedAt
+	}
+	return book
+}
+
+// hasActiveTitle ต้องถือ lock อยู่แล้วก่อนเรียก
+func (repository *BookRepositoryMemory) hasActiveTitle(tenantID, title string, excludeID *uint) bool {
+	lowerTitle := strings.ToLower(title)
+	for id, book := range repository.books {
+		if book.DeletedAt != nil || repository.tenants[id] != tenantID {
+			continue
+		}
+		if excludeID != nil && id == *excludeID {
+			continue
+		}
+		if strings.ToLower(book.Title) == lowerTitle {
+			return true
+		}
+	}
+	return false
+}
+
+func (repository *BookRepositoryMemory) List(requestContext context.Context) ([]domain.Book, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	tenantID := requestmeta.TenantID(requestContext)
+	result := make([]domain.Book, 0, len(repository.books))
+	for id, book := range repository.books {
+		if book.DeletedAt != nil || repository.tenants[id] != tenantID {
+			continue
+		}
+		result = append(result, cloneBook(book))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (repository *BookRepositoryMemory) GetByID(requestContext context.Context, id uint) (domain.Book, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	book, found := repository.activeBook(requestmeta.TenantID(requestContext), id)
+	if !found {
+		return domain.Book{}, domain.ErrNotFound
+	}
+	return cloneBook(book), nil
+}
+
+func (repository *BookRepositoryMemory) ExistsActiveByTitle(requestContext context.Context, title string, excludeID *uint) (bool, error) {
+	repository.mutex.RLock()
+	defer repository.mutex.RUnlock()
+
+	return repository.hasActiveTitle(requestmeta.TenantID(requestContext), title, excludeID), nil
+}
+
+func (repository *BookRepositoryMemory) Create(requestContext context.Context, book *domain.Book) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	// ทำหน้าที่แทน unique index ux_books_title_active ของฝั่งฐานข้อมูล
+	tenantID := requestmeta.TenantID(requestContext)
+	if repository.hasActiveTitle(tenantID, book.Title, nil) {
+		return domain.ErrTitleExists
+	}
+
+	repository.lastID++
+	record := cloneBook(*book)
+	record.ID = repository.lastID
+	record.DeletedAt = nil
+	repository.books[record.ID] = record
+	repository.tenants[record.ID] = tenantID
+
+	book.ID = record.ID
+	return nil
+}
+
+func (repository *BookRepositoryMemory) Update(requestContext context.Context, book *domain.Book) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	tenantID := requestmeta.TenantID(requestContext)
+	current, found := repository.activeBook(tenantID, book.ID)
+	if !found {
+		return nil // เหมือน GORM: UPDATE ที่ไม่โดนแถวไหนไม่ถือว่า error
+	}
+	if repository.hasActiveTitle(tenantID, book.Title, &book.ID) {
+		return domain.ErrTitleExists
+	}
+
+	current.Title = book.Title
+	current.Author = book.Author
+	current.UpdatedAt = book.UpdatedAt
+	repository.books[book.ID] = current
+	return nil
+}
+
+func (repository *BookRepositoryMemory) SoftDelete(requestContext context.Context, id uint) error {
+	repository.mutex.Lock()
+	defer repository.mutex.Unlock()
+
+	current, found := repository.activeBook(requestmeta.TenantID(requestContext), id)
+	if !found {
+		return nil
+	}
+	deletedAt := time.Now()
+	current.DeletedAt = &deletedAt
+	repository.books[id] = current
+	return nil
+}
@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/contract"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/memory"
+)
+
+func TestBookRepositoryMemoryContract(t *testing.T) {
+	contract.RunBookRepositoryContract(t, func(t *testing.T) interfaces.BookRepository {
+		return memory.NewBookRepositoryMemory()
+	})
+}
@@ -0,0 +1,31 @@
+package ratelimit
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/config"
+)
+
+// OpenStoreFromEnv เลือกที่เก็บตาม RATE_LIMIT_BACKEND
+// ว่าง/none = ปิด rate limit (คืน nil), memory = นับในโปรเซส, redis = นับร่วมกันทุก instance
+func OpenStoreFromEnv(clock interfaces.Clock) (interfaces.RateLimitStore, error) {
+	switch backend := os.Getenv("RATE_LIMIT_BACKEND"); backend {
+	case "", "none":
+		return nil, nil
+	case "memory":
+		return NewMemoryStore(clock), nil
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr:     os.Getenv("RATE_LIMIT_REDIS_ADDR"),
+			Password: os.Getenv("RATE_LIMIT_REDIS_PASSWORD"),
+			DB:       config.Int("RATE_LIMIT_REDIS_DB", 0),
+		})
+		return NewRedisStore(client, "books-api:ratelimit:", clock), nil
+	default:
+		return nil, fmt.Errorf("unknown RATE_LIMIT_BACKEND %q (want none, memory or redis)", backend)
+	}
+}
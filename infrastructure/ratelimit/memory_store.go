@@ -0,0 +1,80 @@
+// Package ratelimit = ที่เก็บ token bucket สำหรับ interfaces.RateLimitStore
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+)
+
+// sweepInterval = ความถี่ในการกวาดถังที่เต็มแล้ว (ไม่มีใครใช้) ออกจากหน่วยความจำ
+const sweepInterval = time.Minute
+
+// MemoryStore = token bucket ในโปรเซส (แต่ละ instance นับแยกกัน)
+type MemoryStore struct {
+	clock     interfaces.Clock
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	fullAt    time.Time // เวลาที่ถังจะเต็ม (ใช้ตัดสินว่ากวาดทิ้งได้)
+}
+
+func NewMemoryStore(clock interfaces.Clock) *MemoryStore {
+	return &MemoryStore{clock: clock, buckets: make(map[string]*bucket)}
+}
+
+func (store *MemoryStore) Take(_ context.Context, key string, limit interfaces.RateLimit) (interfaces.RateLimitDecision, error) {
+	now := store.clock.Now()
+	capacity := float64(limit.Capacity())
+	ratePerSecond := float64(limit.Requests) / limit.Per.Seconds()
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.sweep(now)
+
+	state, found := store.buckets[key]
+	if !found {
+		state = &bucket{tokens: capacity, updatedAt: now}
+		store.buckets[key] = state
+	}
+	elapsed := now.Sub(state.updatedAt).Seconds()
+	state.tokens = math.Min(capacity, state.tokens+math.Max(0, elapsed)*ratePerSecond)
+	state.updatedAt = now
+
+	decision := interfaces.RateLimitDecision{}
+	if state.tokens >= 1 {
+		state.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = secondsToDuration((1 - state.tokens) / ratePerSecond)
+	}
+	decision.Remaining = int(state.tokens)
+	decision.ResetAfter = secondsToDuration((capacity - state.tokens) / ratePerSecond)
+	state.fullAt = now.Add(decision.ResetAfter)
+	return decision, nil
+}
+
+// sweep ลบถังที่เต็มแล้ว (ผลเท่ากับไม่มีถัง) ไม่ให้ map โตไม่หยุด; ต้องถือ lock อยู่แล้ว
+func (store *MemoryStore) sweep(now time.Time) {
+	if now.Sub(store.lastSweep) < sweepInterval {
+		return
+	}
+	for key, state := range store.buckets {
+		if !now.Before(state.fullAt) {
+			delete(store.buckets, key)
+		}
+	}
+	store.lastSweep = now
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+)
+
+// takeScript = token bucket แบบ atomic ฝั่งเซิร์ฟเวอร์ (hash: tokens, updated_ms)
+// ARGV: capacity, tokens ต่อมิลลิวินาที, now_ms
+// คืน {allowed, remaining, retry_after_ms, reset_after_ms}
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated_ms')
+local tokens = tonumber(state[1])
+local updated = tonumber(state[2])
+if tokens == nil then
+  tokens = capacity
+  updated = now
+end
+tokens = math.min(capacity, tokens + math.max(0, now - updated) * rate)
+
+local allowed = 0
+local retry_after = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry_after = math.ceil((1 - tokens) / rate)
+end
+local reset_after = math.ceil((capacity - tokens) / rate)
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated_ms', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.max(reset_after, 1))
+return {allowed, math.floor(tokens), retry_after, reset_after}
+`)
+
+// RedisStore = token bucket บนเซิร์ฟเวอร์โปรโตคอล Redis ใช้ร่วมกันได้หลาย instance
+// เวลาอ้างอิงมาจาก clock ของแต่ละ instance (นาฬิกาควร sync ด้วย NTP)
+type RedisStore struct {
+	client    redis.UniversalClient
+	keyPrefix string
+	clock     interfaces.Clock
+}
+
+func NewRedisStore(client redis.UniversalClient, keyPrefix string, clock interfaces.Clock) *RedisStore {
+	return &RedisStore{client: client, keyPrefix: keyPrefix, clock: clock}
+}
+
+// Ping ตรวจว่าเชื่อมต่อ Redis ได้ (ใช้เป็น health check)
+func (store *RedisStore) Ping(requestContext context.Context) error {
+	return store.client.Ping(requestContext).Err()
+}
+
+func (store *RedisStore) Take(requestContext context.Context, key string, limit interfaces.RateLimit) (interfaces.RateLimitDecision, error) {
+	ratePerMillisecond := float64(limit.Requests) / float64(limit.Per.Milliseconds())
+	result, err := takeScript.Run(requestContext, store.client, []string{store.keyPrefix + key},
+		limit.Capacity(), ratePerMillisecond, store.clock.Now().UnixMilli()).Int64Slice()
+	if err != nil {
+		return interfaces.RateLimitDecision{}, err
+	}
+	return interfaces.RateLimitDecision{
+		Allowed:    result[0] == 1,
+		Remaining:  int(result[1]),
+		RetryAfter: time.Duration(result[2]) * time.Millisecond,
+		ResetAfter: time.Duration(result[3]) * time.Millisecond,
+	}, nil
+}
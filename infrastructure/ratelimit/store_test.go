@@ -0,0 +1,64 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/ratelimit"
+)
+
+type manualClock struct{ now time.Time }
+
+func (clock *manualClock) Now() time.Time { return clock.now }
+
+var stores = map[string]func(t *testing.T, clock interfaces.Clock) interfaces.RateLimitStore{
+	"memory": func(t *testing.T, clock interfaces.Clock) interfaces.RateLimitStore {
+		return ratelimit.NewMemoryStore(clock)
+	},
+	"redis": func(t *testing.T, clock interfaces.Clock) interfaces.RateLimitStore {
+		server := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { _ = client.Close() })
+		return ratelimit.NewRedisStore(client, "test:", clock)
+	},
+}
+
+func TestTokenBucket(t *testing.T) {
+	ctx := context.Background()
+	limit := interfaces.RateLimit{Requests: 2, Per: time.Second, Burst: 3}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			clock := &manualClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+			store := newStore(t, clock)
+
+			for i := range 3 {
+				decision, err := store.Take(ctx, "client", limit)
+				if err != nil || !decision.Allowed || decision.Remaining != 2-i {
+					t.Fatalf("take %d within burst: %+v (err %v)", i, decision, err)
+				}
+			}
+			decision, err := store.Take(ctx, "client", limit)
+			if err != nil || decision.Allowed {
+				t.Fatalf("expected rejection after burst, got %+v (err %v)", decision, err)
+			}
+			if decision.RetryAfter != 500*time.Millisecond || decision.ResetAfter != 1500*time.Millisecond {
+				t.Fatalf("unexpected timings %+v", decision)
+			}
+
+			if other, _ := store.Take(ctx, "other-client", limit); !other.Allowed {
+				t.Fatal("buckets must be independent per key")
+			}
+
+			clock.now = clock.now.Add(500 * time.Millisecond) // เติมได้ 1 โทเคน
+			if decision, _ := store.Take(ctx, "client", limit); !decision.Allowed || decision.Remaining != 0 {
+				t.Fatalf("expected one refilled token, got %+v", decision)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const querySpanKey = "tracing:query_span"
+
+// gormPlugin เปิด span ลูกให้ทุก query ของ GORM (ต้องเรียก query ด้วย WithContext ถึงจะต่อกับ span ของ request)
+type gormPlugin struct {
+	tracer trace.Tracer
+	role   string
+}
+
+// InstrumentGorm ติด plugin tracing ให้ database (role = primary/replica ไว้แยกใน attribute)
+func InstrumentGorm(database *gorm.DB, provider trace.TracerProvider, role string) error {
+	return database.Use(&gormPlugin{tracer: provider.Tracer(instrumentationName), role: role})
+}
+
+func (plugin *gormPlugin) Name() string { return "tracing:" + plugin.role }
+
+func (plugin *gormPlugin) Initialize(database *gorm.DB) error {
+	callbacks := database.Callback()
+	prefix := plugin.Name()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register(prefix+":before_create", plugin.startQuery("create")),
+		callbacks.Create().After("gorm:create").Register(prefix+":after_create", plugin.finishQuery),
+		callbacks.Query().Before("gorm:query").Register(prefix+":before_query", plugin.startQuery("query")),
+		callbacks.Query().After("gorm:query").Register(prefix+":after_query", plugin.finishQuery),
+		callbacks.Update().Before("gorm:update").Register(prefix+":before_update", plugin.startQuery("update")),
+		callbacks.Update().After("gorm:update").Register(prefix+":after_update", plugin.finishQuery),
+		callbacks.Delete().Before("gorm:delete").Register(prefix+":before_delete", plugin.startQuery("delete")),
+		callbacks.Delete().After("gorm:delete").Register(prefix+":after_delete", plugin.finishQuery),
+		callbacks.Row().Before("gorm:row").Register(prefix+":before_row", plugin.startQuery("row")),
+		callbacks.Row().After("gorm:row").Register(prefix+":after_row", plugin.finishQuery),
+		callbacks.Raw().Before("gorm:raw").Register(prefix+":before_raw", plugin.startQuery("raw")),
+		callbacks.Raw().After("gorm:raw").Register(prefix+":after_raw", plugin.finishQuery),
+	)
+}
+
+func (plugin *gormPlugin) startQuery(operation string) func(database *gorm.DB) {
+	return func(database *gorm.DB) {
+		requestContext, span := plugin.tracer.Start(database.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", database.Dialector.Name()),
+				attribute.String("db.operation.name", operation),
+				attribute.String("db.role", plugin.role),
+			))
+		database.Statement.Context = requestContext
+		database.InstanceSet(querySpanKey, span)
+	}
+}
+
+func (plugin *gormPlugin) finishQuery(database *gorm.DB) {
+	value, found := database.InstanceGet(querySpanKey)
+	span, isSpan := value.(trace.Span)
+	if !found || !isSpan {
+		return
+	}
+	// SQL มีแต่ placeholder (?, $1) ค่าจริงอยู่ใน Vars ที่ไม่ถูกส่งออกไป
+	span.SetAttributes(
+		attribute.String("db.collection.name", database.Statement.Table),
+		attribute.String("db.query.text", database.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", database.Statement.RowsAffected),
+	)
+	if database.Error != nil && !errors.Is(database.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(database.Error)
+		span.SetStatus(codes.Error, database.Error.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,81 @@
+// Package tracing = อแดปเตอร์ OpenTelemetry: สร้าง TracerProvider ตาม env, span ของ use case และ query ของ GORM
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/config"
+)
+
+// instrumentationName = ชื่อ tracer ของแอปนี้ (ติดอยู่ในทุก span)
+const instrumentationName = "github.com/nuba55yo/go-101-CleanCRUD"
+
+// Propagator = W3C traceparent/tracestate + baggage ใช้ทั้งขาเข้าและขาออก
+func Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// NewProviderFromEnv สร้าง TracerProvider ตาม TRACING_EXPORTER คืน nil ถ้าปิด tracing
+//
+//	TRACING_EXPORTER      none (ค่าเริ่มต้น) | otlp | stdout | file
+//	TRACING_FILE          ไฟล์ปลายทางเมื่อ exporter = file (ค่าเริ่มต้น traces.jsonl)
+//	TRACING_SAMPLE_RATIO  สัดส่วน trace ที่เก็บ 0..1 (ค่าเริ่มต้น 1); มี parent แล้วทำตาม parent
+//	OTEL_SERVICE_NAME     ชื่อ service (ค่าเริ่มต้น books-api)
+//	OTEL_EXPORTER_OTLP_ENDPOINT / OTEL_EXPORTER_OTLP_TRACES_ENDPOINT / OTEL_EXPORTER_OTLP_HEADERS  ตั้งค่า OTLP/HTTP
+//
+// shutdown ส่ง span ที่ค้างอยู่ออกให้หมดแล้วปิด exporter (เรียกตอนปิดโปรแกรม)
+func NewProviderFromEnv(requestContext context.Context) (provider *sdktrace.TracerProvider, shutdown func(context.Context) error, err error) {
+	var exporter sdktrace.SpanExporter
+	var output io.Closer
+	switch backend := os.Getenv("TRACING_EXPORTER"); backend {
+	case "", "none":
+		return nil, func(context.Context) error { return nil }, nil
+	case "otlp":
+		exporter, err = otlptracehttp.New(requestContext)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "file":
+		file, openError := os.OpenFile(config.String("TRACING_FILE", "traces.jsonl"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if openError != nil {
+			return nil, nil, openError
+		}
+		output = file
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	default:
+		return nil, nil, fmt.Errorf("unknown TRACING_EXPORTER %q (want none, otlp, stdout or file)", backend)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serviceResource, err := resource.Merge(resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", config.String("OTEL_SERVICE_NAME", "books-api"))))
+	if err != nil {
+		return nil, nil, err
+	}
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(serviceResource),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.Float("TRACING_SAMPLE_RATIO", 1)))),
+	)
+	shutdown = func(shutdownContext context.Context) error {
+		err := provider.Shutdown(shutdownContext)
+		if output != nil {
+			if closeError := output.Close(); err == nil {
+				err = closeError
+			}
+		}
+		return err
+	}
+	return provider, shutdown, nil
+}
@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+)
+
+// Tracer คืออแดปเตอร์ที่ implement interfaces.Tracer ด้วย OpenTelemetry
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	return &Tracer{tracer: provider.Tracer(instrumentationName)}
+}
+
+func (tracer *Tracer) Start(requestContext context.Context, name string, keyValues ...any) (context.Context, interfaces.Span) {
+	requestContext, span := tracer.tracer.Start(requestContext, name, trace.WithAttributes(attributesOf(keyValues)...))
+	return requestContext, &otelSpan{span: span}
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (span *otelSpan) SetAttributes(keyValues ...any) {
+	span.span.SetAttributes(attributesOf(keyValues)...)
+}
+
+func (span *otelSpan) End(err error) {
+	if err != nil {
+		span.span.RecordError(err)
+		span.span.SetStatus(codes.Error, err.Error())
+	}
+	span.span.End()
+}
+
+// attributesOf แปลง key, value, key, value, ... (แบบเดียวกับ Logger) เป็น attribute ของ OpenTelemetry
+func attributesOf(keyValues []any) []attribute.KeyValue {
+	attributes := make([]attribute.KeyValue, 0, len(keyValues)/2)
+	for index := 0; index+1 < len(keyValues); index += 2 {
+		key := fmt.Sprint(keyValues[index])
+		switch value := keyValues[index+1].(type) {
+		case string:
+			attributes = append(attributes, attribute.String(key, value))
+		case bool:
+			attributes = append(attributes, attribute.Bool(key, value))
+		case int:
+			attributes = append(attributes, attribute.Int(key, value))
+		case int64:
+			attributes = append(attributes, attribute.Int64(key, value))
+		case uint:
+			attributes = append(attributes, attribute.Int64(key, int64(value)))
+		case float64:
+			attributes = append(attributes, attribute.Float64(key, value))
+		default:
+			attributes = append(attributes, attribute.String(key, fmt.Sprint(value)))
+		}
+	}
+	return attributes
+}
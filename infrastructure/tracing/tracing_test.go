@@ -0,0 +1,83 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	gormp "github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/gorm"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/tracing"
+)
+
+func newRecordingProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)), exporter
+}
+
+func TestTracerSpansNestAndRecordErrors(t *testing.T) {
+	provider, exporter := newRecordingProvider()
+	tracer := tracing.NewTracer(provider)
+
+	parentContext, parent := tracer.Start(context.Background(), "parent", "book.id", uint(7))
+	_, child := tracer.Start(parentContext, "child")
+	child.End(errors.New("boom"))
+	parent.End(nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	childSpan, parentSpan := spans[0], spans[1]
+	if childSpan.Parent.SpanID() != parentSpan.SpanContext.SpanID() {
+		t.Fatalf("child parent = %s, want %s", childSpan.Parent.SpanID(), parentSpan.SpanContext.SpanID())
+	}
+	if childSpan.Status.Code != codes.Error || parentSpan.Status.Code != codes.Unset {
+		t.Fatalf("statuses = %v/%v, want Error/Unset", childSpan.Status.Code, parentSpan.Status.Code)
+	}
+	if len(parentSpan.Attributes) != 1 || parentSpan.Attributes[0].Value.AsInt64() != 7 {
+		t.Fatalf("parent attributes = %v", parentSpan.Attributes)
+	}
+}
+
+func TestInstrumentGormCreatesChildSpans(t *testing.T) {
+	database, err := gormp.OpenWith(gormp.DriverSQLite, filepath.Join(t.TempDir(), "tracing.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider, exporter := newRecordingProvider()
+	if err := tracing.InstrumentGorm(database, provider, "primary"); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.Exec("CREATE TABLE widgets (id INTEGER PRIMARY KEY)").Error; err != nil {
+		t.Fatal(err)
+	}
+	exporter.Reset()
+
+	requestContext, request := provider.Tracer("test").Start(context.Background(), "GET /widgets")
+	var count int64
+	if err := database.WithContext(requestContext).Table("widgets").Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	request.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want query + request", len(spans))
+	}
+	query := spans[0]
+	if query.Name != "gorm.query" || query.Parent.SpanID() != request.SpanContext().SpanID() {
+		t.Fatalf("query span = %s parent %s, want gorm.query under request", query.Name, query.Parent.SpanID())
+	}
+	attributes := map[string]string{}
+	for _, attribute := range query.Attributes {
+		attributes[string(attribute.Key)] = attribute.Value.Emit()
+	}
+	if attributes["db.collection.name"] != "widgets" || attributes["db.system"] != "sqlite" {
+		t.Fatalf("query attributes = %v", attributes)
+	}
+}
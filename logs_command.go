@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/accesslog"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/config"
+)
+
+const logsUsage = `usage: logs [flags]
+  --dir DIR            โฟลเดอร์ access log (ค่าเริ่มต้น ACCESS_LOG_DIR หรือ logs)
+  --since 1h           ย้อนหลังจากตอนนี้ (ใช้แทน --from)
+  --from, --to TIME    ช่วงเวลา เช่น 2025-08-09, "2025-08-09 05:00", 2025-08-09T05:00:00+07:00
+  --status 5xx,404     --method GET,POST   --route /api/v1/books/*   --module books   --ip 10.0.0.1
+  --min-latency 200ms  --body TEXT (หาใน req/res)
+  --output text|json|summary   --limit N (0 = ไม่จำกัด)`
+
+// errLogsLimitReached หยุดการค้นเมื่อได้ครบ --limit
+var errLogsLimitReached = errors.New("limit reached")
+
+// runLogsCommand จัดการคำสั่งย่อย `logs ...`: ค้น access log ที่หมุนไว้ (ทั้ง .log และ .log.gz, ทั้งแบบ text และ json)
+func runLogsCommand(arguments []string, output io.Writer) error {
+	flags := flag.NewFlagSet("logs", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprintln(flags.Output(), logsUsage) }
+	directory := flags.String("dir", config.String("ACCESS_LOG_DIR", "logs"), "")
+	since := flags.Duration("since", 0, "")
+	fromText := flags.String("from", "", "")
+	toText := flags.String("to", "", "")
+	statusSpec := flags.String("status", "", "")
+	methods := flags.String("method", "", "")
+	routes := flags.String("route", "", "")
+	modules := flags.String("module", "", "")
+	ips := flags.String("ip", "", "")
+	minLatency := flags.Duration("min-latency", 0, "")
+	body := flags.String("body", "", "")
+	format := flags.String("output", "text", "")
+	limit := flags.Int("limit", 0, "")
+	if err := flags.Parse(arguments); errors.Is(err, flag.ErrHelp) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return errors.New(logsUsage)
+	}
+
+	filter := accesslog.Filter{
+		Methods: splitList(*methods), Routes: splitList(*routes), Modules: splitList(*modules), IPs: splitList(*ips),
+		MinLatency: *minLatency, BodyContains: *body,
+	}
+	var err error
+	if filter.Statuses, err = accesslog.ParseStatusFilter(*statusSpec); err != nil {
+		return err
+	}
+	if filter.From, err = parseLogsTime(*fromText); err != nil {
+		return err
+	}
+	if filter.To, err = parseLogsTime(*toText); err != nil {
+		return err
+	}
+	if *since > 0 {
+		filter.From = time.Now().Add(-*since)
+	}
+
+	var print func(accesslog.Record) error
+	summary := accesslog.NewSummary()
+	encoder := json.NewEncoder(output)
+	encoder.SetEscapeHTML(false)
+	switch *format {
+	case "text":
+		print = func(record accesslog.Record) error { return printLogRecord(output, record) }
+	case "json":
+		print = func(record accesslog.Record) error { return encoder.Encode(logRecordJSON(record)) }
+	case "summary":
+		print = func(record accesslog.Record) error { summary.Add(record); return nil }
+	default:
+		return fmt.Errorf("output %q: want text, json or summary", *format)
+	}
+
+	matched := 0
+	skipped, err := accesslog.Scan(*directory, filter, func(record accesslog.Record) error {
+		if err := print(record); err != nil {
+			return err
+		}
+		if matched++; *limit > 0 && matched >= *limit {
+			return errLogsLimitReached
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errLogsLimitReached) {
+		return err
+	}
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "skipped %d unparseable lines\n", skipped)
+	}
+	if *format == "summary" {
+		return printLogSummary(output, summary.Routes())
+	}
+	return nil
+}
+
+// parseLogsTime รับวันที่/เวลาหลายรูปแบบ เวลาที่ไม่มี timezone ถือเป็นเวลาท้องถิ่น (ตรงกับชื่อไฟล์)
+func parseLogsTime(text string) (time.Time, error) {
+	if text == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, text); err == nil {
+		return parsed, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"} {
+		if parsed, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: want e.g. 2025-08-09, \"2025-08-09 05:00\" or RFC 3339", text)
+}
+
+func splitList(text string) []string {
+	var items []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// orDash แทนค่าว่าง (เช่น route ของ 404 ในบรรทัดแบบ text ที่ไม่มี path) ให้คอลัมน์ไม่เลื่อน
+func orDash(text string) string {
+	if text == "" {
+		return "-"
+	}
+	return text
+}
+
+func printLogRecord(output io.Writer, record accesslog.Record) error {
+	_, err := fmt.Fprintf(output, "%s [%s] [%s] %d %s %s %s ip=%s request_id=%s\n",
+		record.Time.Local().Format("2006-01-02 15:04:05.000"), record.Module, record.Level, record.Status,
+		record.Method, orDash(record.RouteOrPath()), record.Latency, record.IP, record.RequestID)
+	return err
+}
+
+// logRecordJSON = หนึ่ง object ต่อบรรทัด ชื่อฟิลด์เดียวกับ ACCESS_LOG_FORMAT=json
+func logRecordJSON(record accesslog.Record) map[string]any {
+	values := map[string]any{
+		"time": record.Time.UTC().Format(time.RFC3339Nano), "status": record.Status,
+		"latency_ms": float64(record.Latency.Microseconds()) / 1000, "file": record.File,
+	}
+	for name, value := range map[string]string{
+		"level": record.Level, "module": record.Module, "request_id": record.RequestID, "method": record.Method,
+		"route": record.Route, "path": record.Path, "ip": record.IP, "req": record.RequestBody, "res": record.ResponseBody,
+	} {
+		if value != "" {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+func printLogSummary(output io.Writer, routes []accesslog.RouteSummary) error {
+	writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "METHOD\tROUTE\tCOUNT\t2XX\t3XX\t4XX\t5XX\tP50\tP95\tP99")
+	for _, route := range routes {
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%s\t%s\t%s\n", route.Method, orDash(route.Route), route.Count,
+			route.StatusClasses[2], route.StatusClasses[3], route.StatusClasses[4], route.StatusClasses[5],
+			route.P50, route.P95, route.P99)
+	}
+	return writer.Flush()
+}
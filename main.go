@@ -1,56 +1,458 @@
 ﻿package main
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
 
 	_ "github.com/nuba55yo/go-101-CleanCRUD/docs/v1"
 	_ "github.com/nuba55yo/go-101-CleanCRUD/docs/v2"
 
+	"github.com/nuba55yo/go-101-CleanCRUD/application/authorization"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/health"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
 	"github.com/nuba55yo/go-101-CleanCRUD/application/usecase"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/accesslog"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/auth"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/cache"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/config"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/idempotency"
 	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/logging"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/metrics"
 	gormp "github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/gorm"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/memory"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/ratelimit"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/tracing"
 	httpx "github.com/nuba55yo/go-101-CleanCRUD/presentation/http/router"
+	"github.com/nuba55yo/go-101-CleanCRUD/presentation/middleware"
 )
 
 type systemClock struct{}
 
 func (systemClock) Now() time.Time { return time.Now() }
 
+// openBookRepository เลือกอแดปเตอร์ตามค่า STORAGE
+// memory = เก็บในหน่วยความจำ (เดโม/เทส ไม่ต้องมีฐานข้อมูล), ว่างหรือ db = GORM ตาม DB_DRIVER
+// postgres/sqlite/mysql = db ที่ driver นั้น (ค่าเดิมก่อนมี DB_DRIVER)
+// คืน *gorm.DB ด้วย (nil เมื่อใช้ memory) ไว้ต่อ monitoring และ closeStorage ไว้ปิด pool ตอน shutdown
+// health check ของฐานข้อมูล (ping, migration, replica) ลงทะเบียนไว้ใน healthRegistry
+// logger รับข้อความตอนรอฐานข้อมูลที่ยังไม่พร้อม, instrument ติด metrics/tracing ให้ทุกฐานข้อมูลที่เปิด (primary และ replica)
+func openBookRepository(storage string, healthRegistry *health.Registry, logger interfaces.Logger, instrument func(database *gorm.DB, role string) error) (bookRepository interfaces.BookRepository, db *gorm.DB, closeStorage func() error, err error) {
+	switch storage {
+	case "memory":
+		return memory.NewBookRepositoryMemory(), nil, func() error { return nil }, nil
+	case "", "db", gormp.DriverPostgres, gormp.DriverSQLite, gormp.DriverMySQL:
+		// ค่าเดิม STORAGE=postgres (และชื่อ driver อื่น) ยังใช้ได้ = db ที่ driver นั้น
+		// ตั้ง DB_DRIVER ให้ด้วย เพื่อให้ replica และคำสั่งอื่นที่อ่าน DB_DRIVER ใช้ driver เดียวกัน
+		if storage != "" && storage != "db" {
+			if driver := os.Getenv("DB_DRIVER"); driver != "" && driver != storage {
+				return nil, nil, nil, fmt.Errorf("STORAGE=%s conflicts with DB_DRIVER=%s", storage, driver)
+			}
+			if err := os.Setenv("DB_DRIVER", storage); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		db, err = gormp.Open(logger)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		migrator, err := gormp.NewMigrator(db)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		// DB_AUTO_MIGRATE=false: ไม่ migrate ตอนบูต (ให้รัน `migrate up` แยกก่อน deploy)
+		// แต่ยังเช็คว่า schema เป็นเวอร์ชันล่าสุด ไม่งั้นไม่ยอมสตาร์ต
+		if config.Bool("DB_AUTO_MIGRATE", true) {
+			err = migrator.Up(context.Background())
+		} else {
+			err = migrator.EnsureMigrated(context.Background())
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		healthRegistry.Register("database", func(requestContext context.Context) error {
+			return gormp.Ping(requestContext, db)
+		}, health.Critical(), health.WithComponentType("datastore"))
+		healthRegistry.Register("migrations", migrator.EnsureMigrated, health.Critical())
+		// read replica (ถ้าตั้ง DB_REPLICA_DSNS) + read-your-writes หลังเขียน
+		replicas, err := gormp.OpenReplicasFromEnv(logger)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if err := instrument(db, "primary"); err != nil {
+			return nil, nil, nil, err
+		}
+		if replicas != nil {
+			healthRegistry.Register("database-replicas", replicas.Check, health.WithComponentType("datastore"))
+			for index, replica := range replicas.Databases() {
+				if err := instrument(replica, fmt.Sprintf("replica-%d", index)); err != nil {
+					return nil, nil, nil, err
+				}
+			}
+		}
+		repositoryOptions := []gormp.Option{
+			gormp.WithRetryPolicy(gormp.RetryPolicyFromEnv()),
+			gormp.WithReplicas(replicas),
+			gormp.WithReadYourWrites(config.Duration("DB_READ_YOUR_WRITES_WINDOW", 5*time.Second)),
+		}
+		// DB_TENANT_RLS=true (Postgres): ให้ฐานข้อมูลบังคับแยก tenant อีกชั้นด้วย row-level security
+		// (ALTER TABLE เฉพาะเมื่อสถานะในฐานข้อมูลต่างจากค่าที่ตั้ง)
+		rowSecurity := config.Bool("DB_TENANT_RLS", false)
+		if err := gormp.ConfigureRowLevelSecurity(context.Background(), db, rowSecurity); err != nil {
+			return nil, nil, nil, err
+		}
+		if rowSecurity {
+			repositoryOptions = append(repositoryOptions, gormp.WithRowLevelSecurity())
+		}
+		closeStorage = func() error {
+			if replicas != nil {
+				_ = replicas.Close()
+			}
+			return gormp.Close(db)
+		}
+		return gormp.NewBookRepositoryGorm(db, repositoryOptions...), db, closeStorage, nil
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown STORAGE %q (want db, postgres, sqlite, mysql or memory)", storage)
+	}
+}
+
+// instrumentDatabase ติด metrics ของ query/connection pool และ span ของ query ให้ database
+// appMetrics หรือ tracerProvider เป็น nil = ข้ามส่วนนั้น
+func instrumentDatabase(appMetrics *metrics.Metrics, tracerProvider trace.TracerProvider, database *gorm.DB, role string) error {
+	if appMetrics != nil {
+		if err := appMetrics.InstrumentGorm(database, role); err != nil {
+			return err
+		}
+		sqlDatabase, err := database.DB()
+		if err != nil {
+			return err
+		}
+		if err := appMetrics.RegisterDBStats(sqlDatabase, role); err != nil {
+			return err
+		}
+	}
+	if tracerProvider != nil {
+		return tracing.InstrumentGorm(database, tracerProvider, role)
+	}
+	return nil
+}
+
 func main() {
 	_ = godotenv.Load()
 
-	// DB
-	db, err := gormp.Open()
+	// คำสั่งย่อย: go run . migrate up|down|status|to N
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	// go run . apikeys create --name bootstrap (ออกคีย์ scope admin คีย์แรก) | list | revoke <id>
+	if len(os.Args) > 1 && os.Args[1] == "apikeys" {
+		if err := runAPIKeysCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	// go run . logs --since 1h --status 5xx --output summary (ค้น access log ที่หมุนไว้)
+	if len(os.Args) > 1 && os.Args[1] == "logs" {
+		if err := runLogsCommand(os.Args[2:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Health check: อแดปเตอร์แต่ละตัวลงทะเบียน check ของตัวเองไว้ที่นี่ (/readyz, /admin/health/details)
+	healthRegistry := health.NewRegistry(config.Duration("HEALTH_CHECK_TIMEOUT", 2*time.Second))
+
+	// Metrics (METRICS_ENABLED, ค่าเริ่มต้นเปิด): /metrics บนพอร์ต API หรือแยกพอร์ตตาม METRICS_ADDR
+	var appMetrics *metrics.Metrics
+	if config.Bool("METRICS_ENABLED", true) {
+		appMetrics = metrics.New()
+		middleware.SetAccessLogErrorHandler(appMetrics.AccessLogWriteFailed)
+	}
+	metricsAddress := os.Getenv("METRICS_ADDR")
+
+	// Tracing (TRACING_EXPORTER=otlp|stdout|file): span ต่อ request → use case → query
+	sdkTracerProvider, shutdownTracing, err := tracing.NewProviderFromEnv(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	var tracerProvider trace.TracerProvider
+	if sdkTracerProvider != nil {
+		tracerProvider = sdkTracerProvider
+	}
+
+	// Logger (use case และการรอฐานข้อมูลตอนบูต)
+	// LOG_LEVEL / LOG_ENCODING / LOG_SAMPLING_*; ปรับระดับขณะรันได้ที่ /admin/log-level
+	appLogger, flush, err := logging.NewZapLogger(logging.ConfigFromEnv())
 	if err != nil {
 		log.Fatal(err)
 	}
-	if err := gormp.AutoMigrateTables(db); err != nil {
+	defer func() { _ = flush() }()
+
+	// Storage (DB หรือ memory)
+	bookRepository, db, closeStorage, err := openBookRepository(os.Getenv("STORAGE"), healthRegistry, appLogger,
+		func(database *gorm.DB, role string) error {
+			return instrumentDatabase(appMetrics, tracerProvider, database, role)
+		})
+	if err != nil {
 		log.Fatal(err)
 	}
-	if err := gormp.EnsureIndexes(db); err != nil {
+	// ready = สถานะของ /readyz (true หลัง server เริ่มรับ request, false ทันทีที่เริ่ม shutdown)
+	var ready atomic.Bool
+	routerOptions := []httpx.Option{
+		httpx.WithReadiness(ready.Load),
+		httpx.WithHealth(healthRegistry),
+	}
+	if appMetrics != nil {
+		var metricsPage http.Handler
+		if metricsAddress == "" {
+			metricsPage = appMetrics.Handler()
+		}
+		routerOptions = append(routerOptions, httpx.WithMetrics(appMetrics, metricsPage))
+	}
+	if tracerProvider != nil {
+		routerOptions = append(routerOptions, httpx.WithTracing(tracerProvider, tracing.Propagator()))
+	}
+	var databaseStats func() (sql.DBStats, error)
+	if db != nil {
+		databaseStats = func() (sql.DBStats, error) { return gormp.PoolStats(db) }
+	}
+
+	// Cache (CACHE_BACKEND=memory|redis) ครอบ repository อีกชั้น
+	cacheStore, err := cache.OpenStoreFromEnv()
+	if err != nil {
 		log.Fatal(err)
 	}
+	if pinger, ok := cacheStore.(health.Pinger); ok {
+		healthRegistry.Register("cache", pinger.Ping, health.WithComponentType("datastore"))
+	}
+	var cacheStats func() any
+	if cacheStore != nil {
+		cachedRepository := cache.NewBookRepositoryCache(bookRepository, cacheStore, cache.ConfigFromEnv())
+		bookRepository = cachedRepository
+		cacheStats = func() any { return cachedRepository.Stats() }
+	}
 
-	// Logger (use case)
-	appLogger, flush, err := logging.NewZapLogger()
+	// Auth: เปิดเมื่อมีกุญแจ JWT (AUTH_JWT_HS256_SECRET / AUTH_JWT_PUBLIC_KEY_FILE / AUTH_JWT_JWKS_URL)
+	jwtVerifier, err := auth.JWTVerifierFromEnv()
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer func() { _ = flush() }()
+	if jwtVerifier != nil {
+		routerOptions = append(routerOptions, httpx.WithBearerAuth(jwtVerifier))
+	}
+
+	// Access log: ปิดบัง header/ฟิลด์ลับก่อนเขียนไฟล์ (ตั้ง ACCESS_LOG_REDACT_* = แทนที่ค่าเริ่มต้นของรายการนั้น)
+	defaultRedaction := middleware.DefaultRedactionConfig()
+	redactor, err := middleware.NewRedactor(middleware.RedactionConfig{
+		Headers:         config.List("ACCESS_LOG_REDACT_HEADERS", ",", defaultRedaction.Headers),
+		FieldPaths:      config.List("ACCESS_LOG_REDACT_FIELDS", ",", defaultRedaction.FieldPaths),
+		Patterns:        config.List("ACCESS_LOG_REDACT_PATTERNS", ";", defaultRedaction.Patterns),
+		MaskCardNumbers: config.Bool("ACCESS_LOG_MASK_CARDS", defaultRedaction.MaskCardNumbers),
+		SkipBodyRoutes:  config.List("ACCESS_LOG_SKIP_BODY_ROUTES", ";", defaultRedaction.SkipBodyRoutes),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	// ACCESS_LOG_FORMAT=text|json (JSON Lines), ACCESS_LOG_FIELDS เลือกฟิลด์ของรูปแบบ json
+	accessLogFormat, err := middleware.ParseAccessLogFormat(os.Getenv("ACCESS_LOG_FORMAT"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	accessLogFields, err := middleware.ParseAccessLogFields(os.Getenv("ACCESS_LOG_FIELDS"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	// ปลายทาง access log: ACCESS_LOG_SINKS=file,stdout,syslog,http (ไฟล์: ACCESS_LOG_DIR, ACCESS_LOG_ROTATE_INTERVAL, ...)
+	accessLogWriter, err := accesslog.OpenWriterFromEnv(func(err error) {
+		appLogger.Warn(context.Background(), "access log sink failed", "error", err)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	// ACCESS_LOG_ASYNC (ค่าเริ่มต้นเปิด): request แค่ใส่คิว เขียนลงดิสก์เป็นชุดเบื้องหลัง (ACCESS_LOG_QUEUE_SIZE, _OVERFLOW ...)
+	if config.Bool("ACCESS_LOG_ASYNC", true) {
+		asyncConfig, err := accesslog.AsyncConfigFromEnv()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if appMetrics != nil {
+			asyncConfig.OnDrop = appMetrics.AccessLogLineDropped
+			asyncConfig.OnError = appMetrics.AccessLogWriteFailed
+		}
+		accessLogWriter = accesslog.NewAsyncWriter(accessLogWriter, asyncConfig)
+	}
+	// ขนาด/ชนิดของ body ที่เก็บ, path ที่ไม่บันทึก และการสุ่มเก็บ request ที่สำเร็จ (4xx/5xx บันทึกเสมอ)
+	accessLogConfig := middleware.DefaultAccessLogConfig()
+	accessLogConfig.Redactor, accessLogConfig.Writer = redactor, accessLogWriter
+	accessLogConfig.Format, accessLogConfig.Fields = accessLogFormat, accessLogFields
+	accessLogConfig.MaxRequestBodyBytes = config.Int("ACCESS_LOG_MAX_REQUEST_BODY_BYTES", accessLogConfig.MaxRequestBodyBytes)
+	accessLogConfig.MaxResponseBodyBytes = config.Int("ACCESS_LOG_MAX_RESPONSE_BODY_BYTES", accessLogConfig.MaxResponseBodyBytes)
+	accessLogConfig.BodyContentTypes = config.List("ACCESS_LOG_BODY_CONTENT_TYPES", ",", accessLogConfig.BodyContentTypes)
+	accessLogConfig.SkipPaths = config.List("ACCESS_LOG_SKIP_PATHS", ",", accessLogConfig.SkipPaths)
+	accessLogConfig.SuccessSampleRate = config.Float("ACCESS_LOG_SUCCESS_SAMPLE_RATE", accessLogConfig.SuccessSampleRate)
+	routerOptions = append(routerOptions, httpx.WithAccessLog(accessLogConfig))
+
+	// Multi-tenancy (MULTI_TENANCY=true): tenant มาจาก claim ของ credential, header หรือ subdomain
+	if config.Bool("MULTI_TENANCY", false) {
+		tenantHeader := os.Getenv("TENANT_HEADER")
+		if tenantHeader == "" {
+			tenantHeader = "X-Tenant-ID"
+		}
+		routerOptions = append(routerOptions, httpx.WithTenancy(middleware.TenantConfig{
+			Header:     tenantHeader,
+			BaseDomain: os.Getenv("TENANT_BASE_DOMAIN"),
+			Required:   config.Bool("TENANT_REQUIRED", false),
+		}))
+	}
+
+	// Rate limit (RATE_LIMIT_BACKEND=memory|redis): ค่าเริ่มต้น RATE_LIMIT_DEFAULT + กฎเฉพาะเส้นทาง RATE_LIMIT_RULES
+	rateLimitStore, err := ratelimit.OpenStoreFromEnv(systemClock{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if pinger, ok := rateLimitStore.(health.Pinger); ok {
+		healthRegistry.Register("rate-limit-store", pinger.Ping, health.WithComponentType("datastore"))
+	}
+	if rateLimitStore != nil {
+		defaultLimit, err := middleware.ParseRateLimit(config.String("RATE_LIMIT_DEFAULT", "100/1m"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		rules, err := middleware.ParseRateLimitRules(os.Getenv("RATE_LIMIT_RULES"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		// RATE_LIMIT_PRE_AUTH: ต่อ IP ก่อนตรวจ credential (ใช้เมื่อเปิด auth; "off" = ไม่จำกัด)
+		var preAuthLimit interfaces.RateLimit
+		if preAuthSpec := config.String("RATE_LIMIT_PRE_AUTH", "300/1m"); preAuthSpec != "off" {
+			if preAuthLimit, err = middleware.ParseRateLimit(preAuthSpec); err != nil {
+				log.Fatal(err)
+			}
+		}
+		routerOptions = append(routerOptions, httpx.WithRateLimit(middleware.RateLimitConfig{
+			Store: rateLimitStore, Default: defaultLimit, Rules: rules, PreAuth: preAuthLimit,
+		}))
+	}
+
+	// Idempotency-Key (IDEMPOTENCY_BACKEND=memory|redis) สำหรับ POST ที่ client retry
+	idempotencyStore, err := idempotency.OpenStoreFromEnv(systemClock{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if pinger, ok := idempotencyStore.(health.Pinger); ok {
+		healthRegistry.Register("idempotency-store", pinger.Ping, health.WithComponentType("datastore"))
+	}
+	if idempotencyStore != nil {
+		routerOptions = append(routerOptions, httpx.WithIdempotency(middleware.IdempotencyConfig{
+			Store:        idempotencyStore,
+			TTL:          config.Duration("IDEMPOTENCY_TTL", 24*time.Hour),
+			LockTTL:      config.Duration("IDEMPOTENCY_LOCK_TTL", time.Minute),
+			Methods:      strings.Split(strings.ToUpper(config.String("IDEMPOTENCY_METHODS", "POST")), ","),
+			MaxBodyBytes: int64(config.Int("IDEMPOTENCY_MAX_BODY_BYTES", 1<<20)),
+		}))
+	}
+
+	// สิทธิ์ตามบทบาท: ใช้เมื่อเปิดการยืนยันตัวตน (ไม่เปิด = policy nil ทุกคำขอผ่าน)
+	// AUTH_POLICY_FILE แทนที่นโยบายเริ่มต้น (viewer/editor/admin)
+	apiKeysEnabled := config.Bool("AUTH_API_KEYS", false)
+	var policy *authorization.Policy
+	if jwtVerifier != nil || apiKeysEnabled {
+		policy = authorization.DefaultPolicy()
+		if policyPath := os.Getenv("AUTH_POLICY_FILE"); policyPath != "" {
+			if policy, err = auth.LoadPolicyFile(policyPath); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+
+	// API key (AUTH_API_KEYS=true): เก็บในฐานข้อมูลเดียวกับหนังสือ (หรือในหน่วยความจำเมื่อ STORAGE=memory)
+	if apiKeysEnabled {
+		var apiKeyRepository interfaces.APIKeyRepository
+		if db != nil {
+			apiKeyRepository = gormp.NewAPIKeyRepositoryGorm(db)
+		} else {
+			apiKeyRepository = memory.NewAPIKeyRepositoryMemory()
+		}
+		apiKeyUseCase := usecase.NewAPIKeyUseCase(apiKeyRepository,
+			auth.NewBcryptHasher(config.Int("AUTH_API_KEY_BCRYPT_COST", 10)), systemClock{}, appLogger, policy)
+		routerOptions = append(routerOptions, httpx.WithAPIKeys(apiKeyUseCase))
+	}
+
+	// GET/PUT /admin/log-level: เปิดเองเมื่อมีการยืนยันตัวตน (ต้องมี logs:manage); ไม่มี auth ต้องตั้ง LOG_LEVEL_ENDPOINT=true
+	if config.Bool("LOG_LEVEL_ENDPOINT", policy != nil) {
+		routerOptions = append(routerOptions, httpx.WithLogLevelControl(usecase.NewLogLevelUseCase(appLogger, appLogger, policy)))
+	}
+
+	// GET /admin/debug/db/stats, /admin/debug/cache/stats, /admin/health/details: เปิดเองเมื่อมีการยืนยันตัวตน (ต้องมี diagnostics:read)
+	// ไม่มี auth ต้องตั้ง DEBUG_ENDPOINTS=true เอง (ใครก็ดูได้)
+	if config.Bool("DEBUG_ENDPOINTS", policy != nil) {
+		routerOptions = append(routerOptions, httpx.WithDiagnostics(
+			usecase.NewDiagnosticsUseCase(databaseStats, cacheStats, healthRegistry, appLogger, policy)))
+	}
 
 	// DI: Repository -> UseCase -> Router
-	bookRepository := gormp.NewBookRepositoryGorm(db)
-	bookUseCase := usecase.NewBookUseCase(bookRepository, systemClock{}, appLogger)
-	router := httpx.NewRouter(bookUseCase) // ??? /api/v1, /api/v2, /docs, /swagger
+	bookUseCase := usecase.NewBookUseCase(bookRepository, systemClock{}, appLogger, policy)
+	if appMetrics != nil {
+		bookUseCase = usecase.WithOperationMetrics(bookUseCase, appMetrics)
+	}
+	if tracerProvider != nil {
+		bookUseCase = usecase.WithTracing(bookUseCase, tracing.NewTracer(tracerProvider))
+	}
+	router := httpx.NewRouter(bookUseCase, routerOptions...) // ??? /api/v1, /api/v2, /docs, /swagger
 
-	// Run
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// Run: รอ SIGINT/SIGTERM แล้วปิดแบบ graceful (request ค้างทำจนเสร็จก่อนปิด)
+	signalContext, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	serverSettings := serverConfigFromEnv()
+	stopMetricsServer := func() {}
+	if appMetrics != nil && metricsAddress != "" {
+		metricsSettings := serverSettings
+		metricsSettings.Address = metricsAddress
+		stopMetricsServer = serveInBackground(newHTTPServer(appMetrics.Handler(), metricsSettings), metricsSettings, appLogger)
+	}
+	listener, err := net.Listen("tcp", serverSettings.Address)
+	if err != nil {
+		log.Fatal(err)
+	}
+	serveError := serveUntilSignal(signalContext, newHTTPServer(router, serverSettings), listener, serverSettings, &ready, appLogger)
+	stopSignals()
+	if serveError != nil {
+		appLogger.Error(context.Background(), "server stopped with error", "error", serveError)
+	}
+	stopMetricsServer()
+
+	// ปิด resource หลัง server หยุดรับ request แล้ว: DB pool → access log → trace ที่ค้าง → zap
+	if err := closeStorage(); err != nil {
+		appLogger.Error(context.Background(), "close storage failed", "error", err)
+	}
+	// collector ทาง HTTP ที่ล่มอยู่จะไม่ทำให้ปิดโปรแกรมค้างเกิน SHUTDOWN_TIMEOUT
+	accessLogContext, cancelAccessLog := context.WithTimeout(context.Background(), serverSettings.ShutdownTimeout)
+	if err := accesslog.CloseContext(accessLogContext, accessLogWriter); err != nil {
+		appLogger.Error(context.Background(), "close access log failed", "error", err)
+	}
+	cancelAccessLog()
+	tracingContext, cancelTracing := context.WithTimeout(context.Background(), serverSettings.ShutdownTimeout)
+	if err := shutdownTracing(tracingContext); err != nil {
+		appLogger.Error(context.Background(), "flush traces failed", "error", err)
+	}
+	cancelTracing()
+	if serveError != nil {
+		_ = flush() // os.Exit ไม่เรียก defer
+		os.Exit(1)
 	}
-	_ = router.Run(":" + port)
 }
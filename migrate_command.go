@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/logging"
+	gormp "github.com/nuba55yo/go-101-CleanCRUD/infrastructure/persistence/gorm"
+)
+
+const migrateUsage = "usage: migrate up | down | status | to <version>"
+
+// runMigrateCommand จัดการคำสั่งย่อย `migrate ...` (ใช้ DB_DRIVER/DB_DSN เดียวกับตอนรันเซิร์ฟเวอร์)
+func runMigrateCommand(arguments []string) error {
+	if len(arguments) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	logger, flush, err := logging.NewZapLogger(logging.ConfigFromEnv())
+	if err != nil {
+		return err
+	}
+	defer flush()
+	db, err := gormp.Open(logger)
+	if err != nil {
+		return err
+	}
+	migrator, err := gormp.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	requestContext := context.Background()
+
+	switch arguments[0] {
+	case "up":
+		err = migrator.Up(requestContext)
+	case "down":
+		err = migrator.Down(requestContext)
+	case "to":
+		if len(arguments) != 2 {
+			return errors.New(migrateUsage)
+		}
+		target, convertError := strconv.Atoi(arguments[1])
+		if convertError != nil {
+			return fmt.Errorf("invalid version %q", arguments[1])
+		}
+		err = migrator.To(requestContext, target)
+	case "status":
+		// status แค่แสดงผล ไม่ต้องพิมพ์สถานะซ้ำด้านล่าง
+		return printMigrationStatus(requestContext, migrator)
+	default:
+		return errors.New(migrateUsage)
+	}
+	if err != nil {
+		return err
+	}
+	return printMigrationStatus(requestContext, migrator)
+}
+
+func printMigrationStatus(requestContext context.Context, migrator *gormp.Migrator) error {
+	statuses, err := migrator.Status(requestContext)
+	if err != nil {
+		return err
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(writer, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+	return writer.Flush()
+}
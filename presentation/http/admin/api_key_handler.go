@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/usecase"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+// IssueAPIKey ออกคีย์ใหม่; key เต็มอยู่ในคำตอบนี้ครั้งเดียว
+func IssueAPIKey(apiKeyUseCase usecase.APIKeyUseCase) gin.HandlerFunc {
+	return func(requestContext *gin.Context) {
+		var requestBody IssueAPIKeyJSON
+		if bindError := requestContext.ShouldBindJSON(&requestBody); bindError != nil {
+			requestContext.JSON(http.StatusBadRequest, gin.H{"error": bindError.Error()})
+			return
+		}
+		issued, issueError := apiKeyUseCase.Issue(requestContext, MapIssueJSONToCommand(requestBody))
+		if issueError != nil {
+			if errors.Is(issueError, domain.ErrForbidden) {
+				requestContext.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+				return
+			}
+			if errors.Is(issueError, domain.ErrBadInput) {
+				requestContext.JSON(http.StatusBadRequest,
+					gin.H{"error": "name is required, scope must be read-only, read-write or admin, expires_in must be a positive duration"})
+				return
+			}
+			requestContext.JSON(http.StatusInternalServerError, gin.H{"error": "issue failed"})
+			return
+		}
+		requestContext.JSON(http.StatusCreated, IssuedAPIKeyJSON{
+			APIKeyJSON: MapAPIKeyReadModelToJSON(issued.APIKeyReadModel),
+			Key:        issued.Key,
+		})
+	}
+}
+
+// ListAPIKeys แสดงคีย์ทั้งหมด (ไม่มี secret)
+func ListAPIKeys(apiKeyUseCase usecase.APIKeyUseCase) gin.HandlerFunc {
+	return func(requestContext *gin.Context) {
+		readModels, listError := apiKeyUseCase.List(requestContext)
+		if errors.Is(listError, domain.ErrForbidden) {
+			requestContext.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		if listError != nil {
+			requestContext.JSON(http.StatusInternalServerError, gin.H{"error": "cannot list api keys"})
+			return
+		}
+		requestContext.JSON(http.StatusOK, MapAPIKeyReadModelsToJSON(readModels))
+	}
+}
+
+// RevokeAPIKey เพิกถอนคีย์ (มีผลทันที)
+func RevokeAPIKey(apiKeyUseCase usecase.APIKeyUseCase) gin.HandlerFunc {
+	return func(requestContext *gin.Context) {
+		idNumber, convertError := strconv.Atoi(requestContext.Param("id"))
+		if convertError != nil {
+			requestContext.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		revokeError := apiKeyUseCase.Revoke(requestContext, uint(idNumber))
+		if errors.Is(revokeError, domain.ErrForbidden) {
+			requestContext.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		if errors.Is(revokeError, domain.ErrNotFound) {
+			requestContext.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		if revokeError != nil {
+			requestContext.JSON(http.StatusInternalServerError, gin.H{"error": "revoke failed"})
+			return
+		}
+		requestContext.Status(http.StatusNoContent)
+	}
+}
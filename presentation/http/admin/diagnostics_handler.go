@@ -0,0 +1,75 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/health"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/usecase"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+// GetDatabaseStats แสดงสถิติ connection pool (open / in_use / idle / wait ...)
+func GetDatabaseStats(diagnosticsUseCase usecase.DiagnosticsUseCase) gin.HandlerFunc {
+	return func(requestContext *gin.Context) {
+		stats, statsError := diagnosticsUseCase.DatabaseStats(requestContext)
+		if statsError != nil {
+			respondDiagnosticsError(requestContext, statsError, "database stats unavailable")
+			return
+		}
+		requestContext.JSON(http.StatusOK, gin.H{
+			"max_open_connections": stats.MaxOpenConnections,
+			"open_connections":     stats.OpenConnections,
+			"in_use":               stats.InUse,
+			"idle":                 stats.Idle,
+			"wait_count":           stats.WaitCount,
+			"wait_duration_ms":     stats.WaitDuration.Milliseconds(),
+			"max_idle_closed":      stats.MaxIdleClosed,
+			"max_idle_time_closed": stats.MaxIdleTimeClosed,
+			"max_lifetime_closed":  stats.MaxLifetimeClosed,
+		})
+	}
+}
+
+// GetCacheStats แสดงตัวนับ hit/miss ของแคช
+func GetCacheStats(diagnosticsUseCase usecase.DiagnosticsUseCase) gin.HandlerFunc {
+	return func(requestContext *gin.Context) {
+		stats, statsError := diagnosticsUseCase.CacheStats(requestContext)
+		if statsError != nil {
+			respondDiagnosticsError(requestContext, statsError, "cache stats unavailable")
+			return
+		}
+		requestContext.JSON(http.StatusOK, stats)
+	}
+}
+
+// GetHealthDetails แสดงผลทุก health check พร้อมเวลาตอบและข้อความ error (application/health+json)
+func GetHealthDetails(diagnosticsUseCase usecase.DiagnosticsUseCase) gin.HandlerFunc {
+	return func(requestContext *gin.Context) {
+		report, reportError := diagnosticsUseCase.HealthDetails(requestContext)
+		if reportError != nil {
+			respondDiagnosticsError(requestContext, reportError, "health details unavailable")
+			return
+		}
+		status := http.StatusOK
+		if report.Status == health.StatusFail {
+			status = http.StatusServiceUnavailable
+		}
+		body, _ := json.Marshal(report)
+		requestContext.Header("Cache-Control", "no-store")
+		requestContext.Data(status, "application/health+json", body)
+	}
+}
+
+func respondDiagnosticsError(requestContext *gin.Context, statsError error, unavailable string) {
+	switch {
+	case errors.Is(statsError, domain.ErrForbidden):
+		requestContext.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	case errors.Is(statsError, domain.ErrNotFound):
+		requestContext.JSON(http.StatusNotFound, gin.H{"error": "not enabled"})
+	default:
+		requestContext.JSON(http.StatusServiceUnavailable, gin.H{"error": unavailable})
+	}
+}
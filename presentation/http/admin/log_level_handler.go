@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/usecase"
+	"github.com/nuba55yo/go-101-CleanCRUD/domain"
+)
+
+// GetLogLevel แสดงระดับ log ปัจจุบัน
+func GetLogLevel(logLevelUseCase usecase.LogLevelUseCase) gin.HandlerFunc {
+	return func(requestContext *gin.Context) {
+		level, levelError := logLevelUseCase.Level(requestContext)
+		if errors.Is(levelError, domain.ErrForbidden) {
+			requestContext.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		if levelError != nil {
+			requestContext.JSON(http.StatusInternalServerError, gin.H{"error": "cannot read log level"})
+			return
+		}
+		requestContext.JSON(http.StatusOK, LogLevelJSON{Level: level})
+	}
+}
+
+// SetLogLevel เปลี่ยนระดับ log ทันที (ไม่ถาวร รีสตาร์ตแล้วกลับไปใช้ LOG_LEVEL)
+func SetLogLevel(logLevelUseCase usecase.LogLevelUseCase) gin.HandlerFunc {
+	return func(requestContext *gin.Context) {
+		var requestBody LogLevelJSON
+		if bindError := requestContext.ShouldBindJSON(&requestBody); bindError != nil {
+			requestContext.JSON(http.StatusBadRequest, gin.H{"error": bindError.Error()})
+			return
+		}
+		level, setError := logLevelUseCase.SetLevel(requestContext, requestBody.Level)
+		if setError != nil {
+			if errors.Is(setError, domain.ErrForbidden) {
+				requestContext.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+				return
+			}
+			if errors.Is(setError, domain.ErrBadInput) {
+				requestContext.JSON(http.StatusBadRequest, gin.H{"error": "level must be debug, info, warn or error"})
+				return
+			}
+			requestContext.JSON(http.StatusInternalServerError, gin.H{"error": "cannot change log level"})
+			return
+		}
+		requestContext.JSON(http.StatusOK, LogLevelJSON{Level: level})
+	}
+}
@@ -0,0 +1,29 @@
+package admin
+
+import "github.com/nuba55yo/go-101-CleanCRUD/application/dto"
+
+func MapIssueJSONToCommand(requestBody IssueAPIKeyJSON) dto.IssueAPIKeyCommand {
+	return dto.IssueAPIKeyCommand{Name: requestBody.Name, Scope: requestBody.Scope, ExpiresIn: requestBody.ExpiresIn}
+}
+
+func MapAPIKeyReadModelToJSON(readModel dto.APIKeyReadModel) APIKeyJSON {
+	return APIKeyJSON{
+		ID:         readModel.ID,
+		Name:       readModel.Name,
+		Prefix:     readModel.Prefix,
+		Scope:      readModel.Scope,
+		CreatedBy:  readModel.CreatedBy,
+		CreatedAt:  readModel.CreatedAt,
+		ExpiresAt:  readModel.ExpiresAt,
+		LastUsedAt: readModel.LastUsedAt,
+		RevokedAt:  readModel.RevokedAt,
+	}
+}
+
+func MapAPIKeyReadModelsToJSON(readModels []dto.APIKeyReadModel) []APIKeyJSON {
+	result := make([]APIKeyJSON, 0, len(readModels))
+	for _, m := range readModels {
+		result = append(result, MapAPIKeyReadModelToJSON(m))
+	}
+	return result
+}
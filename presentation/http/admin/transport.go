@@ -0,0 +1,30 @@
+package admin
+
+// โครง JSON ของ admin API
+type IssueAPIKeyJSON struct {
+	Name      string `json:"name"       example:"nightly-import"`
+	Scope     string `json:"scope"      example:"read-only" enums:"read-only,read-write,admin"`
+	ExpiresIn string `json:"expires_in" example:"720h"` // ว่าง = ไม่หมดอายุ
+}
+
+type APIKeyJSON struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	Prefix     string `json:"prefix"`
+	Scope      string `json:"scope"`
+	CreatedBy  string `json:"created_by,omitempty"`
+	CreatedAt  string `json:"created_at"`
+	ExpiresAt  string `json:"expires_at,omitempty"`
+	LastUsedAt string `json:"last_used_at,omitempty"`
+	RevokedAt  string `json:"revoked_at,omitempty"`
+}
+
+// IssuedAPIKeyJSON มี key เต็มซึ่งแสดงครั้งเดียวตอนออกคีย์
+type IssuedAPIKeyJSON struct {
+	APIKeyJSON
+	Key string `json:"key"`
+}
+
+type LogLevelJSON struct {
+	Level string `json:"level" example:"debug" enums:"debug,info,warn,error"`
+}
@@ -0,0 +1,44 @@
+package httpx
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/health"
+)
+
+// healthContentType ตาม draft-inadarei-api-health-check
+const healthContentType = "application/health+json"
+
+func writeHealthReport(c *gin.Context, report health.Report) {
+	status := http.StatusOK
+	if report.Status == health.StatusFail {
+		status = http.StatusServiceUnavailable
+	}
+	body, _ := json.Marshal(report)
+	c.Header("Cache-Control", "no-store")
+	c.Data(status, healthContentType, body)
+}
+
+// livenessHandler = GET /healthz ตอบได้แปลว่าโปรเซสยังไม่ค้าง (ไม่แตะ dependency ใด ๆ)
+func livenessHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writeHealthReport(c, health.Report{Status: health.StatusPass})
+	}
+}
+
+// readinessHandler = GET /readyz พร้อมรับ request เมื่อยังไม่เริ่ม shutdown และ check ที่ Critical ผ่านหมด
+func readinessHandler(ready func() bool, registry *health.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ready != nil && !ready() {
+			writeHealthReport(c, health.Report{Status: health.StatusFail, Output: "shutting down"})
+			return
+		}
+		if registry == nil {
+			writeHealthReport(c, health.Report{Status: health.StatusPass})
+			return
+		}
+		writeHealthReport(c, registry.Readiness(c.Request.Context()))
+	}
+}
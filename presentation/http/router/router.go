@@ -2,24 +2,167 @@ package httpx
 
 import (
 	"net/http"
+	"slices"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/health"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
 	"github.com/nuba55yo/go-101-CleanCRUD/application/usecase"
+	"github.com/nuba55yo/go-101-CleanCRUD/presentation/http/admin"
 	v1 "github.com/nuba55yo/go-101-CleanCRUD/presentation/http/v1"
 	v2 "github.com/nuba55yo/go-101-CleanCRUD/presentation/http/v2"
 	"github.com/nuba55yo/go-101-CleanCRUD/presentation/middleware"
 
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func NewRouter(bookUseCase usecase.BookUseCase) *gin.Engine {
+// routerOptions = ส่วนเสริมของ router ที่ main ประกอบเข้ามา (ไม่ใส่ก็ใช้งาน API ได้ตามปกติ)
+type routerOptions struct {
+	diagnostics   usecase.DiagnosticsUseCase
+	authSchemes   []middleware.AuthScheme
+	apiKeyUseCase usecase.APIKeyUseCase
+	logLevels     usecase.LogLevelUseCase
+	tenancy       *middleware.TenantConfig
+	rateLimit     *middleware.RateLimitConfig
+	idempotency   *middleware.IdempotencyConfig
+	ready         func() bool
+	health        *health.Registry
+	metrics       middleware.RequestObserver
+	metricsPage   http.Handler
+	tracer        trace.TracerProvider
+	propagator    propagation.TextMapPropagator
+	accessLog     middleware.AccessLogConfig
+}
+
+// Option ปรับแต่ง router ตอนสร้าง
+type Option func(options *routerOptions)
+
+// WithDiagnostics เปิด GET /admin/debug/db/stats, /admin/debug/cache/stats และ /admin/health/details
+// (use case ตรวจ permission diagnostics:read)
+func WithDiagnostics(diagnosticsUseCase usecase.DiagnosticsUseCase) Option {
+	return func(options *routerOptions) { options.diagnostics = diagnosticsUseCase }
+}
+
+// WithBearerAuth บังคับ Authorization: Bearer <JWT> บนทุกเส้นทาง /api/v1 และ /api/v2
+func WithBearerAuth(verifier interfaces.CredentialVerifier) Option {
+	return func(options *routerOptions) {
+		options.authSchemes = append(options.authSchemes, middleware.AuthScheme{Name: "Bearer", Verifier: verifier})
+	}
+}
+
+// WithAPIKeys รับ API key ผ่าน Authorization: ApiKey <key> หรือ X-API-Key บน /api/v1 และ /api/v2
+// และเปิด /admin/api-keys (ออก/ดู/เพิกถอนคีย์; use case ตรวจ permission apikeys:manage)
+func WithAPIKeys(apiKeyUseCase usecase.APIKeyUseCase) Option {
+	return func(options *routerOptions) {
+		options.apiKeyUseCase = apiKeyUseCase
+		options.authSchemes = append(options.authSchemes,
+			middleware.AuthScheme{Name: "ApiKey", Header: "X-API-Key", Verifier: apiKeyUseCase})
+	}
+}
+
+// WithLogLevelControl เปิด GET/PUT /admin/log-level ดู/ปรับระดับ log ขณะรัน (use case ตรวจ permission logs:manage)
+func WithLogLevelControl(logLevelUseCase usecase.LogLevelUseCase) Option {
+	return func(options *routerOptions) { options.logLevels = logLevelUseCase }
+}
+
+// WithTenancy หา tenant ของแต่ละ request (claim/header/subdomain) ให้ persistence แยกข้อมูลตาม tenant
+// ไม่ใส่ = ทุก request อยู่ใน requestmeta.DefaultTenant
+func WithTenancy(config middleware.TenantConfig) Option {
+	return func(options *routerOptions) { options.tenancy = &config }
+}
+
+// WithRateLimit จำกัดอัตรา request ของ /api/v1, /api/v2 และ /admin ต่อผู้เรียก
+// (เปิด auth: จำกัดต่อ IP ด้วย config.PreAuth ก่อนยืนยันตัวตนอีกชั้น)
+func WithRateLimit(config middleware.RateLimitConfig) Option {
+	return func(options *routerOptions) { options.rateLimit = &config }
+}
+
+// WithIdempotency รองรับ Idempotency-Key บน /api/v1 และ /api/v2 (retry ได้คำตอบเดิม)
+func WithIdempotency(config middleware.IdempotencyConfig) Option {
+	return func(options *routerOptions) { options.idempotency = &config }
+}
+
+// WithReadiness เปิด GET /readyz ตอบ 503 เมื่อ ready() เป็น false (เช่นระหว่าง graceful shutdown)
+// ให้ load balancer เลิกส่ง request ใหม่มาก่อน server หยุดรับจริง
+func WithReadiness(ready func() bool) Option {
+	return func(options *routerOptions) { options.ready = ready }
+}
+
+// WithHealth ให้ /readyz รัน check ที่เป็น Critical (ผลละเอียดทุก check อยู่ที่ /admin/health/details ผ่าน WithDiagnostics)
+func WithHealth(registry *health.Registry) Option {
+	return func(options *routerOptions) { options.health = registry }
+}
+
+// WithMetrics เก็บสถิติทุก request ส่งให้ observer และเปิด GET /metrics ด้วย handler
+// handler = nil เมื่อเปิด /metrics แยกพอร์ต (ไม่ให้ผู้ใช้ API เห็น)
+func WithMetrics(observer middleware.RequestObserver, handler http.Handler) Option {
+	return func(options *routerOptions) {
+		options.metrics = observer
+		options.metricsPage = handler
+	}
+}
+
+// WithTracing เปิด span ของ OpenTelemetry ให้ทุก request และรับ/ส่งต่อ traceparent ด้วย propagator
+func WithTracing(provider trace.TracerProvider, propagator propagation.TextMapPropagator) Option {
+	return func(options *routerOptions) {
+		options.tracer = provider
+		options.propagator = propagator
+	}
+}
+
+// WithAccessLog ตั้งกฎปิดบังข้อมูลของ access log (ไม่ใส่ = DefaultRedactionConfig)
+func WithAccessLog(config middleware.AccessLogConfig) Option {
+	return func(options *routerOptions) { options.accessLog = config }
+}
+
+func NewRouter(bookUseCase usecase.BookUseCase, options ...Option) *gin.Engine {
+	var configured routerOptions
+	for _, option := range options {
+		option(&configured)
+	}
+
 	r := gin.New()
 	_ = r.SetTrustedProxies(nil)
-	r.Use(gin.Recovery(), middleware.AccessLog())
+	// ให้ c.Value() มองทะลุไปถึง c.Request.Context() (middleware ใส่ข้อมูลประจำ request ไว้ที่นั่น)
+	r.ContextWithFallback = true
+	// request id มาก่อนทุกอย่าง ให้ทุก log ของ request มี id เดียวกัน
+	r.Use(middleware.RequestID())
+	// tracing ครอบ middleware ที่เหลือทั้งหมด ให้ access log/metrics เห็น trace id
+	if configured.tracer != nil {
+		r.Use(middleware.Tracing(configured.tracer, configured.propagator))
+	}
+	if configured.metrics != nil {
+		r.Use(middleware.Metrics(configured.metrics))
+	}
+	r.Use(gin.Recovery(), middleware.AccessLog(configured.accessLog), middleware.ClientKey())
+
+	// middleware เฉพาะกลุ่ม API (docs/swagger/monitoring ไม่ต้องยืนยันตัวตน)
+	var apiMiddlewares []gin.HandlerFunc
+	if len(configured.authSchemes) > 0 {
+		// นับต่อ IP ก่อนยืนยันตัวตน ไม่งั้น request ที่ credential ผิด (401) ไม่ถูกจำกัดเลย
+		if configured.rateLimit != nil {
+			apiMiddlewares = append(apiMiddlewares, middleware.PreAuthRateLimit(*configured.rateLimit))
+		}
+		apiMiddlewares = append(apiMiddlewares, middleware.Authenticate(configured.authSchemes...))
+	}
+	if configured.tenancy != nil {
+		apiMiddlewares = append(apiMiddlewares, middleware.ResolveTenant(*configured.tenancy))
+	}
+	if configured.rateLimit != nil {
+		apiMiddlewares = append(apiMiddlewares, middleware.RateLimit(*configured.rateLimit))
+	}
+
+	// idempotency เฉพาะ API หนังสือ (การออก API key ไม่ควรได้ key เดิมกลับไปซ้ำ)
+	bookMiddlewares := apiMiddlewares
+	if configured.idempotency != nil {
+		bookMiddlewares = append(slices.Clone(apiMiddlewares), middleware.Idempotency(*configured.idempotency))
+	}
 
 	// -------- v1 --------
-	apiV1 := r.Group("/api/v1")
+	apiV1 := r.Group("/api/v1", bookMiddlewares...)
 	{
 		apiV1.GET("/books", v1.ListBooks(bookUseCase))
 		apiV1.GET("/books/:id", v1.GetBookByID(bookUseCase))
@@ -29,7 +172,7 @@ func NewRouter(bookUseCase usecase.BookUseCase) *gin.Engine {
 	}
 
 	// -------- v2 --------
-	apiV2 := r.Group("/api/v2")
+	apiV2 := r.Group("/api/v2", bookMiddlewares...)
 	{
 		apiV2.GET("/books", v2.ListBooks(bookUseCase))
 		apiV2.GET("/books/:id", v2.GetBookByID(bookUseCase))
@@ -38,6 +181,23 @@ func NewRouter(bookUseCase usecase.BookUseCase) *gin.Engine {
 		apiV2.DELETE("/books/:id", v2.DeleteBook(bookUseCase))
 	}
 
+	// -------- admin --------
+	adminGroup := r.Group("/admin", apiMiddlewares...)
+	if configured.apiKeyUseCase != nil {
+		adminGroup.POST("/api-keys", admin.IssueAPIKey(configured.apiKeyUseCase))
+		adminGroup.GET("/api-keys", admin.ListAPIKeys(configured.apiKeyUseCase))
+		adminGroup.DELETE("/api-keys/:id", admin.RevokeAPIKey(configured.apiKeyUseCase))
+	}
+	if configured.logLevels != nil {
+		adminGroup.GET("/log-level", admin.GetLogLevel(configured.logLevels))
+		adminGroup.PUT("/log-level", admin.SetLogLevel(configured.logLevels))
+	}
+	if configured.diagnostics != nil {
+		adminGroup.GET("/debug/db/stats", admin.GetDatabaseStats(configured.diagnostics))
+		adminGroup.GET("/debug/cache/stats", admin.GetCacheStats(configured.diagnostics))
+		adminGroup.GET("/health/details", admin.GetHealthDetails(configured.diagnostics))
+	}
+
 	// -------- docs (???? gen ????) --------
 	r.GET("/docs/v1/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.InstanceName("v1")))
 	r.GET("/docs/v2/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.InstanceName("v2")))
@@ -70,5 +230,12 @@ func NewRouter(bookUseCase usecase.BookUseCase) *gin.Engine {
 		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(html))
 	})
 
+	// -------- monitoring --------
+	r.GET("/healthz", livenessHandler())
+	r.GET("/readyz", readinessHandler(configured.ready, configured.health))
+	if configured.metricsPage != nil {
+		r.GET("/metrics", gin.WrapH(configured.metricsPage))
+	}
+
 	return r
 }
@@ -16,6 +16,7 @@ import (
 // @Produce json
 // @Param body body CreateBookJSON true "payload"
 // @Success 201 {object} BookJSON
+// @Failure 403 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 409 {object} map[string]string
 // @Router /books [post]
@@ -29,6 +30,8 @@ func CreateBook(bookUseCase usecase.BookUseCase) gin.HandlerFunc {
 		readModel, createError := bookUseCase.Create(requestContext, MapCreateJSONToCommand(requestBody))
 		if createError != nil {
 			switch {
+			case errors.Is(createError, domain.ErrForbidden):
+				requestContext.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 			case errors.Is(createError, domain.ErrTitleExists):
 				requestContext.JSON(http.StatusConflict, gin.H{"error": "title already exists"})
 			case errors.Is(createError, domain.ErrBadInput):
@@ -46,10 +49,15 @@ func CreateBook(bookUseCase usecase.BookUseCase) gin.HandlerFunc {
 // @Tags books
 // @Produce json
 // @Success 200 {array} BookJSON
+// @Failure 403 {object} map[string]string
 // @Router /books [get]
 func ListBooks(bookUseCase usecase.BookUseCase) gin.HandlerFunc {
 	return func(requestContext *gin.Context) {
 		readModels, listError := bookUseCase.List(requestContext)
+		if errors.Is(listError, domain.ErrForbidden) {
+			requestContext.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
 		if listError != nil {
 			requestContext.JSON(http.StatusInternalServerError, gin.H{"error": "cannot list books"})
 			return
@@ -63,6 +71,7 @@ func ListBooks(bookUseCase usecase.BookUseCase) gin.HandlerFunc {
 // @Produce json
 // @Param id path int true "book id"
 // @Success 200 {object} BookJSON
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /books/{id} [get]
 func GetBookByID(bookUseCase usecase.BookUseCase) gin.HandlerFunc {
@@ -74,6 +83,10 @@ func GetBookByID(bookUseCase usecase.BookUseCase) gin.HandlerFunc {
 			return
 		}
 		readModel, getError := bookUseCase.Get(requestContext, uint(idNumber))
+		if errors.Is(getError, domain.ErrForbidden) {
+			requestContext.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
 		if errors.Is(getError, domain.ErrNotFound) {
 			requestContext.JSON(http.StatusNotFound, gin.H{"error": "not found"})
 			return
@@ -93,6 +106,7 @@ func GetBookByID(bookUseCase usecase.BookUseCase) gin.HandlerFunc {
 // @Param id path int true "book id"
 // @Param body body UpdateBookJSON true "payload"
 // @Success 200 {object} BookJSON
+// @Failure 403 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 409 {object} map[string]string
@@ -113,6 +127,8 @@ func UpdateBook(bookUseCase usecase.BookUseCase) gin.HandlerFunc {
 		readModel, updateError := bookUseCase.Update(requestContext, MapUpdateJSONToCommand(uint(idNumber), requestBody))
 		if updateError != nil {
 			switch {
+			case errors.Is(updateError, domain.ErrForbidden):
+				requestContext.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 			case errors.Is(updateError, domain.ErrTitleExists):
 				requestContext.JSON(http.StatusConflict, gin.H{"error": "title already exists"})
 			case errors.Is(updateError, domain.ErrBadInput):
@@ -132,6 +148,7 @@ func UpdateBook(bookUseCase usecase.BookUseCase) gin.HandlerFunc {
 // @Tags books
 // @Param id path int true "book id"
 // @Success 204
+// @Failure 403 {object} map[string]string
 // @Router /books/{id} [delete]
 func DeleteBook(bookUseCase usecase.BookUseCase) gin.HandlerFunc {
 	return func(requestContext *gin.Context) {
@@ -141,7 +158,12 @@ func DeleteBook(bookUseCase usecase.BookUseCase) gin.HandlerFunc {
 			requestContext.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
 			return
 		}
-		if deleteError := bookUseCase.Delete(requestContext, uint(idNumber)); deleteError != nil {
+		deleteError := bookUseCase.Delete(requestContext, uint(idNumber))
+		if errors.Is(deleteError, domain.ErrForbidden) {
+			requestContext.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		if deleteError != nil {
 			requestContext.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
 			return
 		}
@@ -1,88 +1,41 @@
 package middleware
 
 import (
-	"bytes"
-	"fmt"
-	"io"
-	"net/http"
-	"os"
-	"path/filepath"
+	"math/rand/v2"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
-)
+	"go.opentelemetry.io/otel/trace"
 
-var (
-	logMutex      sync.Mutex
-	currentBucket string
-	currentFile   *os.File
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
 )
 
-type responseRecorder struct {
-	gin.ResponseWriter
-	body bytes.Buffer
-}
-
-func (w *responseRecorder) Write(b []byte) (int, error) {
-	w.body.Write(b)
-	return w.ResponseWriter.Write(b)
-}
-func (w *responseRecorder) WriteString(s string) (int, error) {
-	w.body.WriteString(s)
-	return w.ResponseWriter.WriteString(s)
-}
-
-func timeBucket(t time.Time) string {
-	b := (t.Minute() / 10) * 10 // 10 นาที/ไฟล์
-	return fmt.Sprintf("%04d-%02d-%02d_%02d-%02d", t.Year(), t.Month(), t.Day(), t.Hour(), b)
-}
-
-func ensureLogFile(now time.Time) (*os.File, error) {
-	logMutex.Lock()
-	defer logMutex.Unlock()
-
-	bucket := timeBucket(now)
-	if currentFile != nil && bucket == currentBucket {
-		return currentFile, nil
-	}
-	if currentFile != nil {
-		_ = currentFile.Close()
-	}
-
-	dateFolder := now.Format("2006-01-02")
-	dir := filepath.Join("logs", dateFolder)
-	_ = os.MkdirAll(dir, 0o755)
-
-	name := fmt.Sprintf("log_%s.log", bucket)
-	full := filepath.Join(dir, name)
+var (
+	// sampleRandom สุ่ม 0..1 สำหรับ SuccessSampleRate (เทสแทนที่ได้)
+	sampleRandom = rand.Float64
 
-	f, err := os.OpenFile(full, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
-	if err != nil {
-		return nil, err
-	}
-	currentBucket = bucket
-	currentFile = f
-	return f, nil
-}
+	errorHandlerMutex sync.Mutex
+	// writeErrorHandler ถูกเรียกเมื่อเขียน access log ไม่สำเร็จ (ตั้งผ่าน SetAccessLogErrorHandler)
+	writeErrorHandler func(error)
+)
 
-func writeLine(now time.Time, module, level, message string) {
-	f, err := ensureLogFile(now)
-	if err != nil {
-		return
-	}
-	line := fmt.Sprintf("%s [%s] [%s] %s\n", now.Format("2006-01-02 15:04:05.000"), module, level, message)
-	_, _ = f.WriteString(line)
+// SetAccessLogErrorHandler ตั้งตัวรับ error ตอนเขียน access log (เช่น นับเป็น metrics) ต้องเรียกก่อนเริ่ม server
+func SetAccessLogErrorHandler(handler func(error)) {
+	errorHandlerMutex.Lock()
+	defer errorHandlerMutex.Unlock()
+	writeErrorHandler = handler
 }
 
-func readRequestBodySafely(r *http.Request, limit int64) string {
-	if r.Body == nil {
-		return ""
+func reportWriteError(err error) {
+	errorHandlerMutex.Lock()
+	handler := writeErrorHandler
+	errorHandlerMutex.Unlock()
+	if handler != nil {
+		handler(err)
 	}
-	body, _ := io.ReadAll(io.LimitReader(r.Body, limit))
-	r.Body = io.NopCloser(bytes.NewBuffer(body))
-	return string(body)
 }
 
 func moduleFromRoute(fullPath string) string {
@@ -112,32 +65,91 @@ func levelFromStatus(status int) string {
 	}
 }
 
-// AccessLog: บันทึก request/response + หมุนไฟล์ทุก 10 นาที
-func AccessLog() gin.HandlerFunc {
+// AccessLogConfig = ค่าของ access log
+type AccessLogConfig struct {
+	// Redactor ปิดบัง header/body ก่อนเขียน (nil = ใช้ DefaultRedactionConfig)
+	Redactor *Redactor
+	// Format = รูปแบบบรรทัด (ว่าง = AccessLogFormatText)
+	Format AccessLogFormat
+	// Fields = ฟิลด์ที่เขียนในรูปแบบ JSON (ว่าง = ทุกฟิลด์) ดู ParseAccessLogFields
+	Fields []string
+	// Writer = ปลายทางของบรรทัด (เช่น accesslog.RotatingFile) nil = ไม่บันทึก access log
+	Writer interfaces.AccessLogWriter
+
+	// MaxRequestBodyBytes / MaxResponseBodyBytes = เก็บ body ลง log ไม่เกินเท่านี้ ส่วนเกินตัดพร้อม marker (0 = ไม่เก็บ)
+	MaxRequestBodyBytes  int
+	MaxResponseBodyBytes int
+	// BodyContentTypes = ชนิดของ body ที่เก็บ เช่น application/json, text/* (ว่าง = ทุกชนิด)
+	BodyContentTypes []string
+	// SkipPaths = path ที่ไม่บันทึกเลย เช่น /metrics, /docs/* (ตัวเองและทุก path ใต้นั้น)
+	SkipPaths []string
+	// SuccessSampleRate = สัดส่วนของ request ที่สำเร็จ (< 400) ที่บันทึก 0..1; 4xx/5xx บันทึกเสมอ
+	SuccessSampleRate float64
+}
+
+// AccessLog: บันทึก request/response (ผ่าน Redactor แล้ว) ลง config.Writer
+func AccessLog(config AccessLogConfig) gin.HandlerFunc {
+	if config.Writer == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	redactor := config.Redactor
+	if redactor == nil {
+		redactor, _ = NewRedactor(DefaultRedactionConfig()) // กฎเริ่มต้นถูกต้องเสมอ
+	}
+	format := formatTextLine
+	if config.Format == AccessLogFormatJSON {
+		fields := selectedFields(config.Fields)
+		format = func(entry accessLogEntry) string { return formatJSONLine(entry, fields) }
+	}
 	return func(c *gin.Context) {
+		if skipPath(config.SkipPaths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
 		start := time.Now()
-		reqBody := readRequestBodySafely(c.Request, 1<<20) // 1MB
+		reqBody := captureRequestBody(c.Request, config.MaxRequestBodyBytes, config.BodyContentTypes)
 
-		rec := &responseRecorder{ResponseWriter: c.Writer}
+		rec := &responseRecorder{
+			ResponseWriter: c.Writer, limit: config.MaxResponseBodyBytes, allowedTypes: config.BodyContentTypes,
+		}
 		c.Writer = rec
 
 		c.Next()
 
-		latency := time.Since(start)
 		status := rec.Status()
+		if status < 400 && config.SuccessSampleRate < 1 && sampleRandom() >= config.SuccessSampleRate {
+			return
+		}
+		method := c.Request.Method
 		route := c.FullPath()
-		module := moduleFromRoute(route)
-
-		msg := fmt.Sprintf(
-			"status=%d method=%s route=%s ip=%s latency=%s req=%s res=%s",
-			status,
-			c.Request.Method,
-			route,
-			c.ClientIP(),
-			latency.String(),
-			strings.ReplaceAll(reqBody, "\n", " "),
-			strings.ReplaceAll(rec.body.String(), "\n", " "),
-		)
-		writeLine(time.Now(), module, levelFromStatus(status), msg)
+		entry := accessLogEntry{
+			Time:         time.Now(),
+			Module:       moduleFromRoute(route),
+			Level:        levelFromStatus(status),
+			RequestID:    requestmeta.RequestID(c.Request.Context()),
+			Status:       status,
+			Method:       method,
+			Route:        route,
+			Path:         c.Request.URL.Path,
+			Query:        redactor.Text(c.Request.URL.RawQuery),
+			IP:           c.ClientIP(),
+			UserAgent:    c.Request.UserAgent(),
+			Latency:      time.Since(start),
+			BytesIn:      c.Request.ContentLength,
+			BytesOut:     rec.total,
+			Headers:      redactor.Headers(c.Request.Header),
+			RequestBody:  redactor.Body(method, route, reqBody),
+			ResponseBody: redactor.Body(method, route, rec.capturedBody()),
+		}
+		if entry.BytesIn < 0 { // chunked: ใช้ขนาดที่อ่านได้จริง
+			entry.BytesIn = int64(len(reqBody))
+		}
+		if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.IsValid() {
+			entry.TraceID = spanContext.TraceID().String()
+			entry.SpanID = spanContext.SpanID().String()
+		}
+		if err := config.Writer.WriteLine(entry.Time, format(entry)); err != nil {
+			reportWriteError(err)
+		}
 	}
 }
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultAccessLogConfig = ค่าเริ่มต้นของการเก็บ body, path ที่ข้าม และการสุ่ม (ยังไม่มี Writer)
+func DefaultAccessLogConfig() AccessLogConfig {
+	return AccessLogConfig{
+		MaxRequestBodyBytes:  64 << 10,
+		MaxResponseBodyBytes: 64 << 10,
+		BodyContentTypes: []string{
+			"application/json", "application/*+json", "application/x-www-form-urlencoded", "application/xml", "text/*",
+		},
+		SkipPaths:         []string{"/swagger", "/docs/*", "/metrics", "/healthz", "/readyz"},
+		SuccessSampleRate: 1,
+	}
+}
+
+// truncatedMarker ต่อท้าย body ที่ถูกตัด บอกขนาดจริงไว้ด้วย (total < 0 = ไม่รู้ขนาด เช่น chunked)
+func truncatedMarker(total int64) string {
+	if total < 0 {
+		return "...[truncated]"
+	}
+	return fmt.Sprintf("...[truncated, %d bytes total]", total)
+}
+
+// notCapturedMarker แทน body ที่ชนิดไม่อยู่ใน allow-list หรือปิดการเก็บ body
+func notCapturedMarker(contentType string, total int64) string {
+	if total < 0 {
+		return fmt.Sprintf("[not captured: %s]", contentType)
+	}
+	return fmt.Sprintf("[not captured: %s, %d bytes]", contentType, total)
+}
+
+// contentTypeAllowed = ชนิดของ body อยู่ใน allow-list (รองรับ text/* และ application/*+json)
+// allow-list ว่าง = ทุกชนิด; ไม่มี Content-Type = ถือว่าเก็บได้ (เช่น body ว่าง)
+func contentTypeAllowed(allowed []string, contentType string) bool {
+	if len(allowed) == 0 || contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range allowed {
+		if matched, _ := path.Match(strings.ToLower(pattern), mediaType); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// skipPath: "/docs/*" = ตัว /docs และทุก path ใต้นั้น, นอกนั้นใช้ path.Match (เช่น "/metrics", "/api/*/export")
+func skipPath(patterns []string, requestPath string) bool {
+	for _, pattern := range patterns {
+		if base, subtree := strings.CutSuffix(pattern, "/*"); subtree {
+			if requestPath == base || strings.HasPrefix(requestPath, base+"/") {
+				return true
+			}
+			continue
+		}
+		if matched, _ := path.Match(pattern, requestPath); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// captureRequestBody อ่าน body ไม่เกิน limit ไบต์ไว้ลง log แล้วต่อส่วนที่อ่านไปกลับเข้า body ให้ handler อ่านได้ครบ
+func captureRequestBody(request *http.Request, limit int, allowedTypes []string) string {
+	if request.Body == nil || request.Body == http.NoBody {
+		return ""
+	}
+	contentType := request.Header.Get("Content-Type")
+	if limit <= 0 || !contentTypeAllowed(allowedTypes, contentType) {
+		return notCapturedMarker(contentType, request.ContentLength)
+	}
+	captured, _ := io.ReadAll(io.LimitReader(request.Body, int64(limit)+1))
+	request.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(captured), request.Body), Closer: request.Body}
+	if len(captured) > limit {
+		return string(captured[:limit]) + truncatedMarker(request.ContentLength)
+	}
+	return string(captured)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// responseRecorder ส่ง response ต่อตามปกติ แต่เก็บสำเนาไว้ไม่เกิน limit ไบต์ และเฉพาะชนิดที่อยู่ใน allow-list
+type responseRecorder struct {
+	gin.ResponseWriter
+	body         bytes.Buffer
+	limit        int
+	allowedTypes []string
+	decided      bool
+	capturing    bool
+	total        int64
+}
+
+// startCapture ตัดสินใจครั้งแรกที่มีการเขียน (ตอนนั้น handler ตั้ง Content-Type แล้ว) คืนจำนวนไบต์ที่ยังเก็บได้
+func (w *responseRecorder) startCapture(size int) int {
+	if !w.decided {
+		w.decided = true
+		w.capturing = w.limit > 0 && contentTypeAllowed(w.allowedTypes, w.Header().Get("Content-Type"))
+	}
+	w.total += int64(size)
+	if !w.capturing {
+		return 0
+	}
+	return min(size, max(w.limit-w.body.Len(), 0))
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b[:w.startCapture(len(b))])
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s[:w.startCapture(len(s))])
+	return w.ResponseWriter.WriteString(s)
+}
+
+// capturedBody = body ที่เก็บได้ พร้อม marker เมื่อถูกตัดหรือไม่ได้เก็บ
+func (w *responseRecorder) capturedBody() string {
+	switch {
+	case w.total == 0:
+		return ""
+	case !w.capturing:
+		return notCapturedMarker(w.Header().Get("Content-Type"), w.total)
+	case w.total > int64(w.body.Len()):
+		return w.body.String() + truncatedMarker(w.total)
+	default:
+		return w.body.String()
+	}
+}
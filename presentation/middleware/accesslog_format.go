@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AccessLogFormat = รูปแบบบรรทัดของ access log
+type AccessLogFormat string
+
+const (
+	// AccessLogFormatText = `เวลา [module] [level] key=value ...` (รูปแบบเดิม อ่านด้วยตาง่าย)
+	AccessLogFormatText AccessLogFormat = "text"
+	// AccessLogFormatJSON = JSON Lines หนึ่ง object ต่อบรรทัด ฟิลด์มีชนิดชัดเจน (ให้ log shipper อ่าน)
+	AccessLogFormatJSON AccessLogFormat = "json"
+)
+
+// ParseAccessLogFormat อ่าน "text" / "json" (ว่าง = text)
+func ParseAccessLogFormat(text string) (AccessLogFormat, error) {
+	switch format := AccessLogFormat(strings.ToLower(strings.TrimSpace(text))); format {
+	case "", AccessLogFormatText:
+		return AccessLogFormatText, nil
+	case AccessLogFormatJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("access log format %q: want text or json", text)
+	}
+}
+
+// accessLogFields = ฟิลด์ทั้งหมดของรูปแบบ JSON ตามลำดับที่เขียน
+var accessLogFields = []string{
+	"time", "level", "module", "request_id", "status", "method", "route", "path", "query", "ip", "user_agent",
+	"latency_ms", "bytes_in", "bytes_out", "trace_id", "span_id", "headers", "req", "res",
+}
+
+// ParseAccessLogFields อ่านรายชื่อฟิลด์ของรูปแบบ JSON คั่นด้วย "," เช่น "time,status,route,latency_ms"
+// ว่าง = ทุกฟิลด์; ชื่อที่ไม่รู้จักคืน error
+func ParseAccessLogFields(spec string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !slices.Contains(accessLogFields, field) {
+			return nil, fmt.Errorf("access log field %q: want one of %s", field, strings.Join(accessLogFields, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// selectedFields คืนชุดฟิลด์ที่จะเขียน (ว่าง = ทุกฟิลด์)
+func selectedFields(fields []string) map[string]bool {
+	if len(fields) == 0 {
+		fields = accessLogFields
+	}
+	selected := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		selected[field] = true
+	}
+	return selected
+}
+
+// accessLogEntry = ข้อมูลของ request หนึ่งตัวที่ปิดบังแล้ว รอจัดรูปแบบ
+type accessLogEntry struct {
+	Time         time.Time
+	Module       string
+	Level        string
+	RequestID    string
+	Status       int
+	Method       string
+	Route        string
+	Path         string
+	Query        string
+	IP           string
+	UserAgent    string
+	Latency      time.Duration
+	BytesIn      int64
+	BytesOut     int64
+	TraceID      string
+	SpanID       string
+	Headers      map[string]string
+	RequestBody  string
+	ResponseBody string
+}
+
+// formatTextLine = รูปแบบเดิม: `2025-08-09 05:01:14.533 [books] [info] request_id=... req=... res=...`
+func formatTextLine(entry accessLogEntry) string {
+	traceFields := ""
+	if entry.TraceID != "" {
+		traceFields = fmt.Sprintf(" trace_id=%s span_id=%s", entry.TraceID, entry.SpanID)
+	}
+	headers, _ := json.Marshal(entry.Headers) // map → เรียงตามชื่อ header
+	return fmt.Sprintf(
+		"%s [%s] [%s] request_id=%s status=%d method=%s route=%s ip=%s latency=%s%s headers=%s req=%s res=%s",
+		entry.Time.Format("2006-01-02 15:04:05.000"),
+		entry.Module,
+		entry.Level,
+		entry.RequestID,
+		entry.Status,
+		entry.Method,
+		entry.Route,
+		entry.IP,
+		entry.Latency.String(),
+		traceFields,
+		headers,
+		strings.ReplaceAll(entry.RequestBody, "\n", " "),
+		strings.ReplaceAll(entry.ResponseBody, "\n", " "),
+	)
+}
+
+// formatJSONLine = JSON หนึ่งบรรทัด เฉพาะฟิลด์ที่เลือก; body ที่เป็น JSON เขียนเป็น object ไม่ใช่ string
+func formatJSONLine(entry accessLogEntry, fields map[string]bool) string {
+	values := map[string]any{
+		"time":       entry.Time.UTC().Format(time.RFC3339Nano),
+		"level":      entry.Level,
+		"module":     entry.Module,
+		"request_id": entry.RequestID,
+		"status":     entry.Status,
+		"method":     entry.Method,
+		"route":      entry.Route,
+		"path":       entry.Path,
+		"query":      entry.Query,
+		"ip":         entry.IP,
+		"user_agent": entry.UserAgent,
+		"latency_ms": float64(entry.Latency.Microseconds()) / 1000,
+		"bytes_in":   entry.BytesIn,
+		"bytes_out":  entry.BytesOut,
+		"trace_id":   entry.TraceID,
+		"span_id":    entry.SpanID,
+		"headers":    entry.Headers,
+		"req":        bodyValue(entry.RequestBody),
+		"res":        bodyValue(entry.ResponseBody),
+	}
+
+	var line bytes.Buffer
+	line.WriteByte('{')
+	for _, field := range accessLogFields {
+		value := values[field]
+		if !fields[field] || value == "" || value == nil {
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		if line.Len() > 1 {
+			line.WriteByte(',')
+		}
+		fmt.Fprintf(&line, "%q:", field)
+		line.Write(encoded)
+	}
+	line.WriteByte('}')
+	return line.String()
+}
+
+// bodyValue: body ที่เป็น JSON คืนเป็น RawMessage (บีบให้อยู่บรรทัดเดียว) ไม่งั้นคืน string ตามเดิม
+func bodyValue(body string) any {
+	if body == "" {
+		return nil
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, []byte(body)); err != nil {
+		return body
+	}
+	return json.RawMessage(compacted.Bytes())
+}
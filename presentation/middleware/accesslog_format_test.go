@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleEntry() accessLogEntry {
+	return accessLogEntry{
+		Time:         time.Date(2025, 8, 9, 5, 1, 14, 533_000_000, time.UTC),
+		Module:       "books",
+		Level:        "info",
+		RequestID:    "01J0000000000000000000000",
+		Status:       201,
+		Method:       "POST",
+		Route:        "/api/v1/books",
+		Path:         "/api/v1/books",
+		Query:        "dry_run=true",
+		IP:           "127.0.0.1",
+		UserAgent:    "curl/8.0",
+		Latency:      1500 * time.Microsecond,
+		BytesIn:      27,
+		BytesOut:     12,
+		Headers:      map[string]string{"Content-Type": "application/json"},
+		RequestBody:  "{\n  \"title\": \"a b=c\"\n}",
+		ResponseBody: "created id=1",
+	}
+}
+
+func TestFormatJSONLineTypedFields(t *testing.T) {
+	line := formatJSONLine(sampleEntry(), selectedFields(nil))
+	if strings.Contains(line, "\n") {
+		t.Fatalf("line spans several lines: %q", line)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(line), &decoded); err != nil {
+		t.Fatalf("line is not JSON: %v\n%s", err, line)
+	}
+	if decoded["status"] != float64(201) || decoded["latency_ms"] != 1.5 || decoded["bytes_in"] != float64(27) {
+		t.Fatalf("numeric fields not typed: %s", line)
+	}
+	if body, ok := decoded["req"].(map[string]any); !ok || body["title"] != "a b=c" {
+		t.Fatalf("req = %#v, want the JSON body as an object", decoded["req"])
+	}
+	if decoded["res"] != "created id=1" {
+		t.Fatalf("res = %#v, want the non-JSON body as a string", decoded["res"])
+	}
+	if _, found := decoded["trace_id"]; found {
+		t.Fatalf("empty trace_id should be omitted: %s", line)
+	}
+}
+
+func TestFormatJSONLineSelectedFields(t *testing.T) {
+	fields, err := ParseAccessLogFields("time, status ,route")
+	if err != nil {
+		t.Fatalf("ParseAccessLogFields: %v", err)
+	}
+	got := formatJSONLine(sampleEntry(), selectedFields(fields))
+	want := `{"time":"2025-08-09T05:01:14.533Z","status":201,"route":"/api/v1/books"}`
+	if got != want {
+		t.Fatalf("line = %s, want %s", got, want)
+	}
+
+	if _, err := ParseAccessLogFields("status,password"); err == nil {
+		t.Fatal("unknown field accepted")
+	}
+}
+
+func TestFormatTextLineKeepsLegacyLayout(t *testing.T) {
+	got := formatTextLine(sampleEntry())
+	want := `2025-08-09 05:01:14.533 [books] [info] request_id=01J0000000000000000000000 status=201 method=POST ` +
+		`route=/api/v1/books ip=127.0.0.1 latency=1.5ms headers={"Content-Type":"application/json"} ` +
+		`req={   "title": "a b=c" } res=created id=1`
+	if got != want {
+		t.Fatalf("line =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParseAccessLogFormat(t *testing.T) {
+	for text, want := range map[string]AccessLogFormat{"": AccessLogFormatText, "TEXT": AccessLogFormatText, "json": AccessLogFormatJSON} {
+		if got, err := ParseAccessLogFormat(text); err != nil || got != want {
+			t.Fatalf("ParseAccessLogFormat(%q) = %q, %v; want %q", text, got, err, want)
+		}
+	}
+	if _, err := ParseAccessLogFormat("xml"); err == nil {
+		t.Fatal("ParseAccessLogFormat(xml) succeeded")
+	}
+}
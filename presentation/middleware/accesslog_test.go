@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type memoryWriter struct {
+	mutex sync.Mutex
+	lines []string
+}
+
+func (writer *memoryWriter) WriteLine(_ time.Time, line string) error {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	writer.lines = append(writer.lines, line)
+	return nil
+}
+
+func (writer *memoryWriter) Close() error { return nil }
+
+// entries ถอด JSON Lines ที่เขียนไว้
+func (writer *memoryWriter) entries(t *testing.T) []map[string]any {
+	t.Helper()
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	var entries []map[string]any
+	for _, line := range writer.lines {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("line is not JSON: %v\n%s", err, line)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func newAccessLogEngine(config AccessLogConfig) (*gin.Engine, *memoryWriter) {
+	gin.SetMode(gin.TestMode)
+	writer := &memoryWriter{}
+	config.Writer = writer
+	config.Format = AccessLogFormatJSON
+	engine := gin.New()
+	engine.Use(AccessLog(config))
+	engine.POST("/echo", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusOK, c.GetHeader("Content-Type"), body)
+	})
+	engine.GET("/status/:code", func(c *gin.Context) {
+		code := map[string]int{"ok": http.StatusOK, "missing": http.StatusNotFound}[c.Param("code")]
+		c.JSON(code, gin.H{"code": code})
+	})
+	engine.GET("/metrics", func(c *gin.Context) { c.String(http.StatusOK, "up 1") })
+	engine.GET("/docs/v1/index.html", func(c *gin.Context) { c.String(http.StatusOK, "<html>") })
+	return engine, writer
+}
+
+func serve(engine *gin.Engine, method, target, contentType, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	response := httptest.NewRecorder()
+	engine.ServeHTTP(response, request)
+	return response
+}
+
+func TestAccessLogTruncatesBodiesButHandlerSeesEverything(t *testing.T) {
+	config := DefaultAccessLogConfig()
+	config.MaxRequestBodyBytes, config.MaxResponseBodyBytes = 10, 5
+	engine, writer := newAccessLogEngine(config)
+
+	body := strings.Repeat("a", 30)
+	response := serve(engine, http.MethodPost, "/echo", "text/plain", body)
+	if response.Body.String() != body {
+		t.Fatalf("handler read %d bytes, want the full %d", response.Body.Len(), len(body))
+	}
+
+	entry := writer.entries(t)[0]
+	if entry["req"] != "aaaaaaaaaa...[truncated, 30 bytes total]" {
+		t.Fatalf("req = %v", entry["req"])
+	}
+	if entry["res"] != "aaaaa...[truncated, 30 bytes total]" || entry["bytes_out"] != float64(30) {
+		t.Fatalf("res = %v, bytes_out = %v", entry["res"], entry["bytes_out"])
+	}
+}
+
+func TestAccessLogCapturesAllowedContentTypesOnly(t *testing.T) {
+	engine, writer := newAccessLogEngine(DefaultAccessLogConfig())
+
+	serve(engine, http.MethodPost, "/echo", "application/octet-stream", "\x00\x01\x02")
+	serve(engine, http.MethodPost, "/echo", "application/problem+json; charset=utf-8", `{"title":"x"}`)
+
+	entries := writer.entries(t)
+	if entries[0]["req"] != "[not captured: application/octet-stream, 3 bytes]" {
+		t.Fatalf("binary req = %v", entries[0]["req"])
+	}
+	if body, ok := entries[1]["res"].(map[string]any); !ok || body["title"] != "x" {
+		t.Fatalf("json res = %#v, want it captured", entries[1]["res"])
+	}
+}
+
+func TestAccessLogSkipPaths(t *testing.T) {
+	engine, writer := newAccessLogEngine(DefaultAccessLogConfig())
+
+	serve(engine, http.MethodGet, "/metrics", "", "")
+	serve(engine, http.MethodGet, "/docs/v1/index.html", "", "")
+	serve(engine, http.MethodGet, "/status/ok", "", "")
+
+	entries := writer.entries(t)
+	if len(entries) != 1 || entries[0]["path"] != "/status/ok" {
+		t.Fatalf("entries = %v, want only /status/ok", entries)
+	}
+}
+
+func TestAccessLogSamplesSuccessfulRequestsOnly(t *testing.T) {
+	previous := sampleRandom
+	sampleRandom = func() float64 { return 0.9 }
+	defer func() { sampleRandom = previous }()
+
+	config := DefaultAccessLogConfig()
+	config.SuccessSampleRate = 0.5
+	engine, writer := newAccessLogEngine(config)
+
+	serve(engine, http.MethodGet, "/status/ok", "", "")
+	serve(engine, http.MethodGet, "/status/missing", "", "")
+
+	entries := writer.entries(t)
+	if len(entries) != 1 || entries[0]["status"] != float64(http.StatusNotFound) {
+		t.Fatalf("entries = %v, want only the 404", entries)
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+)
+
+// authRealm = realm ที่แจ้งกลับใน WWW-Authenticate
+const authRealm = "books-api"
+
+// AuthScheme คู่ชื่อ scheme ใน header Authorization (เช่น "Bearer") กับตัวตรวจ credential
+// Header (ถ้ามี) = header ทางเลือกที่ส่ง credential มาตรง ๆ ได้ (เช่น X-API-Key)
+type AuthScheme struct {
+	Name     string
+	Header   string
+	Verifier interfaces.CredentialVerifier
+}
+
+// Authenticate บังคับให้ request มี credential ที่ผ่านการตรวจของ scheme ใด scheme หนึ่ง
+// ผ่าน: ใส่ requestmeta.Principal ลง request context ให้ use case/logger เห็น
+// ไม่ผ่าน: ตอบ 401 พร้อม WWW-Authenticate (RFC 6750)
+func Authenticate(schemes ...AuthScheme) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		schemeName, authorizationCredential, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+		authorizationCredential = strings.TrimSpace(authorizationCredential)
+
+		for _, scheme := range schemes {
+			credential := ""
+			switch {
+			case strings.EqualFold(scheme.Name, schemeName):
+				credential = authorizationCredential
+			case scheme.Header != "":
+				credential = strings.TrimSpace(c.GetHeader(scheme.Header))
+			}
+			if credential == "" {
+				continue
+			}
+			principal, err := scheme.Verifier.Verify(c.Request.Context(), credential)
+			if err != nil {
+				abortUnauthorized(c, schemes, scheme.Name, err.Error())
+				return
+			}
+			c.Request = c.Request.WithContext(requestmeta.WithPrincipal(c.Request.Context(), principal))
+			c.Next()
+			return
+		}
+		abortUnauthorized(c, schemes, "", "")
+	}
+}
+
+// abortUnauthorized ส่ง challenge ของทุก scheme; scheme ที่ credential ไม่ผ่านจะมี error="invalid_token"
+func abortUnauthorized(c *gin.Context, schemes []AuthScheme, failedScheme, description string) {
+	for _, scheme := range schemes {
+		challenge := fmt.Sprintf(`%s realm=%q`, scheme.Name, authRealm)
+		if scheme.Name == failedScheme {
+			challenge += fmt.Sprintf(`, error="invalid_token", error_description=%q`, description)
+		}
+		c.Writer.Header().Add("WWW-Authenticate", challenge)
+	}
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+}
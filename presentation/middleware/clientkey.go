@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+)
+
+// ClientKey ผูกตัวตนของ client เข้ากับ request context (ใช้ทำ read-your-writes ฝั่ง repository)
+// ใช้ X-Client-ID ถ้า client ส่งมา ไม่งั้นใช้ IP (c.ClientIP ตาม SetTrustedProxies)
+func ClientKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientKey := c.GetHeader("X-Client-ID")
+		if clientKey == "" {
+			clientKey = c.ClientIP()
+		}
+		c.Request = c.Request.WithContext(requestmeta.WithClientKey(c.Request.Context(), clientKey))
+		c.Next()
+	}
+}
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+)
+
+const (
+	maxIdempotencyKeyLength     = 255
+	defaultIdempotencyBodyBytes = 1 << 20
+)
+
+// IdempotencyConfig = ที่เก็บ + อายุของคีย์ + เมธอดที่รองรับ
+type IdempotencyConfig struct {
+	Store        interfaces.IdempotencyStore
+	TTL          time.Duration // เก็บคำตอบไว้ส่งซ้ำนานเท่าไร
+	LockTTL      time.Duration // จองคีย์ระหว่าง request แรกทำงาน (กันค้างถ้า instance ตาย)
+	Methods      []string      // ค่าเริ่มต้น POST
+	MaxBodyBytes int64         // body ที่อ่านเข้าหน่วยความจำเพื่อทำ fingerprint ได้มากสุด (ค่าเริ่มต้น 1 MiB; เกิน = 413)
+}
+
+// Idempotency รองรับ header Idempotency-Key (ต้องวางหลัง Authenticate/ResolveTenant)
+//   - ครั้งแรก: ทำงานตามปกติแล้วบันทึกคำตอบ (ยกเว้น 5xx ซึ่งให้ retry ทำใหม่ได้)
+//   - ส่งซ้ำด้วย body เดิม: ตอบคำตอบเดิมพร้อม Idempotent-Replayed: true
+//   - ใช้คีย์เดิมกับ body อื่น: 422; request แรกยังไม่เสร็จ: 409
+//
+// คีย์แยกตามผู้เรียกและ tenant; ไม่มี header = ทำงานตามปกติ
+func Idempotency(config IdempotencyConfig) gin.HandlerFunc {
+	methods := config.Methods
+	if len(methods) == 0 {
+		methods = []string{http.MethodPost}
+	}
+	maxBodyBytes := config.MaxBodyBytes
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = defaultIdempotencyBodyBytes
+	}
+	return func(c *gin.Context) {
+		idempotencyKey := c.GetHeader("Idempotency-Key")
+		if idempotencyKey == "" || !slices.Contains(methods, c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			abortProblem(c, http.StatusBadRequest, "Idempotency-Key is too long")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyBytes))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			abortProblem(c, http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+			return
+		}
+		if err != nil {
+			abortProblem(c, http.StatusBadRequest, "cannot read request body")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		requestContext := c.Request.Context()
+		storeKey := strings.Join([]string{
+			requestmeta.TenantID(requestContext), rateLimitIdentity(c), idempotencyKey,
+		}, "|")
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+
+		existing, reserved, err := config.Store.Reserve(requestContext, storeKey, fingerprint, config.LockTTL)
+		if err != nil {
+			// store ใช้ไม่ได้: ทำงานต่อแบบไม่มี idempotency ดีกว่าตอบ error ทั้งหมด
+			_ = c.Error(fmt.Errorf("idempotency store: %w", err))
+			c.Next()
+			return
+		}
+		if !reserved {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				abortProblem(c, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request")
+			case !existing.Completed:
+				c.Header("Retry-After", "1")
+				abortProblem(c, http.StatusConflict, "a request with this Idempotency-Key is still being processed")
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(existing.Response.Status, existing.Response.ContentType, existing.Response.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		recorder := &idempotencyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// บันทึกด้วย context ที่ไม่ถูกยกเลิกตาม client (client อาจตัดการเชื่อมต่อไปแล้ว)
+		storeContext := context.WithoutCancel(requestContext)
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			_ = config.Store.Release(storeContext, storeKey)
+			return
+		}
+		completeError := config.Store.Complete(storeContext, storeKey, interfaces.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Response: interfaces.IdempotentResponse{
+				Status:      status,
+				ContentType: recorder.Header().Get("Content-Type"),
+				Body:        recorder.body.Bytes(),
+			},
+		}, config.TTL)
+		if completeError != nil {
+			_ = c.Error(fmt.Errorf("idempotency store: %w", completeError))
+		}
+	}
+}
+
+// idempotencyRecorder เก็บ body ของคำตอบทั้งหมดไว้ส่งซ้ำ
+// (responseRecorder ของ access log ตัดตามขีดจำกัดและชนิดเนื้อหา ใช้แทนกันไม่ได้)
+type idempotencyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotencyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// requestFingerprint = sha256 ของ method, path และ body
+func requestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
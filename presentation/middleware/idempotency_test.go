@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/idempotency"
+)
+
+// newIdempotencyEngine = POST /books ที่นับจำนวนครั้งที่ handler ทำงานจริง
+func newIdempotencyEngine(config IdempotencyConfig, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	if config.Store == nil {
+		config.Store = idempotency.NewMemoryStore(systemClock{})
+	}
+	if config.TTL == 0 {
+		config.TTL, config.LockTTL = time.Hour, time.Minute
+	}
+	engine := gin.New()
+	engine.Use(Idempotency(config))
+	engine.POST("/books", handler)
+	return engine
+}
+
+func postWithKey(engine *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/books", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Idempotency-Key", key)
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestIdempotencyReplaysFullResponseBody(t *testing.T) {
+	var calls atomic.Int64
+	// ยาวกว่าขีดจำกัดของ access log และชนิดเนื้อหาที่ access log ไม่เก็บ ต้องส่งซ้ำได้ครบ
+	largeBody := strings.Repeat("x", 64<<10)
+	engine := newIdempotencyEngine(IdempotencyConfig{}, func(c *gin.Context) {
+		calls.Add(1)
+		c.Data(http.StatusCreated, "application/octet-stream", []byte(largeBody))
+	})
+
+	first := postWithKey(engine, "key-1", `{"title":"Go"}`)
+	replayed := postWithKey(engine, "key-1", `{"title":"Go"}`)
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls.Load())
+	}
+	if first.Code != http.StatusCreated || replayed.Code != http.StatusCreated || replayed.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("first %d, replay %d (Idempotent-Replayed %q)", first.Code, replayed.Code, replayed.Header().Get("Idempotent-Replayed"))
+	}
+	if replayed.Body.String() != largeBody || replayed.Header().Get("Content-Type") != "application/octet-stream" {
+		t.Fatalf("replayed %d bytes of %q, want the full original response", replayed.Body.Len(), replayed.Header().Get("Content-Type"))
+	}
+}
+
+func TestIdempotencyRejectsSameKeyWithDifferentBody(t *testing.T) {
+	var calls atomic.Int64
+	engine := newIdempotencyEngine(IdempotencyConfig{}, func(c *gin.Context) {
+		calls.Add(1)
+		c.JSON(http.StatusCreated, gin.H{"id": calls.Load()})
+	})
+
+	if first := postWithKey(engine, "key-1", `{"title":"Go"}`); first.Code != http.StatusCreated {
+		t.Fatalf("first: status %d", first.Code)
+	}
+	if replayed := postWithKey(engine, "key-1", `{"title":"Go"}`); replayed.Code != http.StatusCreated || replayed.Body.String() != `{"id":1}` {
+		t.Fatalf("same body: status %d body %q, want the first response", replayed.Code, replayed.Body)
+	}
+	if mismatch := postWithKey(engine, "key-1", `{"title":"Rust"}`); mismatch.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("different body: status %d, want 422", mismatch.Code)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls.Load())
+	}
+}
+
+func TestIdempotencyConflictsWhileFirstRequestIsInProgress(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	engine := newIdempotencyEngine(IdempotencyConfig{}, func(c *gin.Context) {
+		close(entered)
+		<-release
+		c.Status(http.StatusCreated)
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- postWithKey(engine, "key-1", `{"title":"Go"}`) }()
+	<-entered
+
+	concurrent := postWithKey(engine, "key-1", `{"title":"Go"}`)
+	if concurrent.Code != http.StatusConflict || concurrent.Header().Get("Retry-After") == "" {
+		t.Fatalf("while in progress: status %d (Retry-After %q), want 409", concurrent.Code, concurrent.Header().Get("Retry-After"))
+	}
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Fatalf("first: status %d", first.Code)
+	}
+}
+
+func TestIdempotencyLimitsBufferedBody(t *testing.T) {
+	var calls atomic.Int64
+	engine := newIdempotencyEngine(IdempotencyConfig{MaxBodyBytes: 16}, func(c *gin.Context) {
+		calls.Add(1)
+		c.Status(http.StatusCreated)
+	})
+
+	if tooLarge := postWithKey(engine, "key-1", strings.Repeat("x", 17)); tooLarge.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status %d, want 413", tooLarge.Code)
+	}
+	if fits := postWithKey(engine, "key-2", strings.Repeat("x", 16)); fits.Code != http.StatusCreated {
+		t.Fatalf("body at the limit: status %d", fits.Code)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls.Load())
+	}
+}
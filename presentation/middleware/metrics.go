@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestObserver รับสถิติของแต่ละ request (อแดปเตอร์ metrics เป็นคน implement)
+type RequestObserver interface {
+	ObserveRequest(method, route string, status int, duration time.Duration)
+}
+
+// เมธอดที่ใช้เป็น label ได้ตรง ๆ ที่เหลือรวมเป็น OTHER กัน label บวมจาก request แปลก ๆ
+var knownMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodOptions: true,
+}
+
+// Metrics จับเวลาและ status ของทุก request
+// ติดป้ายด้วย route template (c.FullPath() เช่น /api/v1/books/:id) ไม่ใช่ path จริง; ไม่ตรง route ใดเลย = "unmatched"
+// ต้องอยู่หน้า gin.Recovery() เพื่อให้เห็น 500 จาก panic ด้วย
+func Metrics(observer RequestObserver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		if !knownMethods[method] {
+			method = "OTHER"
+		}
+		observer.ObserveRequest(method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type observedRequest struct {
+	method string
+	route  string
+	status int
+}
+
+// recordingObserver เก็บทุก request ที่ Metrics รายงาน
+type recordingObserver struct {
+	mutex    sync.Mutex
+	requests []observedRequest
+}
+
+func (observer *recordingObserver) ObserveRequest(method, route string, status int, _ time.Duration) {
+	observer.mutex.Lock()
+	defer observer.mutex.Unlock()
+	observer.requests = append(observer.requests, observedRequest{method: method, route: route, status: status})
+}
+
+// label ต้องเป็น route template / unmatched / OTHER ไม่ใช่ path หรือเมธอดจริง (กัน cardinality บวม)
+func TestMetricsLabelsUseRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	observer := &recordingObserver{}
+	engine := gin.New()
+	engine.Use(Metrics(observer))
+	engine.GET("/api/v1/books/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	engine.Handle("PROPFIND", "/api/v1/books/:id", func(c *gin.Context) { c.Status(http.StatusMethodNotAllowed) })
+
+	for _, request := range []struct{ method, target string }{
+		{http.MethodGet, "/api/v1/books/7"},
+		{http.MethodGet, "/api/v1/books/8"},
+		{http.MethodGet, "/wp-login.php"},
+		{"PROPFIND", "/api/v1/books/7"},
+	} {
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(request.method, request.target, nil))
+	}
+
+	want := []observedRequest{
+		{http.MethodGet, "/api/v1/books/:id", http.StatusOK},
+		{http.MethodGet, "/api/v1/books/:id", http.StatusOK},
+		{http.MethodGet, "unmatched", http.StatusNotFound},
+		{"OTHER", "/api/v1/books/:id", http.StatusMethodNotAllowed},
+	}
+	observer.mutex.Lock()
+	defer observer.mutex.Unlock()
+	if len(observer.requests) != len(want) {
+		t.Fatalf("observed %v, want %v", observer.requests, want)
+	}
+	for index := range want {
+		if observer.requests[index] != want[index] {
+			t.Errorf("request %d: observed %+v, want %+v", index, observer.requests[index], want[index])
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+)
+
+// RateLimitRule = ขีดจำกัดเฉพาะเส้นทาง
+// Method เป็น "*" ได้; Path เทียบกับ route ของ gin (เช่น /api/v1/books/:id) ลงท้าย "*" = prefix
+type RateLimitRule struct {
+	Method string
+	Path   string
+	Limit  interfaces.RateLimit
+}
+
+func (rule RateLimitRule) matches(method, route string) bool {
+	if rule.Method != "*" && !strings.EqualFold(rule.Method, method) {
+		return false
+	}
+	if prefix, isPrefix := strings.CutSuffix(rule.Path, "*"); isPrefix {
+		return strings.HasPrefix(route, prefix)
+	}
+	return rule.Path == route
+}
+
+// RateLimitConfig = ที่เก็บ + ขีดจำกัดเริ่มต้น + กฎเฉพาะเส้นทาง (กฎแรกที่ตรงชนะ)
+type RateLimitConfig struct {
+	Store   interfaces.RateLimitStore
+	Default interfaces.RateLimit
+	Rules   []RateLimitRule
+	// PreAuth = ขีดจำกัดต่อ IP ที่ตรวจก่อน Authenticate (ศูนย์ = ไม่ใช้)
+	// กันการเดา credential และ request ที่ตรวจ bcrypt ของ API key ไม่ให้ถูกนับแค่หลังยืนยันตัวตนผ่าน
+	PreAuth interfaces.RateLimit
+}
+
+// RateLimit จำกัดอัตรา request ด้วย token bucket ต่อผู้เรียก (ต้องวางหลัง Authenticate)
+// ผู้เรียก = principal (ผู้ใช้หรือ API key) ถ้ายืนยันตัวตนแล้ว ไม่งั้นใช้ c.ClientIP() (ตาม SetTrustedProxies)
+// ตอบ RateLimit-Limit/Remaining/Reset ทุกครั้ง และ 429 problem+json พร้อม Retry-After เมื่อเกิน
+// ถ้า store ใช้ไม่ได้จะปล่อยผ่าน (ไม่ให้ Redis ล่มแล้ว API ล่มตาม)
+func RateLimit(config RateLimitConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		limit, ruleName := config.Default, "default"
+		for _, rule := range config.Rules {
+			if rule.matches(c.Request.Method, route) {
+				limit, ruleName = rule.Limit, rule.Method+" "+rule.Path
+				break
+			}
+		}
+		if takeRateLimit(c, config.Store, rateLimitIdentity(c)+"|"+ruleName, limit) {
+			c.Next()
+		}
+	}
+}
+
+// PreAuthRateLimit จำกัดอัตราต่อ IP ด้วย config.PreAuth (วางก่อน Authenticate)
+// request ที่ credential ผิดก็ถูกนับ จึงเดาคีย์/token แบบรัว ๆ ไม่ได้
+func PreAuthRateLimit(config RateLimitConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if takeRateLimit(c, config.Store, "ip:"+c.ClientIP()+"|pre-auth", config.PreAuth) {
+			c.Next()
+		}
+	}
+}
+
+// takeRateLimit หัก token ของ key แล้วตั้ง header; คืน false เมื่อเกินขีดจำกัด (ตอบ 429 ไปแล้ว)
+func takeRateLimit(c *gin.Context, store interfaces.RateLimitStore, key string, limit interfaces.RateLimit) bool {
+	if limit.Requests <= 0 || limit.Per <= 0 {
+		return true
+	}
+	decision, err := store.Take(c.Request.Context(), key, limit)
+	if err != nil {
+		_ = c.Error(fmt.Errorf("rate limit store: %w", err))
+		return true
+	}
+
+	header := c.Writer.Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(limit.Capacity()))
+	header.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.ResetAfter)))
+	header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d;burst=%d",
+		limit.Requests, ceilSeconds(limit.Per), limit.Capacity()))
+	if decision.Allowed {
+		return true
+	}
+
+	retryAfter := ceilSeconds(decision.RetryAfter)
+	header.Set("Retry-After", strconv.Itoa(retryAfter))
+	abortProblem(c, http.StatusTooManyRequests, fmt.Sprintf("rate limit exceeded, retry in %d seconds", retryAfter))
+	return false
+}
+
+func rateLimitIdentity(c *gin.Context) string {
+	if principal, ok := requestmeta.PrincipalFrom(c.Request.Context()); ok {
+		return "principal:" + principal.Subject
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
+
+// ParseRateLimit อ่านรูปแบบ "<requests>/<duration>[:<burst>]" เช่น "100/1m" หรือ "10/1s:20"
+func ParseRateLimit(spec string) (interfaces.RateLimit, error) {
+	rate, burstText, hasBurst := strings.Cut(strings.TrimSpace(spec), ":")
+	requestsText, perText, found := strings.Cut(rate, "/")
+	if !found {
+		return interfaces.RateLimit{}, fmt.Errorf("rate limit %q: want <requests>/<duration>[:<burst>]", spec)
+	}
+	requests, err := strconv.Atoi(requestsText)
+	if err != nil || requests <= 0 {
+		return interfaces.RateLimit{}, fmt.Errorf("rate limit %q: invalid request count", spec)
+	}
+	per, err := time.ParseDuration(perText)
+	if err != nil || per <= 0 {
+		return interfaces.RateLimit{}, fmt.Errorf("rate limit %q: invalid duration", spec)
+	}
+	limit := interfaces.RateLimit{Requests: requests, Per: per}
+	if hasBurst {
+		if limit.Burst, err = strconv.Atoi(burstText); err != nil || limit.Burst <= 0 {
+			return interfaces.RateLimit{}, fmt.Errorf("rate limit %q: invalid burst", spec)
+		}
+	}
+	return limit, nil
+}
+
+// ParseRateLimitRules อ่านกฎคั่นด้วย ";" แต่ละกฎคือ "<METHOD> <path>=<rate limit>"
+// เช่น "POST /api/v1/books=10/1m:5; * /api/v2/*=50/1m"
+func ParseRateLimitRules(spec string) ([]RateLimitRule, error) {
+	var rules []RateLimitRule
+	for _, ruleText := range strings.Split(spec, ";") {
+		ruleText = strings.TrimSpace(ruleText)
+		if ruleText == "" {
+			continue
+		}
+		target, limitText, found := strings.Cut(ruleText, "=")
+		method, path, hasPath := strings.Cut(strings.TrimSpace(target), " ")
+		if !found || !hasPath {
+			return nil, fmt.Errorf("rate limit rule %q: want <METHOD> <path>=<rate limit>", ruleText)
+		}
+		limit, err := ParseRateLimit(limitText)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, RateLimitRule{Method: strings.ToUpper(method), Path: strings.TrimSpace(path), Limit: limit})
+	}
+	return rules, nil
+}
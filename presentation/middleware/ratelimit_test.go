@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/ratelimit"
+)
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+type staticVerifier struct{}
+
+func (staticVerifier) Verify(_ context.Context, credential string) (requestmeta.Principal, error) {
+	if credential != "good" {
+		return requestmeta.Principal{}, errors.New("bad credential")
+	}
+	return requestmeta.Principal{Subject: "user"}, nil
+}
+
+// credential ผิดต้องถูกนับด้วย (เดิม RateLimit อยู่หลัง Authenticate ทำให้ 401 ไม่ถูกจำกัดเลย)
+func TestPreAuthRateLimitCountsFailedCredentials(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config := RateLimitConfig{
+		Store:   ratelimit.NewMemoryStore(systemClock{}),
+		Default: interfaces.RateLimit{Requests: 100, Per: time.Minute},
+		PreAuth: interfaces.RateLimit{Requests: 3, Per: time.Minute},
+	}
+	engine := gin.New()
+	engine.Use(PreAuthRateLimit(config), Authenticate(AuthScheme{Name: "Bearer", Verifier: staticVerifier{}}), RateLimit(config))
+	engine.GET("/", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	send := func(credential string) int {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set("Authorization", "Bearer "+credential)
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+	for range 3 {
+		if status := send("guess"); status != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want 401", status)
+		}
+	}
+	if status := send("guess"); status != http.StatusTooManyRequests {
+		t.Fatalf("4th bad credential: status = %d, want 429", status)
+	}
+	if status := send("good"); status != http.StatusTooManyRequests {
+		t.Fatalf("same IP is limited before credentials are checked, got %d", status)
+	}
+}
@@ -0,0 +1,276 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	redactedValue = "[REDACTED]"
+	omittedBody   = "[OMITTED]"
+)
+
+// RedactionConfig = กฎปิดบังข้อมูลลับ/ข้อมูลส่วนบุคคลก่อนเขียน access log
+type RedactionConfig struct {
+	// Headers = ชื่อ header ที่ไม่เขียนค่าจริง (ไม่สนตัวพิมพ์)
+	Headers []string
+	// FieldPaths = ฟิลด์ใน body JSON ที่ปิดบัง รองรับ $.a.b (จาก root), $..a (ทุกระดับ), [*] (ทุกสมาชิกของ array)
+	// ชื่อฟิลด์ไม่สนตัวพิมพ์
+	FieldPaths []string
+	// Patterns = regex ที่ปิดบังในข้อความ body (ใช้กับ body ที่ไม่ใช่ JSON ด้วย)
+	Patterns []string
+	// MaskCardNumbers = ปิดบังตัวเลข 13-19 หลักที่ผ่าน Luhn (เลขบัตร) ไม่กระทบ id/timestamp ทั่วไป
+	MaskCardNumbers bool
+	// SkipBodyRoutes = route ที่ไม่เขียน body เลย รูปแบบ "METHOD /route/template" หรือ "/route/template" (ทุกเมธอด)
+	SkipBodyRoutes []string
+}
+
+// DefaultRedactionConfig = กฎเริ่มต้น (ใช้เมื่อไม่ได้ตั้งค่าเอง)
+func DefaultRedactionConfig() RedactionConfig {
+	return RedactionConfig{
+		Headers: []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-API-Key"},
+		FieldPaths: []string{
+			"$..password", "$..secret", "$..token", "$..access_token", "$..refresh_token", "$..key", "$..email",
+		},
+		MaskCardNumbers: true,
+		SkipBodyRoutes:  []string{"POST /admin/api-keys"},
+	}
+}
+
+// Redactor ใช้กฎที่ตรวจแล้วกับ header และ body ของ access log
+type Redactor struct {
+	headers    map[string]bool
+	fieldPaths [][]pathStep
+	// jsonFieldPattern / formFieldPattern = ชื่อฟิลด์สุดท้ายของ FieldPaths ใช้กับ body ที่ parse ไม่ได้
+	// (ถูกตัดเพราะเกินขนาด หรือเป็น form) และ query string
+	jsonFieldPattern *regexp.Regexp
+	formFieldPattern *regexp.Regexp
+	patterns         []*regexp.Regexp
+	maskCards        bool
+	skipBodyRoutes   map[string]bool
+}
+
+// pathStep = หนึ่งขั้นของ field path: ชื่อฟิลด์ (recursive = ค้นทุกระดับ) หรือ [*]
+type pathStep struct {
+	key       string
+	recursive bool
+	anyIndex  bool
+}
+
+// NewRedactor ตรวจ field path และ regex ให้ถูกต้องก่อนใช้งาน
+func NewRedactor(config RedactionConfig) (*Redactor, error) {
+	redactor := &Redactor{
+		headers:        make(map[string]bool, len(config.Headers)),
+		maskCards:      config.MaskCardNumbers,
+		skipBodyRoutes: make(map[string]bool, len(config.SkipBodyRoutes)),
+	}
+	for _, header := range config.Headers {
+		redactor.headers[http.CanonicalHeaderKey(strings.TrimSpace(header))] = true
+	}
+	var fieldNames []string
+	for _, fieldPath := range config.FieldPaths {
+		steps, err := parseFieldPath(fieldPath)
+		if err != nil {
+			return nil, err
+		}
+		redactor.fieldPaths = append(redactor.fieldPaths, steps)
+		if last := steps[len(steps)-1]; !last.anyIndex {
+			fieldNames = append(fieldNames, regexp.QuoteMeta(last.key))
+		}
+	}
+	if len(fieldNames) > 0 {
+		names := strings.Join(fieldNames, "|")
+		redactor.jsonFieldPattern = regexp.MustCompile(`(?i)("(?:` + names + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]*)`)
+		redactor.formFieldPattern = regexp.MustCompile(`(?i)((?:^|[&;])(?:` + names + `)=)[^&;]*`)
+	}
+	for _, pattern := range config.Patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redaction pattern %q: %w", pattern, err)
+		}
+		redactor.patterns = append(redactor.patterns, compiled)
+	}
+	for _, route := range config.SkipBodyRoutes {
+		method, path, found := strings.Cut(strings.TrimSpace(route), " ")
+		if !found {
+			method, path = "*", method
+		}
+		redactor.skipBodyRoutes[strings.ToUpper(method)+" "+strings.TrimSpace(path)] = true
+	}
+	return redactor, nil
+}
+
+// parseFieldPath แปลง "$.user.password" / "$..email" / "$.items[*].card" เป็นลำดับขั้น
+func parseFieldPath(fieldPath string) ([]pathStep, error) {
+	rest, found := strings.CutPrefix(strings.TrimSpace(fieldPath), "$")
+	if !found || rest == "" {
+		return nil, fmt.Errorf("redaction field path %q must start with $. or $..", fieldPath)
+	}
+	var steps []pathStep
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "[*]"):
+			steps = append(steps, pathStep{anyIndex: true})
+			rest = rest[len("[*]"):]
+		case strings.HasPrefix(rest, "."):
+			recursive := strings.HasPrefix(rest, "..")
+			rest = strings.TrimLeft(rest, ".")
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("redaction field path %q has an empty field name", fieldPath)
+			}
+			steps = append(steps, pathStep{key: rest[:end], recursive: recursive})
+			rest = rest[end:]
+		default:
+			return nil, fmt.Errorf("redaction field path %q: unexpected %q", fieldPath, rest)
+		}
+	}
+	return steps, nil
+}
+
+// Headers คืน header ของ request เป็น map (หลายค่ารวมด้วย ", ") โดยปิดบังตัวที่อยู่ใน deny-list
+func (redactor *Redactor) Headers(header http.Header) map[string]string {
+	flattened := make(map[string]string, len(header))
+	for name, values := range header {
+		if redactor.headers[http.CanonicalHeaderKey(name)] {
+			flattened[name] = redactedValue
+			continue
+		}
+		flattened[name] = strings.Join(values, ", ")
+	}
+	return flattened
+}
+
+// SkipsBody = route นี้ถูกตั้งไม่ให้เขียน body
+func (redactor *Redactor) SkipsBody(method, route string) bool {
+	return redactor.skipBodyRoutes[strings.ToUpper(method)+" "+route] || redactor.skipBodyRoutes["* "+route]
+}
+
+// Body ปิดบัง body ตามกฎ: route ที่ opt-out → [OMITTED], JSON → ปิดบังตาม field path, แล้วจึงใช้ regex/เลขบัตร
+func (redactor *Redactor) Body(method, route, body string) string {
+	if body == "" {
+		return body
+	}
+	if redactor.SkipsBody(method, route) {
+		return omittedBody
+	}
+	if len(redactor.fieldPaths) > 0 {
+		body = redactor.maskJSONFields(body)
+	}
+	return redactor.maskPatterns(body)
+}
+
+// maskFieldNames ปิดค่าตามชื่อฟิลด์แบบข้อความ: "password": "..." (JSON ที่ถูกตัด) และ password=... (form)
+func (redactor *Redactor) maskFieldNames(text string) string {
+	if redactor.jsonFieldPattern == nil {
+		return text
+	}
+	text = redactor.jsonFieldPattern.ReplaceAllString(text, `${1}"`+redactedValue+`"`)
+	return redactor.formFieldPattern.ReplaceAllString(text, `${1}`+redactedValue)
+}
+
+// Text ปิดค่าตามชื่อฟิลด์แบบ form, regex และเลขบัตร ในข้อความทั่วไป (เช่น query string)
+func (redactor *Redactor) Text(text string) string {
+	return redactor.maskPatterns(redactor.maskFieldNames(text))
+}
+
+// maskPatterns ใช้ regex ที่ตั้งไว้และการปิดเลขบัตร
+func (redactor *Redactor) maskPatterns(text string) string {
+	for _, pattern := range redactor.patterns {
+		text = pattern.ReplaceAllString(text, redactedValue)
+	}
+	if redactor.maskCards {
+		text = maskCardNumbers(text)
+	}
+	return text
+}
+
+// maskJSONFields ใช้ field path กับ body ที่เป็น JSON; body ที่ parse ไม่ได้ (form หรือถูกตัดกลางคัน) ปิดตามชื่อฟิลด์แทน
+func (redactor *Redactor) maskJSONFields(body string) string {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber() // ไม่ให้ตัวเลขใหญ่ถูกแปลงเป็น float
+	var document any
+	if err := decoder.Decode(&document); err != nil || decoder.More() {
+		return redactor.maskFieldNames(body)
+	}
+	for _, steps := range redactor.fieldPaths {
+		document = maskPath(document, steps)
+	}
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(document); err != nil {
+		return body
+	}
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+func maskPath(value any, steps []pathStep) any {
+	if len(steps) == 0 {
+		return redactedValue
+	}
+	step := steps[0]
+	switch typed := value.(type) {
+	case map[string]any:
+		if step.anyIndex {
+			return value
+		}
+		for key, child := range typed {
+			switch {
+			case strings.EqualFold(key, step.key):
+				typed[key] = maskPath(child, steps[1:])
+			case step.recursive:
+				typed[key] = maskPath(child, steps)
+			}
+		}
+	case []any:
+		for index, child := range typed {
+			if step.anyIndex {
+				typed[index] = maskPath(child, steps[1:])
+			} else if step.recursive {
+				typed[index] = maskPath(child, steps)
+			}
+		}
+	}
+	return value
+}
+
+// เลข 13-19 หลัก คั่นด้วยช่องว่างหรือขีดได้
+var cardNumberPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+
+func maskCardNumbers(text string) string {
+	return cardNumberPattern.ReplaceAllStringFunc(text, func(candidate string) string {
+		if passesLuhn(candidate) {
+			return redactedValue
+		}
+		return candidate
+	})
+}
+
+// passesLuhn ตรวจ checksum ของเลขบัตร (ข้ามช่องว่าง/ขีด)
+func passesLuhn(candidate string) bool {
+	sum, digits := 0, 0
+	for index := len(candidate) - 1; index >= 0; index-- {
+		character := candidate[index]
+		if character < '0' || character > '9' {
+			continue
+		}
+		digit := int(character - '0')
+		if digits%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
@@ -0,0 +1,135 @@
+package middleware_test
+
+import (
+	"maps"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/presentation/middleware"
+)
+
+func newRedactor(t *testing.T, config middleware.RedactionConfig) *middleware.Redactor {
+	t.Helper()
+	redactor, err := middleware.NewRedactor(config)
+	if err != nil {
+		t.Fatalf("NewRedactor: %v", err)
+	}
+	return redactor
+}
+
+func TestRedactorBodyFieldPaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		body  string
+		want  string
+	}{
+		{
+			name:  "root field",
+			paths: []string{"$.password"},
+			body:  `{"username":"alice","password":"hunter2","nested":{"password":"kept"}}`,
+			want:  `{"nested":{"password":"kept"},"password":"[REDACTED]","username":"alice"}`,
+		},
+		{
+			name:  "recursive field in arrays, case-insensitive",
+			paths: []string{"$..email"},
+			body:  `{"users":[{"Email":"a@example.com"},{"email":"b@example.com","id":1}]}`,
+			want:  `{"users":[{"Email":"[REDACTED]"},{"email":"[REDACTED]","id":1}]}`,
+		},
+		{
+			name:  "array wildcard",
+			paths: []string{"$.cards[*].cvv"},
+			body:  `{"cards":[{"cvv":"123","last4":"4242"}]}`,
+			want:  `{"cards":[{"cvv":"[REDACTED]","last4":"4242"}]}`,
+		},
+		{
+			name:  "large numbers survive re-encoding",
+			paths: []string{"$.secret"},
+			body:  `{"id":12345678901234567890,"secret":{"a":1}}`,
+			want:  `{"id":12345678901234567890,"secret":"[REDACTED]"}`,
+		},
+		{
+			name:  "form body masked by field name",
+			paths: []string{"$..password"},
+			body:  `user=alice&Password=hunter2&remember=1`,
+			want:  `user=alice&Password=[REDACTED]&remember=1`,
+		},
+		{
+			name:  "truncated JSON masked by field name",
+			paths: []string{"$..password", "$..token"},
+			body:  `{"password": "hunter2", "token": 12345, "note": "x", "nested": {"token": "abcd...[truncated, 900 bytes total]`,
+			want:  `{"password": "[REDACTED]", "token": "[REDACTED]", "note": "x", "nested": {"token": "[REDACTED]"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			redactor := newRedactor(t, middleware.RedactionConfig{FieldPaths: test.paths})
+			if got := redactor.Body("POST", "/api/v1/books", test.body); got != test.want {
+				t.Fatalf("Body() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRedactorBodyPatternsAndCards(t *testing.T) {
+	redactor := newRedactor(t, middleware.RedactionConfig{
+		Patterns:        []string{`Bearer [A-Za-z0-9._-]+`},
+		MaskCardNumbers: true,
+	})
+
+	got := redactor.Body("POST", "/pay", `card 4111 1111 1111 1111 at 1712345678901 with Bearer abc.def`)
+	want := `card [REDACTED] at 1712345678901 with [REDACTED]`
+	if got != want {
+		t.Fatalf("Body() = %q, want %q", got, want)
+	}
+}
+
+func TestRedactorSkipBodyRoutes(t *testing.T) {
+	redactor := newRedactor(t, middleware.RedactionConfig{
+		SkipBodyRoutes: []string{"POST /admin/api-keys", "/login"},
+	})
+
+	if got := redactor.Body("POST", "/admin/api-keys", `{"key":"secret"}`); got != "[OMITTED]" {
+		t.Fatalf("POST /admin/api-keys = %q, want [OMITTED]", got)
+	}
+	if got := redactor.Body("GET", "/admin/api-keys", `[]`); got != "[]" {
+		t.Fatalf("GET /admin/api-keys = %q, want body kept", got)
+	}
+	if got := redactor.Body("PUT", "/login", `{}`); got != "[OMITTED]" {
+		t.Fatalf("PUT /login = %q, want [OMITTED] for any method", got)
+	}
+}
+
+func TestRedactorHeaders(t *testing.T) {
+	redactor := newRedactor(t, middleware.DefaultRedactionConfig())
+	header := http.Header{}
+	header.Set("Authorization", "Bearer abc")
+	header.Set("X-Api-Key", "key_123")
+	header.Add("Accept", "application/json")
+	header.Add("Accept", "text/plain")
+
+	got := redactor.Headers(header)
+	want := map[string]string{
+		"Accept":        "application/json, text/plain",
+		"Authorization": "[REDACTED]",
+		"X-Api-Key":     "[REDACTED]",
+	}
+	if !maps.Equal(got, want) {
+		t.Fatalf("Headers() = %v, want %v", got, want)
+	}
+}
+
+func TestNewRedactorRejectsInvalidRules(t *testing.T) {
+	for _, config := range []middleware.RedactionConfig{
+		{FieldPaths: []string{"password"}},
+		{FieldPaths: []string{"$.a..[x]"}},
+		{Patterns: []string{"("}},
+	} {
+		if _, err := middleware.NewRedactor(config); err == nil {
+			t.Fatalf("NewRedactor(%+v) succeeded, want error", config)
+		} else if !strings.Contains(err.Error(), "redaction") {
+			t.Fatalf("error = %v, want it to mention redaction", err)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oklog/ulid/v2"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+)
+
+// RequestIDHeader = header ที่รับ/ตอบ request id
+const RequestIDHeader = "X-Request-ID"
+
+// id ที่รับจาก client ต้องสั้นและไม่มีช่องว่าง/อักขระควบคุม (กันปลอมบรรทัดใน log)
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID ใช้ X-Request-ID ที่ client/gateway ส่งมา (ถ้ารูปแบบถูก) ไม่งั้นสร้าง ULID ใหม่
+// ผูกไว้ใน context (requestmeta.RequestID) และตอบกลับใน header เดียวกัน
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = ulid.Make().String()
+		}
+		c.Request = c.Request.WithContext(requestmeta.WithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
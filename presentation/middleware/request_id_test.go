@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/oklog/ulid/v2"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+)
+
+// newRequestIDEngine = RequestID → AccessLog (ลำดับเดียวกับ router) และ handler ที่ตอบ id จาก context
+func newRequestIDEngine() (*gin.Engine, *memoryWriter) {
+	gin.SetMode(gin.TestMode)
+	writer := &memoryWriter{}
+	config := DefaultAccessLogConfig()
+	config.Writer = writer
+	config.Format = AccessLogFormatJSON
+	engine := gin.New()
+	engine.Use(RequestID(), AccessLog(config))
+	engine.GET("/id", func(c *gin.Context) {
+		c.String(http.StatusOK, requestmeta.RequestID(c.Request.Context()))
+	})
+	return engine, writer
+}
+
+func serveWithRequestID(engine *gin.Engine, requestID string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/id", nil)
+	if requestID != "" {
+		request.Header.Set(RequestIDHeader, requestID)
+	}
+	response := httptest.NewRecorder()
+	engine.ServeHTTP(response, request)
+	return response
+}
+
+// id เดียวกันต้องไปถึง header ของคำตอบ, requestmeta.RequestID และบรรทัด access log
+func assertRequestIDPropagated(t *testing.T, response *httptest.ResponseRecorder, writer *memoryWriter, want string) {
+	t.Helper()
+	if got := response.Header().Get(RequestIDHeader); got != want {
+		t.Errorf("response header = %q, want %q", got, want)
+	}
+	if got := response.Body.String(); got != want {
+		t.Errorf("requestmeta.RequestID = %q, want %q", got, want)
+	}
+	entries := writer.entries(t)
+	if len(entries) != 1 || entries[0]["request_id"] != want {
+		t.Errorf("access log entries = %v, want request_id %q", entries, want)
+	}
+}
+
+func TestRequestIDEchoesValidIncomingID(t *testing.T) {
+	engine, writer := newRequestIDEngine()
+	const incoming = "gateway-01.trace:42_x"
+
+	response := serveWithRequestID(engine, incoming)
+	assertRequestIDPropagated(t, response, writer, incoming)
+}
+
+func TestRequestIDReplacesInvalidID(t *testing.T) {
+	for name, incoming := range map[string]string{
+		"missing":  "",
+		"space":    "abc def",
+		"newline":  "abc\nstatus=200 forged=1",
+		"too long": strings.Repeat("a", 129),
+		"quote":    `abc"def`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			engine, writer := newRequestIDEngine()
+
+			response := serveWithRequestID(engine, incoming)
+			generated := response.Header().Get(RequestIDHeader)
+			if _, err := ulid.ParseStrict(generated); err != nil {
+				t.Fatalf("replacement id %q is not a ULID: %v", generated, err)
+			}
+			assertRequestIDPropagated(t, response, writer, generated)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+)
+
+// tenantIDPattern จำกัดรูปแบบ tenant id (ใช้เป็นค่าใน query และ key ของแคช)
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// TenantConfig = แหล่งที่ใช้หา tenant ของ request
+type TenantConfig struct {
+	Header     string // เช่น X-Tenant-ID (ว่าง = ไม่ใช้ header)
+	BaseDomain string // เช่น books.example.com → acme.books.example.com คือ tenant "acme" (ว่าง = ไม่ใช้ subdomain)
+	Required   bool   // true = request ที่หา tenant ไม่ได้ตอบ 400 แทนการใช้ requestmeta.DefaultTenant
+}
+
+// ResolveTenant หา tenant แล้วผูกไว้กับ request context (ต้องวางหลัง Authenticate ถ้าเปิด auth)
+// เปิด auth: tenant มาจาก credential เท่านั้น (claim ของ JWT / tenant ของ API key)
+// credential ที่ไม่ผูก tenant ตอบ 403 และ header/subdomain ที่ขอ tenant อื่นตอบ 403 (กันสลับไปดู tenant อื่น)
+// ไม่เปิด auth: เลือก tenant ด้วย header → subdomain
+func ResolveTenant(config TenantConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requested := ""
+		if config.Header != "" {
+			requested = strings.ToLower(strings.TrimSpace(c.GetHeader(config.Header)))
+		}
+		if requested == "" && config.BaseDomain != "" {
+			requested = subdomainOf(c.Request.Host, config.BaseDomain)
+		}
+
+		tenantID := requested
+		if principal, ok := requestmeta.PrincipalFrom(c.Request.Context()); ok {
+			if principal.TenantID == "" {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "credential has no tenant"})
+				return
+			}
+			if requested != "" && requested != principal.TenantID {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "tenant mismatch"})
+				return
+			}
+			tenantID = principal.TenantID
+		}
+
+		switch {
+		case tenantID == "" && config.Required:
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "tenant is required"})
+			return
+		case tenantID == "":
+			tenantID = requestmeta.DefaultTenant
+		case !tenantIDPattern.MatchString(tenantID):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid tenant"})
+			return
+		}
+		c.Request = c.Request.WithContext(requestmeta.WithTenant(c.Request.Context(), tenantID))
+		c.Next()
+	}
+}
+
+// subdomainOf คืน label แรกหน้า baseDomain เช่น ("acme.books.example.com:8080", "books.example.com") → "acme"
+func subdomainOf(host, baseDomain string) string {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	host = strings.ToLower(host)
+	label, found := strings.CutSuffix(host, "."+strings.ToLower(baseDomain))
+	if !found || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
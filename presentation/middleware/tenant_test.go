@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/requestmeta"
+)
+
+// newTenantEngine ใส่ principal (ถ้ามี) แทน Authenticate แล้วตอบ tenant ที่ได้
+func newTenantEngine(principal *requestmeta.Principal) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	if principal != nil {
+		engine.Use(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(requestmeta.WithPrincipal(c.Request.Context(), *principal))
+		})
+	}
+	engine.Use(ResolveTenant(TenantConfig{Header: "X-Tenant-ID", BaseDomain: "books.example.com"}))
+	engine.GET("/", func(c *gin.Context) { c.String(http.StatusOK, requestmeta.TenantID(c.Request.Context())) })
+	return engine
+}
+
+func TestResolveTenant(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		principal  *requestmeta.Principal
+		host       string
+		header     string
+		wantStatus int
+		wantTenant string
+	}{
+		{name: "anonymous header", header: "acme", wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "anonymous subdomain", host: "globex.books.example.com", wantStatus: http.StatusOK, wantTenant: "globex"},
+		{name: "anonymous default", wantStatus: http.StatusOK, wantTenant: requestmeta.DefaultTenant},
+		{name: "credential tenant", principal: &requestmeta.Principal{TenantID: "acme"}, wantStatus: http.StatusOK, wantTenant: "acme"},
+		{name: "credential mismatch", principal: &requestmeta.Principal{TenantID: "acme"}, header: "globex", wantStatus: http.StatusForbidden},
+		{name: "credential without tenant picks header", principal: &requestmeta.Principal{Subject: "u"}, header: "globex", wantStatus: http.StatusForbidden},
+		{name: "credential without tenant", principal: &requestmeta.Principal{Subject: "u"}, wantStatus: http.StatusForbidden},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.host != "" {
+				request.Host = test.host
+			}
+			if test.header != "" {
+				request.Header.Set("X-Tenant-ID", test.header)
+			}
+			recorder := httptest.NewRecorder()
+			newTenantEngine(test.principal).ServeHTTP(recorder, request)
+			if recorder.Code != test.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", recorder.Code, test.wantStatus, recorder.Body)
+			}
+			if test.wantStatus == http.StatusOK && recorder.Body.String() != test.wantTenant {
+				t.Fatalf("tenant = %q, want %q", recorder.Body, test.wantTenant)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing เปิด span ระดับ server ให้ทุก request
+// อ่าน traceparent ขาเข้า (ต่อ trace เดิมของผู้เรียก) และใส่ traceparent ของ span นี้กลับใน response
+// span ชื่อ "METHOD /route/template" ตาม c.FullPath() ไม่ใช้ path จริงกันชื่อบวม
+func Tracing(provider trace.TracerProvider, propagator propagation.TextMapPropagator) gin.HandlerFunc {
+	tracer := provider.Tracer("github.com/nuba55yo/go-101-CleanCRUD/presentation/middleware")
+	return func(c *gin.Context) {
+		requestContext := propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		spanName := c.Request.Method
+		if route != "" {
+			spanName += " " + route
+		}
+		requestContext, span := tracer.Start(requestContext, spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", c.Request.URL.Path),
+				attribute.String("client.address", c.ClientIP()),
+				attribute.String("user_agent.original", c.Request.UserAgent()),
+			))
+		defer span.End()
+
+		c.Request = c.Request.WithContext(requestContext)
+		propagator.Inject(requestContext, propagation.HeaderCarrier(c.Writer.Header()))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTracingEngine() (*gin.Engine, *tracetest.SpanRecorder) {
+	gin.SetMode(gin.TestMode)
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	engine := gin.New()
+	engine.Use(Tracing(provider, propagation.TraceContext{}))
+	engine.GET("/api/v1/books/:id", func(c *gin.Context) {
+		// handler ต้องเห็น span ของ request ใน context (use case / query ต่อ trace เดียวกัน)
+		c.String(http.StatusOK, trace.SpanContextFromContext(c.Request.Context()).SpanID().String())
+	})
+	return engine, recorder
+}
+
+// traceparent ขาเข้าต้องเป็น parent ของ span ฝั่ง server และ response ต้องมี traceparent ของ span นั้น
+func TestTracingContinuesIncomingTraceparent(t *testing.T) {
+	engine, recorder := newTracingEngine()
+	const incomingTraceID, incomingSpanID = "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/books/7", nil)
+	request.Header.Set("traceparent", "00-"+incomingTraceID+"-"+incomingSpanID+"-01")
+	response := httptest.NewRecorder()
+	engine.ServeHTTP(response, request)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET /api/v1/books/:id" || span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("span = %q (%v), want server span named after the route template", span.Name(), span.SpanKind())
+	}
+	if span.SpanContext().TraceID().String() != incomingTraceID || span.Parent().SpanID().String() != incomingSpanID {
+		t.Errorf("span trace/parent = %s/%s, want %s/%s",
+			span.SpanContext().TraceID(), span.Parent().SpanID(), incomingTraceID, incomingSpanID)
+	}
+
+	spanID := span.SpanContext().SpanID().String()
+	if got, want := response.Header().Get("traceparent"), "00-"+incomingTraceID+"-"+spanID+"-01"; got != want {
+		t.Errorf("response traceparent = %q, want %q", got, want)
+	}
+	if response.Body.String() != spanID {
+		t.Errorf("handler saw span %q, want %q", response.Body.String(), spanID)
+	}
+}
+
+// ไม่มี traceparent = เริ่ม trace ใหม่ และยังตอบ traceparent ให้ผู้เรียกเอาไปค้นได้
+func TestTracingStartsNewTraceWithoutTraceparent(t *testing.T) {
+	engine, recorder := newTracingEngine()
+
+	response := httptest.NewRecorder()
+	engine.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/api/v1/books/7", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Parent().IsValid() {
+		t.Fatalf("spans = %v, want one root span", spans)
+	}
+	traceID := spans[0].SpanContext().TraceID().String()
+	spanID := spans[0].SpanContext().SpanID().String()
+	if got, want := response.Header().Get("traceparent"), "00-"+traceID+"-"+spanID+"-01"; got != want {
+		t.Errorf("response traceparent = %q, want %q", got, want)
+	}
+}
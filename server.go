@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+	"github.com/nuba55yo/go-101-CleanCRUD/infrastructure/config"
+)
+
+// serverConfig = ค่าของ http.Server และขั้นตอนปิดแบบ graceful
+type serverConfig struct {
+	Address           string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	// DrainDelay = เวลารอหลังตั้ง /readyz เป็น 503 ก่อนหยุดรับ connection (ให้ load balancer ถอดเครื่องออกก่อน)
+	DrainDelay time.Duration
+	// ShutdownTimeout = เวลาสูงสุดที่รอ request ค้างให้เสร็จ เกินนี้ตัด connection ทิ้ง
+	ShutdownTimeout time.Duration
+}
+
+// serverConfigFromEnv อ่านค่า server จาก PORT, HTTP_* และ SHUTDOWN_*
+func serverConfigFromEnv() serverConfig {
+	return serverConfig{
+		Address:           ":" + config.String("PORT", "8080"),
+		ReadTimeout:       config.Duration("HTTP_READ_TIMEOUT", 15*time.Second),
+		ReadHeaderTimeout: config.Duration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		WriteTimeout:      config.Duration("HTTP_WRITE_TIMEOUT", 30*time.Second),
+		IdleTimeout:       config.Duration("HTTP_IDLE_TIMEOUT", 60*time.Second),
+		MaxHeaderBytes:    config.Int("HTTP_MAX_HEADER_BYTES", 1<<20),
+		DrainDelay:        config.Duration("SHUTDOWN_DRAIN_DELAY", 0),
+		ShutdownTimeout:   config.Duration("SHUTDOWN_TIMEOUT", 20*time.Second),
+	}
+}
+
+func newHTTPServer(handler http.Handler, serverSettings serverConfig) *http.Server {
+	return &http.Server{
+		Addr:              serverSettings.Address,
+		Handler:           handler,
+		ReadTimeout:       serverSettings.ReadTimeout,
+		ReadHeaderTimeout: serverSettings.ReadHeaderTimeout,
+		WriteTimeout:      serverSettings.WriteTimeout,
+		IdleTimeout:       serverSettings.IdleTimeout,
+		MaxHeaderBytes:    serverSettings.MaxHeaderBytes,
+	}
+}
+
+// serveUntilSignal รับ request จาก listener ที่ bind ไว้แล้วจนกว่า signalContext ถูกยกเลิก (SIGINT/SIGTERM)
+// แล้วปิดแบบ graceful: ตั้ง ready เป็น false → รอ DrainDelay → Shutdown รอ request ค้างไม่เกิน ShutdownTimeout
+// คืน nil เมื่อปิดเรียบร้อย; error เมื่อ Serve ล้มหรือ request ค้างไม่เสร็จทันเวลา
+// (bind ก่อนเรียก: port ชนจะล้มตั้งแต่ตอนเปิด และ ready เป็น true ได้ก็ต่อเมื่อรับ connection ได้จริง)
+func serveUntilSignal(signalContext context.Context, server *http.Server, listener net.Listener,
+	serverSettings serverConfig, ready *atomic.Bool, logger interfaces.Logger) error {
+	serveErrors := make(chan error, 1)
+	go func() { serveErrors <- server.Serve(listener) }()
+	ready.Store(true)
+	logger.Info(context.Background(), "server started", "address", listener.Addr().String(), "pid", os.Getpid())
+
+	select {
+	case err := <-serveErrors:
+		ready.Store(false)
+		return err
+	case <-signalContext.Done():
+	}
+
+	ready.Store(false)
+	logger.Info(context.Background(), "shutdown started",
+		"drain_delay", serverSettings.DrainDelay.String(), "timeout", serverSettings.ShutdownTimeout.String())
+	if serverSettings.DrainDelay > 0 {
+		time.Sleep(serverSettings.DrainDelay)
+	}
+
+	shutdownContext, cancel := context.WithTimeout(context.Background(), serverSettings.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownContext); err != nil {
+		// หมดเวลา: ตัด connection ที่เหลือทิ้ง
+		_ = server.Close()
+		return err
+	}
+	if err := <-serveErrors; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	logger.Info(context.Background(), "server stopped")
+	return nil
+}
+
+// serveInBackground รัน server เสริม (เช่น /metrics แยกพอร์ต) คืนฟังก์ชันปิดที่รอ request ค้างไม่เกิน ShutdownTimeout
+func serveInBackground(server *http.Server, serverSettings serverConfig, logger interfaces.Logger) (stop func()) {
+	go func() {
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			logger.Error(context.Background(), "background server failed", "address", serverSettings.Address, "error", err)
+		}
+	}()
+	return func() {
+		shutdownContext, cancel := context.WithTimeout(context.Background(), serverSettings.ShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownContext); err != nil {
+			_ = server.Close()
+		}
+	}
+}
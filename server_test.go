@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nuba55yo/go-101-CleanCRUD/application/interfaces"
+)
+
+type discardLogger struct{}
+
+func (discardLogger) Debug(context.Context, string, ...any) {}
+func (discardLogger) Info(context.Context, string, ...any)  {}
+func (discardLogger) Warn(context.Context, string, ...any)  {}
+func (discardLogger) Error(context.Context, string, ...any) {}
+func (logger discardLogger) With(...any) interfaces.Logger  { return logger }
+
+// slowServer เปิด server ที่ handler ค้างจนกว่าจะปิด release แล้วคืนช่องทางที่ใช้ควบคุม
+func slowServer(t *testing.T, serverSettings serverConfig) (
+	address string, ready *atomic.Bool, stop context.CancelFunc, started, release chan struct{}, result chan error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	started, release = make(chan struct{}, 1), make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		_, _ = io.WriteString(w, "done")
+	})
+	ready = &atomic.Bool{}
+	signalContext, stop := context.WithCancel(context.Background())
+	result = make(chan error, 1)
+	go func() {
+		result <- serveUntilSignal(signalContext, newHTTPServer(handler, serverSettings), listener, serverSettings, ready, discardLogger{})
+	}()
+	waitFor(t, ready.Load)
+	return listener.Addr().String(), ready, stop, started, release, result
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// สัญญาณระหว่างมี request ค้าง: readiness ต้องเป็น false ทันที และ request นั้นยังได้คำตอบครบ
+func TestServeUntilSignalDrainsInFlightRequests(t *testing.T) {
+	address, ready, stop, started, release, result := slowServer(t, serverConfig{ShutdownTimeout: 5 * time.Second})
+
+	responses := make(chan string, 1)
+	go func() {
+		response, err := http.Get("http://" + address + "/slow")
+		if err != nil {
+			responses <- err.Error()
+			return
+		}
+		defer response.Body.Close()
+		body, _ := io.ReadAll(response.Body)
+		responses <- string(body)
+	}()
+	<-started
+
+	stop()
+	waitFor(t, func() bool { return !ready.Load() })
+	close(release)
+
+	if body := <-responses; body != "done" {
+		t.Fatalf("in-flight request got %q, want it to finish", body)
+	}
+	if err := <-result; err != nil {
+		t.Fatalf("serveUntilSignal = %v, want nil after a clean drain", err)
+	}
+}
+
+// request ที่ค้างเกิน ShutdownTimeout: ต้องตัดทิ้งแล้วคืน error ไม่รอไปเรื่อย ๆ
+func TestServeUntilSignalReturnsErrorWhenShutdownTimesOut(t *testing.T) {
+	address, _, stop, started, release, result := slowServer(t, serverConfig{ShutdownTimeout: 100 * time.Millisecond})
+	defer close(release)
+
+	go func() {
+		if response, err := http.Get("http://" + address + "/stuck"); err == nil {
+			response.Body.Close()
+		}
+	}()
+	<-started
+
+	stop()
+	select {
+	case err := <-result:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("serveUntilSignal = %v, want context.DeadlineExceeded", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("serveUntilSignal did not give up after ShutdownTimeout")
+	}
+}